@@ -20,6 +20,7 @@ import (
 	"context"
 	"io"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -30,6 +31,7 @@ import (
 	"github.com/milvus-io/milvus/internal/allocator"
 	"github.com/milvus-io/milvus/internal/flushcommon/metacache"
 	"github.com/milvus-io/milvus/internal/flushcommon/syncmgr"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/util/importutilv2"
 	"github.com/milvus-io/milvus/pkg/v2/log"
@@ -53,6 +55,11 @@ type ImportTask struct {
 	syncMgr    syncmgr.SyncManager
 	cm         storage.ChunkManager
 	metaCaches map[string]metacache.MetaCache
+
+	// the constraints are compiled once per task, the files are imported concurrently.
+	constraintsOnce sync.Once
+	constraints     planparserv2.Constraints
+	constraintsErr  error
 }
 
 func NewImportTask(req *datapb.ImportRequest,
@@ -180,9 +187,24 @@ func (t *ImportTask) Execute() []*conc.Future[any] {
 	return futures
 }
 
+func (t *ImportTask) getConstraints() (planparserv2.Constraints, error) {
+	t.constraintsOnce.Do(func() {
+		t.constraints, t.constraintsErr = CompileConstraints(t.GetSchema())
+	})
+	return t.constraints, t.constraintsErr
+}
+
 func (t *ImportTask) importFile(reader importutilv2.Reader) error {
 	syncFutures := make([]*conc.Future[struct{}], 0)
 	syncTasks := make([]syncmgr.Task, 0)
+	var constraints planparserv2.Constraints
+	if !importutilv2.IsBackup(t.req.GetOptions()) {
+		var err error
+		constraints, err = t.getConstraints()
+		if err != nil {
+			return err
+		}
+	}
 	for {
 		data, err := reader.Read()
 		if err != nil {
@@ -196,6 +218,10 @@ func (t *ImportTask) importFile(reader importutilv2.Reader) error {
 			log.Info("0 row was imported, the data may have been deleted", WrapLogFields(t)...)
 			continue
 		}
+		err = CheckConstraints(constraints, data, rowNum)
+		if err != nil {
+			return err
+		}
 		err = AppendSystemFieldsData(t, data, rowNum)
 		if err != nil {
 			return err
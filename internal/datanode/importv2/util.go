@@ -32,6 +32,7 @@ import (
 	"github.com/milvus-io/milvus/internal/flushcommon/metacache"
 	"github.com/milvus-io/milvus/internal/flushcommon/metacache/pkoracle"
 	"github.com/milvus-io/milvus/internal/flushcommon/syncmgr"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/util/function"
 	"github.com/milvus-io/milvus/pkg/v2/common"
@@ -129,6 +130,32 @@ func PickSegment(segments []*datapb.ImportRequestSegment, vchannel string, parti
 	return candidates[r.Intn(len(candidates))].GetSegmentID(), nil
 }
 
+// CompileConstraints compiles the constraints defined by the properties of the collection, nil if there are none.
+func CompileConstraints(schema *schemapb.CollectionSchema) (planparserv2.Constraints, error) {
+	if !planparserv2.DefinesConstraints(schema.GetProperties()) {
+		return nil, nil
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	if err != nil {
+		return nil, err
+	}
+	constraints, err := planparserv2.CompileConstraints(helper)
+	if err != nil {
+		return nil, merr.WrapErrImportFailed(err.Error())
+	}
+	return constraints, nil
+}
+
+// CheckConstraints fails the import if a row doesn't match the constraints of the collection.
+func CheckConstraints(constraints planparserv2.Constraints, data *storage.InsertData, rowNum int) error {
+	for i := 0; i < rowNum; i++ {
+		if err := constraints.CheckRow(data.GetRow(i)); err != nil {
+			return merr.WrapErrImportFailed(fmt.Sprintf("row %d: %s", i, err.Error()))
+		}
+	}
+	return nil
+}
+
 func CheckRowsEqual(schema *schemapb.CollectionSchema, data *storage.InsertData) error {
 	if len(data.Data) == 0 {
 		return nil
@@ -0,0 +1,503 @@
+package planparserv2
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// ASTNode is the stable JSON representation of a filter expression, so that REST clients and
+// query builders can construct filters structurally instead of concatenating strings.
+//
+//	{"op": "and", "children": [
+//	    {"op": ">", "field": {"name": "age"}, "value": 18},
+//	    {"op": "in", "field": {"name": "meta", "path": ["tags"]}, "value": ["a", "b"]}
+//	]}
+//
+// Supported ops:
+//   - logical: "and", "or", "not", "true"
+//   - comparison: "==", "!=", ">", ">=", "<", "<=", with either value, template or right_field
+//   - "in", "like", "range", "is_null", "is_not_null", "exists"
+//   - "json_contains", "json_contains_all", "json_contains_any", "text_match", "phrase_match", "random_sample"
+type ASTNode struct {
+	Op             string      `json:"op"`
+	Children       []*ASTNode  `json:"children,omitempty"`
+	Field          *ASTField   `json:"field,omitempty"`
+	RightField     *ASTField   `json:"right_field,omitempty"`
+	Arith          *ASTArith   `json:"arith,omitempty"`
+	Value          interface{} `json:"value,omitempty"`
+	Template       string      `json:"template,omitempty"`
+	Lower          interface{} `json:"lower,omitempty"`
+	Upper          interface{} `json:"upper,omitempty"`
+	LowerInclusive bool        `json:"lower_inclusive,omitempty"`
+	UpperInclusive bool        `json:"upper_inclusive,omitempty"`
+	Slop           *int64      `json:"slop,omitempty"`
+	SampleFactor   float64     `json:"sample_factor,omitempty"`
+}
+
+// ASTField references a field by name, path is the nested JSON path or array index.
+type ASTField struct {
+	Name string   `json:"name"`
+	Path []string `json:"path,omitempty"`
+}
+
+// ASTArith is the arithmetic applied on the field before comparison, e.g. `a + 1 == 3` or `array_length(a) == 3`.
+type ASTArith struct {
+	Op    string      `json:"op"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+var astCompareOps = map[planpb.OpType]string{
+	planpb.OpType_GreaterThan:  ">",
+	planpb.OpType_GreaterEqual: ">=",
+	planpb.OpType_LessThan:     "<",
+	planpb.OpType_LessEqual:    "<=",
+	planpb.OpType_Equal:        "==",
+	planpb.OpType_NotEqual:     "!=",
+}
+
+var astArithOps = map[planpb.ArithOpType]string{
+	planpb.ArithOpType_Add:         "+",
+	planpb.ArithOpType_Sub:         "-",
+	planpb.ArithOpType_Mul:         "*",
+	planpb.ArithOpType_Div:         "/",
+	planpb.ArithOpType_Mod:         "%",
+	planpb.ArithOpType_ArrayLength: "array_length",
+}
+
+var astContainsOps = map[planpb.JSONContainsExpr_JSONOp]string{
+	planpb.JSONContainsExpr_Contains:    "json_contains",
+	planpb.JSONContainsExpr_ContainsAll: "json_contains_all",
+	planpb.JSONContainsExpr_ContainsAny: "json_contains_any",
+}
+
+// ParseExprFromAST parses the JSON representation of ASTNode into a plan expression.
+func ParseExprFromAST(schema *typeutil.SchemaHelper, ast []byte, exprTemplateValues map[string]*schemapb.TemplateValue) (*planpb.Expr, error) {
+	exprStr, err := ASTToExprString(ast)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExpr(schema, exprStr, exprTemplateValues)
+}
+
+// ASTToExprString renders the JSON representation of ASTNode as an expression string.
+func ASTToExprString(ast []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewBuffer(ast))
+	decoder.UseNumber()
+	node := &ASTNode{}
+	if err := decoder.Decode(node); err != nil {
+		return "", fmt.Errorf("invalid expression ast: %s", err)
+	}
+	if node.Op == "true" {
+		return "", nil
+	}
+	return node.render()
+}
+
+// ExportAST converts a parsed expression into the JSON representation of ASTNode.
+func ExportAST(schema *typeutil.SchemaHelper, expr *planpb.Expr) ([]byte, error) {
+	node, err := exportASTNode(schema, expr)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(node)
+}
+
+func (f *ASTField) render() (string, error) {
+	if f == nil {
+		return "", fmt.Errorf("field is required")
+	}
+	if f.Name != "$meta" && !identifierPattern.MatchString(f.Name) {
+		return "", fmt.Errorf("invalid field name: %s", f.Name)
+	}
+	var b strings.Builder
+	b.WriteString(f.Name)
+	for _, path := range f.Path {
+		if _, err := strconv.ParseUint(path, 10, 64); err == nil {
+			b.WriteString("[" + path + "]")
+		} else {
+			b.WriteString("[" + strconv.Quote(path) + "]")
+		}
+	}
+	return b.String(), nil
+}
+
+func (n *ASTNode) renderValue() (string, error) {
+	if n.Template != "" {
+		if !identifierPattern.MatchString(n.Template) {
+			return "", fmt.Errorf("invalid template name: %s", n.Template)
+		}
+		return "{" + n.Template + "}", nil
+	}
+	if n.Value == nil {
+		return "", fmt.Errorf("value is required by op %s", n.Op)
+	}
+	return formatJSONLiteral(n.Value)
+}
+
+func (n *ASTNode) renderChildren(op string) (string, error) {
+	if len(n.Children) == 0 {
+		return "", fmt.Errorf("op %s requires children", n.Op)
+	}
+	exprs := make([]string, 0, len(n.Children))
+	for _, child := range n.Children {
+		expr, err := child.render()
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	return joinExprs(exprs, op), nil
+}
+
+func (n *ASTNode) render() (string, error) {
+	switch n.Op {
+	case "and", "or":
+		return n.renderChildren(n.Op)
+	case "not":
+		if len(n.Children) != 1 {
+			return "", fmt.Errorf("op not requires exactly one child")
+		}
+		child, err := n.Children[0].render()
+		if err != nil {
+			return "", err
+		}
+		return negateExpr(child), nil
+	case "true":
+		return "", fmt.Errorf("op true can only be used as the root")
+	case "random_sample":
+		factor := strconv.FormatFloat(n.SampleFactor, 'f', -1, 64)
+		if len(n.Children) == 0 {
+			return "random_sample(" + factor + ")", nil
+		}
+		predicate, err := n.renderChildren("and")
+		if err != nil {
+			return "", err
+		}
+		return predicate + " and random_sample(" + factor + ")", nil
+	}
+
+	field, err := n.Field.render()
+	if err != nil {
+		return "", err
+	}
+	switch n.Op {
+	case "==", "!=", ">", ">=", "<", "<=":
+		left := field
+		if n.Arith != nil {
+			if left, err = n.Arith.render(field); err != nil {
+				return "", err
+			}
+		}
+		if n.RightField != nil {
+			right, err := n.RightField.render()
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s %s %s", left, n.Op, right), nil
+		}
+		value, err := n.renderValue()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s %s", left, n.Op, value), nil
+	case "in":
+		value, err := n.renderValue()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s in %s", field, value), nil
+	case "like", "text_match", "phrase_match":
+		pattern, ok := n.Value.(string)
+		if !ok {
+			return "", fmt.Errorf("op %s requires a string value", n.Op)
+		}
+		switch {
+		case n.Op == "like":
+			return fmt.Sprintf("%s like %s", field, strconv.Quote(pattern)), nil
+		case n.Op == "phrase_match" && n.Slop != nil:
+			return fmt.Sprintf("phrase_match(%s, %s, %d)", field, strconv.Quote(pattern), *n.Slop), nil
+		default:
+			return fmt.Sprintf("%s(%s, %s)", n.Op, field, strconv.Quote(pattern)), nil
+		}
+	case "range":
+		if n.Lower == nil || n.Upper == nil {
+			return "", fmt.Errorf("op range requires both lower and upper")
+		}
+		lower, err := formatJSONLiteral(n.Lower)
+		if err != nil {
+			return "", err
+		}
+		upper, err := formatJSONLiteral(n.Upper)
+		if err != nil {
+			return "", err
+		}
+		lowerOp, upperOp := "<", "<"
+		if n.LowerInclusive {
+			lowerOp = "<="
+		}
+		if n.UpperInclusive {
+			upperOp = "<="
+		}
+		return fmt.Sprintf("%s %s %s %s %s", lower, lowerOp, field, upperOp, upper), nil
+	case "is_null":
+		return field + " is null", nil
+	case "is_not_null":
+		return field + " is not null", nil
+	case "exists":
+		return "exists " + field, nil
+	case "json_contains", "json_contains_all", "json_contains_any":
+		value, err := n.renderValue()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s(%s, %s)", n.Op, field, value), nil
+	default:
+		return "", fmt.Errorf("unknown ast op: %s", n.Op)
+	}
+}
+
+func (a *ASTArith) render(field string) (string, error) {
+	if a.Op == "array_length" {
+		return "array_length(" + field + ")", nil
+	}
+	switch a.Op {
+	case "+", "-", "*", "/", "%":
+	default:
+		return "", fmt.Errorf("unknown arithmetic op: %s", a.Op)
+	}
+	if a.Value == nil {
+		return "", fmt.Errorf("arithmetic op %s requires a value", a.Op)
+	}
+	value, err := formatJSONLiteral(a.Value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s", field, a.Op, value), nil
+}
+
+func exportASTField(schema *typeutil.SchemaHelper, info *planpb.ColumnInfo) (*ASTField, error) {
+	field, err := schema.GetFieldFromID(info.GetFieldId())
+	if err != nil {
+		return nil, err
+	}
+	return &ASTField{Name: field.GetName(), Path: info.GetNestedPath()}, nil
+}
+
+func exportASTValue(value *planpb.GenericValue) interface{} {
+	switch v := value.GetVal().(type) {
+	case *planpb.GenericValue_BoolVal:
+		return v.BoolVal
+	case *planpb.GenericValue_Int64Val:
+		return v.Int64Val
+	case *planpb.GenericValue_FloatVal:
+		// keep the decimal point so that the value is still parsed as floating after round trip.
+		literal := strconv.FormatFloat(v.FloatVal, 'g', -1, 64)
+		if !strings.ContainsAny(literal, ".eEnN") {
+			literal += ".0"
+		}
+		return json.Number(literal)
+	case *planpb.GenericValue_StringVal:
+		return v.StringVal
+	case *planpb.GenericValue_ArrayVal:
+		values := make([]interface{}, 0, len(v.ArrayVal.GetArray()))
+		for _, e := range v.ArrayVal.GetArray() {
+			values = append(values, exportASTValue(e))
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func exportASTValues(values []*planpb.GenericValue) []interface{} {
+	ret := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		ret = append(ret, exportASTValue(value))
+	}
+	return ret
+}
+
+func exportASTNode(schema *typeutil.SchemaHelper, expr *planpb.Expr) (*ASTNode, error) {
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_AlwaysTrueExpr:
+		return &ASTNode{Op: "true"}, nil
+	case *planpb.Expr_UnaryExpr:
+		child, err := exportASTNode(schema, realExpr.UnaryExpr.GetChild())
+		if err != nil {
+			return nil, err
+		}
+		return &ASTNode{Op: "not", Children: []*ASTNode{child}}, nil
+	case *planpb.Expr_BinaryExpr:
+		op := "and"
+		if realExpr.BinaryExpr.GetOp() == planpb.BinaryExpr_LogicalOr {
+			op = "or"
+		}
+		node := &ASTNode{Op: op}
+		for _, child := range []*planpb.Expr{realExpr.BinaryExpr.GetLeft(), realExpr.BinaryExpr.GetRight()} {
+			childNode, err := exportASTNode(schema, child)
+			if err != nil {
+				return nil, err
+			}
+			// flatten the nested conjunctions/disjunctions to keep the representation canonical.
+			if childNode.Op == op {
+				node.Children = append(node.Children, childNode.Children...)
+			} else {
+				node.Children = append(node.Children, childNode)
+			}
+		}
+		return node, nil
+	case *planpb.Expr_TermExpr:
+		field, err := exportASTField(schema, realExpr.TermExpr.GetColumnInfo())
+		if err != nil {
+			return nil, err
+		}
+		node := &ASTNode{Op: "in", Field: field, Template: realExpr.TermExpr.GetTemplateVariableName()}
+		if node.Template == "" {
+			node.Value = exportASTValues(realExpr.TermExpr.GetValues())
+		}
+		return node, nil
+	case *planpb.Expr_CompareExpr:
+		left, err := exportASTField(schema, realExpr.CompareExpr.GetLeftColumnInfo())
+		if err != nil {
+			return nil, err
+		}
+		right, err := exportASTField(schema, realExpr.CompareExpr.GetRightColumnInfo())
+		if err != nil {
+			return nil, err
+		}
+		op, ok := astCompareOps[realExpr.CompareExpr.GetOp()]
+		if !ok {
+			return nil, fmt.Errorf("operator %s cannot be exported as ast", realExpr.CompareExpr.GetOp())
+		}
+		return &ASTNode{Op: op, Field: left, RightField: right}, nil
+	case *planpb.Expr_UnaryRangeExpr:
+		return exportASTUnaryRange(schema, realExpr.UnaryRangeExpr)
+	case *planpb.Expr_BinaryRangeExpr:
+		e := realExpr.BinaryRangeExpr
+		if e.GetLowerTemplateVariableName() != "" || e.GetUpperTemplateVariableName() != "" {
+			return nil, fmt.Errorf("template variables in range expression cannot be exported")
+		}
+		field, err := exportASTField(schema, e.GetColumnInfo())
+		if err != nil {
+			return nil, err
+		}
+		return &ASTNode{
+			Op:             "range",
+			Field:          field,
+			Lower:          exportASTValue(e.GetLowerValue()),
+			Upper:          exportASTValue(e.GetUpperValue()),
+			LowerInclusive: e.GetLowerInclusive(),
+			UpperInclusive: e.GetUpperInclusive(),
+		}, nil
+	case *planpb.Expr_BinaryArithOpEvalRangeExpr:
+		e := realExpr.BinaryArithOpEvalRangeExpr
+		if e.GetOperandTemplateVariableName() != "" {
+			return nil, fmt.Errorf("template variables in arithmetic operand cannot be exported")
+		}
+		field, err := exportASTField(schema, e.GetColumnInfo())
+		if err != nil {
+			return nil, err
+		}
+		arithOp, ok := astArithOps[e.GetArithOp()]
+		if !ok {
+			return nil, fmt.Errorf("arithmetic operator %s cannot be exported as ast", e.GetArithOp())
+		}
+		op, ok := astCompareOps[e.GetOp()]
+		if !ok {
+			return nil, fmt.Errorf("operator %s cannot be exported as ast", e.GetOp())
+		}
+		arith := &ASTArith{Op: arithOp}
+		if e.GetArithOp() != planpb.ArithOpType_ArrayLength {
+			arith.Value = exportASTValue(e.GetRightOperand())
+		}
+		node := &ASTNode{Op: op, Field: field, Arith: arith, Template: e.GetValueTemplateVariableName()}
+		if node.Template == "" {
+			node.Value = exportASTValue(e.GetValue())
+		}
+		return node, nil
+	case *planpb.Expr_NullExpr:
+		field, err := exportASTField(schema, realExpr.NullExpr.GetColumnInfo())
+		if err != nil {
+			return nil, err
+		}
+		op := "is_null"
+		if realExpr.NullExpr.GetOp() == planpb.NullExpr_IsNotNull {
+			op = "is_not_null"
+		}
+		return &ASTNode{Op: op, Field: field}, nil
+	case *planpb.Expr_ExistsExpr:
+		field, err := exportASTField(schema, realExpr.ExistsExpr.GetInfo())
+		if err != nil {
+			return nil, err
+		}
+		return &ASTNode{Op: "exists", Field: field}, nil
+	case *planpb.Expr_JsonContainsExpr:
+		e := realExpr.JsonContainsExpr
+		field, err := exportASTField(schema, e.GetColumnInfo())
+		if err != nil {
+			return nil, err
+		}
+		op, ok := astContainsOps[e.GetOp()]
+		if !ok {
+			return nil, fmt.Errorf("operator %s cannot be exported as ast", e.GetOp())
+		}
+		node := &ASTNode{Op: op, Field: field, Template: e.GetTemplateVariableName()}
+		if node.Template == "" {
+			if e.GetOp() == planpb.JSONContainsExpr_Contains && len(e.GetElements()) == 1 {
+				node.Value = exportASTValue(e.GetElements()[0])
+			} else {
+				node.Value = exportASTValues(e.GetElements())
+			}
+		}
+		return node, nil
+	case *planpb.Expr_RandomSampleExpr:
+		node := &ASTNode{Op: "random_sample", SampleFactor: float64(realExpr.RandomSampleExpr.GetSampleFactor())}
+		if predicate := realExpr.RandomSampleExpr.GetPredicate(); predicate != nil {
+			child, err := exportASTNode(schema, predicate)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = []*ASTNode{child}
+		}
+		return node, nil
+	default:
+		return nil, fmt.Errorf("expression %T cannot be exported as ast", realExpr)
+	}
+}
+
+func exportASTUnaryRange(schema *typeutil.SchemaHelper, expr *planpb.UnaryRangeExpr) (*ASTNode, error) {
+	field, err := exportASTField(schema, expr.GetColumnInfo())
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := astCompareOps[expr.GetOp()]; ok {
+		node := &ASTNode{Op: op, Field: field, Template: expr.GetTemplateVariableName()}
+		if node.Template == "" {
+			node.Value = exportASTValue(expr.GetValue())
+		}
+		return node, nil
+	}
+	operand := expr.GetValue().GetStringVal()
+	switch expr.GetOp() {
+	case planpb.OpType_PrefixMatch, planpb.OpType_PostfixMatch, planpb.OpType_Match:
+		return &ASTNode{Op: "like", Field: field, Value: likePattern(expr.GetOp(), operand)}, nil
+	case planpb.OpType_TextMatch:
+		return &ASTNode{Op: "text_match", Field: field, Value: operand}, nil
+	case planpb.OpType_PhraseMatch:
+		node := &ASTNode{Op: "phrase_match", Field: field, Value: operand}
+		if len(expr.GetExtraValues()) > 0 {
+			slop := expr.GetExtraValues()[0].GetInt64Val()
+			node.Slop = &slop
+		}
+		return node, nil
+	default:
+		return nil, fmt.Errorf("operator %s cannot be exported as ast", expr.GetOp())
+	}
+}
@@ -0,0 +1,121 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestASTToExprString(t *testing.T) {
+	cases := []struct {
+		ast  string
+		expr string
+	}{
+		{`{"op": "true"}`, ``},
+		{
+			`{"op": "and", "children": [{"op": ">", "field": {"name": "age"}, "value": 18}, {"op": "in", "field": {"name": "meta", "path": ["tags", "0"]}, "value": ["a", "b"]}]}`,
+			`(age > 18 and meta["tags"][0] in ["a", "b"])`,
+		},
+		{`{"op": "not", "children": [{"op": "is_null", "field": {"name": "a"}}]}`, `not (a is null)`},
+		{`{"op": "==", "field": {"name": "a"}, "template": "x"}`, `a == {x}`},
+		{`{"op": "<", "field": {"name": "a"}, "right_field": {"name": "b"}}`, `a < b`},
+		{`{"op": "==", "field": {"name": "a"}, "arith": {"op": "+", "value": 1}, "value": 3}`, `a + 1 == 3`},
+		{`{"op": ">", "field": {"name": "a"}, "arith": {"op": "array_length"}, "value": 3}`, `array_length(a) > 3`},
+		{`{"op": "range", "field": {"name": "a"}, "lower": 1, "upper": 2.5, "lower_inclusive": true}`, `1 <= a < 2.5`},
+		{`{"op": "like", "field": {"name": "s"}, "value": "a\"b%"}`, `s like "a\"b%"`},
+		{`{"op": "phrase_match", "field": {"name": "s"}, "value": "x y", "slop": 2}`, `phrase_match(s, "x y", 2)`},
+		{`{"op": "json_contains_any", "field": {"name": "$meta", "path": ["a"]}, "value": [1, 2]}`, `json_contains_any($meta["a"], [1, 2])`},
+		{`{"op": "random_sample", "sample_factor": 0.5, "children": [{"op": "exists", "field": {"name": "j", "path": ["k"]}}]}`, `exists j["k"] and random_sample(0.5)`},
+	}
+	for _, c := range cases {
+		expr, err := ASTToExprString([]byte(c.ast))
+		assert.NoError(t, err, c.ast)
+		assert.Equal(t, c.expr, expr, c.ast)
+	}
+
+	invalid := []string{
+		`[]`,
+		`{"op": "xor"}`,
+		`{"op": "and"}`,
+		`{"op": "not", "children": []}`,
+		`{"op": "and", "children": [{"op": "true"}]}`,
+		`{"op": "==", "value": 1}`,
+		`{"op": "==", "field": {"name": "a b"}, "value": 1}`,
+		`{"op": "==", "field": {"name": "a"}}`,
+		`{"op": "==", "field": {"name": "a"}, "template": "a-b"}`,
+		`{"op": "==", "field": {"name": "a"}, "arith": {"op": "^", "value": 1}, "value": 1}`,
+		`{"op": "==", "field": {"name": "a"}, "arith": {"op": "+"}, "value": 1}`,
+		`{"op": "like", "field": {"name": "a"}, "value": 1}`,
+		`{"op": "range", "field": {"name": "a"}, "lower": 1}`,
+		`{"op": "==", "field": {"name": "a"}, "value": {"x": 1}}`,
+	}
+	for _, ast := range invalid {
+		_, err := ASTToExprString([]byte(ast))
+		assert.Error(t, err, ast)
+	}
+}
+
+func TestExportAST_RoundTrip(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	exprStrs := []string{
+		``,
+		`Int64Field in [1, 2, 3]`,
+		`Int64Field > 1 and (VarCharField == "a\"b" or not (BoolField == true))`,
+		`Int64Field < Int32Field`,
+		`DoubleField >= 1.0`,
+		`FloatField in [1.5, 2]`,
+		`VarCharField like "ab%"`,
+		`VarCharField like "%a_b%"`,
+		`1 < Int64Field <= 10`,
+		`Int64Field % 3 == 1`,
+		`array_length(ArrayField) == 2`,
+		`Int64Field is not null`,
+		`exists JSONField["a"]["b"]`,
+		`A["b"] != "x"`,
+		`ArrayField[0] == 1`,
+		`json_contains(JSONField["x"], 1)`,
+		`array_contains_all(ArrayField, [1, 2])`,
+		`Int64Field > 1 and random_sample(0.25)`,
+	}
+	for _, exprStr := range exprStrs {
+		expr, err := ParseExpr(helper, exprStr, nil)
+		require.NoError(t, err, exprStr)
+
+		ast, err := ExportAST(helper, expr)
+		require.NoError(t, err, exprStr)
+
+		expr2, err := ParseExprFromAST(helper, ast, nil)
+		require.NoError(t, err, string(ast))
+		assert.True(t, proto.Equal(expr, expr2), "%s: %s", exprStr, string(ast))
+	}
+}
+
+func TestExportAST_UnmappedOp(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+	info := &planpb.ColumnInfo{FieldId: 105, DataType: schemapb.DataType_Int64}
+
+	exprs := []*planpb.Expr{
+		{Expr: &planpb.Expr_CompareExpr{CompareExpr: &planpb.CompareExpr{
+			LeftColumnInfo: info, RightColumnInfo: info, Op: planpb.OpType_Range,
+		}}},
+		{Expr: &planpb.Expr_BinaryArithOpEvalRangeExpr{BinaryArithOpEvalRangeExpr: &planpb.BinaryArithOpEvalRangeExpr{
+			ColumnInfo: info, ArithOp: planpb.ArithOpType_Unknown, RightOperand: NewInt(1), Op: planpb.OpType_Equal, Value: NewInt(1),
+		}}},
+		{Expr: &planpb.Expr_BinaryArithOpEvalRangeExpr{BinaryArithOpEvalRangeExpr: &planpb.BinaryArithOpEvalRangeExpr{
+			ColumnInfo: info, ArithOp: planpb.ArithOpType_Add, RightOperand: NewInt(1), Op: planpb.OpType_PrefixMatch, Value: NewInt(1),
+		}}},
+		{Expr: &planpb.Expr_JsonContainsExpr{JsonContainsExpr: &planpb.JSONContainsExpr{
+			ColumnInfo: info, Op: planpb.JSONContainsExpr_Invalid, Elements: []*planpb.GenericValue{NewInt(1)},
+		}}},
+	}
+	for _, expr := range exprs {
+		_, err := ExportAST(helper, expr)
+		assert.Error(t, err, expr.String())
+	}
+}
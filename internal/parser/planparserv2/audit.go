@@ -0,0 +1,43 @@
+package planparserv2
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// AuditEvent describes an expression parsed successfully by ParseExpr.
+type AuditEvent struct {
+	Expr           string
+	Database       string
+	CollectionID   int64
+	CollectionName string
+	// Role is the role set by WithRole.
+	Role string
+	// Fields are the fields filtered on by the expression, without the ones of the row level policy.
+	Fields []*FieldReference
+}
+
+// AuditHook is called after each successful parse, with the request context set by WithContext,
+// which carries the identity of the caller.
+type AuditHook func(ctx context.Context, event *AuditEvent)
+
+var auditHook atomic.Pointer[AuditHook]
+
+// SetAuditHook sets the hook used to audit parsed expressions. A nil hook disables auditing.
+func SetAuditHook(hook AuditHook) {
+	if hook == nil {
+		auditHook.Store(nil)
+		return
+	}
+	auditHook.Store(&hook)
+}
+
+func collectFieldReferences(schema *typeutil.SchemaHelper, expr *planpb.Expr) []*FieldReference {
+	collector := newExprReportCollector(schema, &ExprReport{})
+	// the expression is already checked against the schema, errors only stop collecting unknown nodes.
+	_ = collector.collect(expr)
+	return collector.report.Fields
+}
@@ -0,0 +1,47 @@
+package planparserv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/common"
+)
+
+type testUserKey struct{}
+
+func TestParseExpr_AuditHook(t *testing.T) {
+	schema := newTestSchemaHelper(t).WithEpoch("db", 1, 1)
+
+	var events []*AuditEvent
+	SetAuditHook(func(ctx context.Context, event *AuditEvent) {
+		assert.Equal(t, "alice", ctx.Value(testUserKey{}))
+		events = append(events, event)
+	})
+	defer SetAuditHook(nil)
+	RegisterRowLevelPolicy(1, "tenant", `Int32Field == 1`)
+	defer UnregisterRowLevelPolicy(1, "tenant")
+
+	ctx := WithContext(context.WithValue(context.Background(), testUserKey{}, "alice"))
+	exprStr := `Int64Field > 1 and JSONField["a"] == "b" and color == "red"`
+	_, err := ParseExpr(schema, exprStr, nil, ctx, WithRole("tenant", nil))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	event := events[0]
+	assert.Equal(t, exprStr, event.Expr)
+	assert.Equal(t, "db", event.Database)
+	assert.Equal(t, int64(1), event.CollectionID)
+	assert.Equal(t, "test", event.CollectionName)
+	assert.Equal(t, "tenant", event.Role)
+	require.Len(t, event.Fields, 3)
+	assert.Equal(t, "Int64Field", event.Fields[0].FieldName)
+	assert.Equal(t, []string{"a"}, event.Fields[1].NestedPath)
+	assert.Equal(t, common.MetaFieldName, event.Fields[2].FieldName)
+
+	// failed parses are not audited.
+	_, err = ParseExpr(schema, `Int64Field >`, nil, ctx)
+	assert.Error(t, err)
+	assert.Len(t, events, 1)
+}
@@ -0,0 +1,76 @@
+package planparserv2
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	parser "github.com/milvus-io/milvus/internal/parser/planparserv2/generated"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// parseIntegerLiteral parses an integer literal. Literals beyond the int64 range, such as uint64 values, are
+// kept as the nearest double, which floating point and JSON columns compare with, while integer columns reject them.
+func parseIntegerLiteral(literal string) (*planpb.GenericValue, schemapb.DataType, error) {
+	i, err := strconv.ParseInt(literal, 0, 64)
+	if err == nil {
+		return NewInt(i), schemapb.DataType_Int64, nil
+	}
+	if !errors.Is(err, strconv.ErrRange) {
+		return nil, schemapb.DataType_None, err
+	}
+	n, ok := new(big.Int).SetString(literal, 0)
+	if !ok {
+		return nil, schemapb.DataType_None, err
+	}
+	f, _ := new(big.Float).SetInt(n).Float64()
+	if math.IsInf(f, 0) {
+		return nil, schemapb.DataType_None, fmt.Errorf("integer literal %s is out of the range of double", literal)
+	}
+	return NewFloat(f), schemapb.DataType_Double, nil
+}
+
+// isBeyondInt64 returns whether the value is an integral double of magnitude 2^63 or more, which is how
+// integer literals beyond the int64 range are kept after rounding.
+func isBeyondInt64(value *planpb.GenericValue) bool {
+	if !IsFloating(value) {
+		return false
+	}
+	f := value.GetFloatVal()
+	return math.Trunc(f) == f && math.Abs(f) >= 1<<63
+}
+
+// checkIntegerOverflow rejects folding two integer constants if the result overflows int64.
+func checkIntegerOverflow(op int, a, b *planpb.GenericValue) error {
+	if !IsInteger(a) || !IsInteger(b) {
+		return nil
+	}
+	x, y := big.NewInt(a.GetInt64Val()), big.NewInt(b.GetInt64Val())
+	var symbol string
+	switch op {
+	case parser.PlanParserADD:
+		x.Add(x, y)
+		symbol = "+"
+	case parser.PlanParserSUB:
+		x.Sub(x, y)
+		symbol = "-"
+	case parser.PlanParserMUL:
+		x.Mul(x, y)
+		symbol = "*"
+	case parser.PlanParserDIV:
+		if y.Sign() == 0 {
+			return nil
+		}
+		x.Quo(x, y)
+		symbol = "/"
+	default:
+		return nil
+	}
+	if !x.IsInt64() {
+		return fmt.Errorf("integer overflow: %d %s %d is out of the int64 range", a.GetInt64Val(), symbol, b.GetInt64Val())
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package planparserv2
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIntegerLiteral(t *testing.T) {
+	value, _, err := parseIntegerLiteral("9223372036854775807")
+	require.NoError(t, err)
+	assert.Equal(t, int64(math.MaxInt64), value.GetInt64Val())
+
+	for literal, expected := range map[string]float64{
+		"18446744073709551615":          math.MaxUint64,
+		"0xFFFFFFFFFFFFFFFF":            math.MaxUint64,
+		"9223372036854775808":           math.MaxInt64 + 1.0,
+		"10000000000000000000000000000": 1e28,
+	} {
+		value, _, err := parseIntegerLiteral(literal)
+		require.NoError(t, err, literal)
+		assert.True(t, IsFloating(value), literal)
+		assert.Equal(t, expected, value.GetFloatVal(), literal)
+		assert.True(t, isBeyondInt64(value), literal)
+	}
+
+	_, _, err = parseIntegerLiteral("1" + strings.Repeat("0", 400))
+	assert.Error(t, err)
+}
+
+func TestBigIntegerLiterals(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	expr, err := ParseExpr(schema, `Int64Field > -9223372036854775808`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(math.MinInt64), expr.GetUnaryRangeExpr().GetValue().GetInt64Val())
+
+	for exprStr, expected := range map[string]float64{
+		`DoubleField < 18446744073709551615`:    math.MaxUint64,
+		`FloatField > -18446744073709551616`:    -math.MaxUint64,
+		`JSONField["a"] == 9223372036854775808`: math.MaxInt64 + 1.0,
+	} {
+		expr, err := ParseExpr(schema, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		assert.Equal(t, expected, expr.GetUnaryRangeExpr().GetValue().GetFloatVal(), exprStr)
+	}
+
+	for exprStr, msg := range map[string]string{
+		`Int64Field < 18446744073709551615`:       "integer 18446744073709551616 is out of the range of Int64",
+		`Int64Field in [1, 9223372036854775808]`:  "out of the range of Int64",
+		`Int32Field > -9223372036854775809`:       "out of the range of Int32",
+		`Int64Field == 9223372036854775807 + 1`:   "integer overflow: 9223372036854775807 + 1",
+		`Int64Field == -9223372036854775808 - 1`:  "integer overflow",
+		`Int64Field == 4294967296 * 4294967296`:   "integer overflow: 4294967296 * 4294967296",
+		`Int64Field == -9223372036854775808 / -1`: "integer overflow",
+		`Int64Field == -(-9223372036854775808)`:   "integer overflow",
+	} {
+		_, err := ParseExpr(schema, exprStr, nil)
+		assert.ErrorContains(t, err, msg, exprStr)
+	}
+}
@@ -0,0 +1,66 @@
+package planparserv2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	parser "github.com/milvus-io/milvus/internal/parser/planparserv2/generated"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// rewriteIsBoolean rewrites `x IS TRUE` and `x IS FALSE` into `x == true` and `x == false`, which the grammar
+// can parse. The expression is lexed, so that string literals are left untouched.
+func rewriteIsBoolean(exprStr string) string {
+	if !strings.Contains(strings.ToLower(exprStr), "is") {
+		return exprStr
+	}
+	listener := &errorListenerImpl{}
+	lexer := getLexer(antlr.NewInputStream(exprStr), listener)
+	defer putLexer(lexer)
+	tokens := lexer.GetAllTokens()
+	if listener.Error() != nil {
+		// leave the error to the parser.
+		return exprStr
+	}
+
+	runes := []rune(exprStr)
+	var builder strings.Builder
+	builder.Grow(len(exprStr) + 1)
+	last := 0
+	for i := 1; i+1 < len(tokens); i++ {
+		token := tokens[i]
+		if token.GetTokenType() != parser.PlanLexerIdentifier || !strings.EqualFold(token.GetText(), "is") ||
+			tokens[i+1].GetTokenType() != parser.PlanLexerBooleanConstant {
+			continue
+		}
+		builder.WriteString(string(runes[last:token.GetStart()]))
+		builder.WriteString("==")
+		last = token.GetStop() + 1
+	}
+	if last == 0 {
+		return exprStr
+	}
+	builder.WriteString(string(runes[last:]))
+	return builder.String()
+}
+
+// checkBareBoolColumn rejects a bare boolean column used as a predicate in a logical context, at any nesting
+// level, pointing to the explicit forms instead.
+func checkBareBoolColumn(schema *typeutil.SchemaHelper, e *ExprWithType) error {
+	if e == nil || e.dataType != schemapb.DataType_Bool {
+		return nil
+	}
+	columnInfo := e.expr.GetColumnExpr().GetInfo()
+	if columnInfo == nil {
+		return nil
+	}
+	name := fmt.Sprint(columnInfo.GetFieldId())
+	if field, err := schema.GetFieldFromID(columnInfo.GetFieldId()); err == nil {
+		name = field.GetName()
+	}
+	return fmt.Errorf("boolean column %s cannot be used as a predicate directly, use `%s is true` or `%s is false` instead",
+		name, name, name)
+}
@@ -0,0 +1,65 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestRewriteIsBoolean(t *testing.T) {
+	for exprStr, expected := range map[string]string{
+		`BoolField is true`:                         `BoolField == true`,
+		`BoolField IS FALSE and Int64Field > 1`:     `BoolField == FALSE and Int64Field > 1`,
+		`VarCharField == "is true" or A is  True`:   `VarCharField == "is true" or A ==  True`,
+		`$meta["中"] is false`:                       `$meta["中"] == false`,
+		`A is null and B is not null`:               `A is null and B is not null`,
+		`Int64Field > 1`:                            `Int64Field > 1`,
+		`VarCharField == "unterminated is true`:     `VarCharField == "unterminated is true`,
+		`ArrayField[0] is true and BoolField is is`: `ArrayField[0] == true and BoolField is is`,
+	} {
+		assert.Equal(t, expected, rewriteIsBoolean(exprStr), exprStr)
+	}
+}
+
+func TestBareBoolColumn(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	field, err := schema.GetFieldFromName("BoolField")
+	require.NoError(t, err)
+
+	for _, exprStr := range []string{`BoolField is true`, `BoolField IS TRUE`, `(BoolField is true)`} {
+		expr, err := ParseExpr(schema, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		unary := expr.GetUnaryRangeExpr()
+		assert.Equal(t, field.GetFieldID(), unary.GetColumnInfo().GetFieldId())
+		assert.Equal(t, planpb.OpType_Equal, unary.GetOp())
+		assert.True(t, unary.GetValue().GetBoolVal())
+	}
+
+	expr, err := ParseExpr(schema, `Int64Field > 1 and not (BoolField is false)`, nil)
+	require.NoError(t, err)
+	assert.False(t, expr.GetBinaryExpr().GetRight().GetUnaryExpr().GetChild().GetUnaryRangeExpr().GetValue().GetBoolVal())
+
+	// bare columns are rejected the same at any nesting level.
+	for _, exprStr := range []string{
+		`BoolField`, `(BoolField)`, `BoolField and Int64Field > 1`, `Int64Field > 1 or BoolField`,
+		`not BoolField`, `Int64Field > 1 or (Int8Field < 0 and not (BoolField))`,
+	} {
+		_, err := ParseExpr(schema, exprStr, nil)
+		assert.ErrorContains(t, err, "use `BoolField is true` or `BoolField is false` instead", exprStr)
+	}
+
+	for _, exprStr := range []string{`$meta["a"] and BoolField is true`, `Int64Field is true`} {
+		_, err := ParseExpr(schema, exprStr, nil)
+		assert.Error(t, err, exprStr)
+	}
+
+	// identifiers are still parsed as columns.
+	err = ParseIdentifier(schema, "BoolField", func(expr *planpb.Expr) error {
+		assert.NotNil(t, expr.GetColumnExpr())
+		return nil
+	})
+	assert.NoError(t, err)
+}
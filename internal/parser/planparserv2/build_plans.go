@@ -0,0 +1,51 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/hardware"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// SearchPlanRequest holds the arguments of CreateSearchPlan for one sub-request of a hybrid search.
+type SearchPlanRequest struct {
+	ExprStr            string
+	VectorFieldName    string
+	QueryInfo          *planpb.QueryInfo
+	ExprTemplateValues map[string]*schemapb.TemplateValue
+	// Opts are applied after the options shared by all sub-requests.
+	Opts []ParseOption
+}
+
+// BuildPlans creates the search plans of the sub-requests of a hybrid search concurrently, sharing the schema
+// helper and the expression cache. At most parallelism plans are built at a time, or the number of CPUs
+// if parallelism is not positive. The plans are returned in the order of the requests.
+func BuildPlans(schema *typeutil.SchemaHelper, requests []*SearchPlanRequest, parallelism int, opts ...ParseOption) ([]*planpb.PlanNode, error) {
+	if parallelism <= 0 {
+		parallelism = hardware.GetCPUNum()
+	}
+	plans := make([]*planpb.PlanNode, len(requests))
+	group := errgroup.Group{}
+	group.SetLimit(parallelism)
+	for i, request := range requests {
+		i, request := i, request
+		group.Go(func() error {
+			requestOpts := append(append([]ParseOption{}, opts...), request.Opts...)
+			plan, err := CreateSearchPlan(schema, request.ExprStr, request.VectorFieldName, request.QueryInfo,
+				request.ExprTemplateValues, requestOpts...)
+			if err != nil {
+				return fmt.Errorf("failed to create plan of sub-request %d: %w", i, err)
+			}
+			plans[i] = plan
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
@@ -0,0 +1,58 @@
+package planparserv2
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestBuildPlans(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	fields := []string{"FloatVectorField", "BinaryVectorField", "Float16VectorField", "SparseFloatVectorField"}
+
+	requests := make([]*SearchPlanRequest, 0, 32)
+	for i := 0; i < 32; i++ {
+		requests = append(requests, &SearchPlanRequest{
+			ExprStr:         fmt.Sprintf(`Int64Field > %d and VarCharField == {v}`, i),
+			VectorFieldName: fields[i%len(fields)],
+			QueryInfo:       &planpb.QueryInfo{Topk: int64(i + 1)},
+			ExprTemplateValues: map[string]*schemapb.TemplateValue{
+				"v": {Val: &schemapb.TemplateValue_StringVal{StringVal: fmt.Sprint(i)}},
+			},
+		})
+	}
+	for _, parallelism := range []int{0, 1, 4} {
+		plans, err := BuildPlans(schema, requests, parallelism, WithTopKLimit(100))
+		require.NoError(t, err)
+		require.Len(t, plans, len(requests))
+		for i, plan := range plans {
+			anns := plan.GetVectorAnns()
+			assert.Equal(t, int64(i+1), anns.GetQueryInfo().GetTopk())
+			field, err := schema.GetFieldFromName(fields[i%len(fields)])
+			require.NoError(t, err)
+			assert.Equal(t, field.GetFieldID(), anns.GetFieldId())
+			binary := anns.GetPredicates().GetBinaryExpr()
+			assert.Equal(t, int64(i), binary.GetLeft().GetUnaryRangeExpr().GetValue().GetInt64Val())
+			assert.Equal(t, fmt.Sprint(i), binary.GetRight().GetUnaryRangeExpr().GetValue().GetStringVal())
+		}
+	}
+
+	// options of sub-requests apply after the shared ones.
+	requests[3].Opts = []ParseOption{WithTopKLimit(2)}
+	_, err := BuildPlans(schema, requests, 4, WithTopKLimit(100))
+	assert.ErrorContains(t, err, "sub-request 3")
+
+	requests[3].Opts = nil
+	requests[5].ExprStr = `Int64Field >`
+	_, err = BuildPlans(schema, requests, 4)
+	assert.ErrorContains(t, err, "sub-request 5")
+
+	plans, err := BuildPlans(schema, nil, 4)
+	require.NoError(t, err)
+	assert.Empty(t, plans)
+}
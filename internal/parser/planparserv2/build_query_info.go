@@ -0,0 +1,135 @@
+package planparserv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+const searchHintsKey = "hints"
+
+// searchParamIntKeys are the search params which must be positive integers.
+var searchParamIntKeys = []string{"nprobe", "ef", "level", "search_list", "itopk_size"}
+
+// BuildQueryInfo builds the query info of a search on the vector field from the topk, metric type and the
+// search_params JSON given by the user, such as `{"nprobe": 10, "radius": 0.5, "hints": "iterative_filter"}`.
+// The hints are moved into QueryInfo.Hints, and the query info is validated like CreateSearchPlan does.
+func BuildQueryInfo(schema *typeutil.SchemaHelper, vectorFieldName string, topK int64, metricType string,
+	searchParams string, opts ...ParseOption,
+) (*planpb.QueryInfo, error) {
+	vectorField, err := schema.GetFieldFromName(vectorFieldName)
+	if err != nil {
+		return nil, err
+	}
+	if !typeutil.IsVectorType(vectorField.GetDataType()) {
+		return nil, merr.WrapErrParameterInvalidMsg("field (%s) to search is not of vector data type", vectorFieldName)
+	}
+	queryInfo := &planpb.QueryInfo{Topk: topK, MetricType: metricType, RoundDecimal: -1, GroupByFieldId: -1}
+
+	if searchParams != "" {
+		params := make(map[string]interface{})
+		decoder := json.NewDecoder(bytes.NewReader([]byte(searchParams)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&params); err != nil {
+			return nil, merr.WrapErrParameterInvalidMsg("invalid search params %s: %s", searchParams, err.Error())
+		}
+		if err := parseSearchParams(params, queryInfo); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validateQueryInfo(schema, vectorField, queryInfo, newParseOptions(opts...)); err != nil {
+		return nil, err
+	}
+	return queryInfo, nil
+}
+
+func parseSearchParams(params map[string]interface{}, queryInfo *planpb.QueryInfo) error {
+	for _, key := range searchParamIntKeys {
+		value, ok := params[key]
+		if !ok {
+			continue
+		}
+		i, err := searchParamNumber(key, value)
+		if err != nil {
+			return err
+		}
+		if i <= 0 || i != math.Trunc(i) {
+			return merr.WrapErrParameterInvalidMsg("search param %s must be a positive integer, got %v", key, value)
+		}
+	}
+
+	radius, hasRadius := params[radiusKey]
+	rangeFilter, hasRangeFilter := params[rangeFilterKey]
+	if hasRangeFilter && !hasRadius {
+		return merr.WrapErrParameterInvalidMsg("search param %s requires %s", rangeFilterKey, radiusKey)
+	}
+	if hasRadius {
+		r, err := searchParamNumber(radiusKey, radius)
+		if err != nil {
+			return err
+		}
+		if hasRangeFilter {
+			f, err := searchParamNumber(rangeFilterKey, rangeFilter)
+			if err != nil {
+				return err
+			}
+			if queryInfo.GetMetricType() == "" {
+				return merr.WrapErrParameterInvalidMsg("metric type is required by range search")
+			}
+			if metric.PositivelyRelated(queryInfo.GetMetricType()) && f <= r {
+				return merr.WrapErrParameterInvalidMsg("%s must be greater than %s for metric %s", rangeFilterKey, radiusKey, queryInfo.GetMetricType())
+			}
+			if !metric.PositivelyRelated(queryInfo.GetMetricType()) && f >= r {
+				return merr.WrapErrParameterInvalidMsg("%s must be less than %s for metric %s", rangeFilterKey, radiusKey, queryInfo.GetMetricType())
+			}
+		}
+	}
+
+	if hints, ok := params[searchHintsKey]; ok {
+		value, ok := hints.(string)
+		if !ok {
+			return merr.WrapErrParameterInvalidMsg("search param %s must be a string, got %v", searchHintsKey, hints)
+		}
+		supported := false
+		for _, hint := range searchHints {
+			supported = supported || hint == value
+		}
+		if !supported {
+			return merr.WrapErrParameterInvalidMsg("hints %s are not supported by search", value)
+		}
+		queryInfo.Hints = value
+		delete(params, searchHintsKey)
+	}
+	if value, ok := params[filterBudgetKey]; ok {
+		budget, err := parseFilterBudget(value)
+		if err != nil {
+			return err
+		}
+		params[filterBudgetKey] = budget.params()
+	}
+
+	searchParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	queryInfo.SearchParams = string(searchParams)
+	return nil
+}
+
+func searchParamNumber(key string, value interface{}) (float64, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, merr.WrapErrParameterInvalidMsg("search param %s must be a number, got %v", key, value)
+	}
+	f, err := number.Float64()
+	if err != nil {
+		return 0, merr.WrapErrParameterInvalidMsg("search param %s must be a number, got %v", key, value)
+	}
+	return f, nil
+}
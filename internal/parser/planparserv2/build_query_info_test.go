@@ -0,0 +1,57 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+func TestBuildQueryInfo(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	queryInfo, err := BuildQueryInfo(schema, "FloatVectorField", 10, "IP",
+		`{"nprobe": 16, "level": 2, "radius": 0.5, "range_filter": 0.9, "hints": "iterative_filter"}`, WithTopKLimit(100))
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), queryInfo.GetTopk())
+	assert.Equal(t, "IP", queryInfo.GetMetricType())
+	assert.Equal(t, "iterative_filter", queryInfo.GetHints())
+	assert.Equal(t, int64(-1), queryInfo.GetGroupByFieldId())
+	assert.JSONEq(t, `{"nprobe": 16, "level": 2, "radius": 0.5, "range_filter": 0.9}`, queryInfo.GetSearchParams())
+
+	queryInfo, err = BuildQueryInfo(schema, "FloatVectorField", 10, "L2", `{"ef": 64, "radius": 2, "range_filter": 1}`)
+	require.NoError(t, err)
+	assert.Empty(t, queryInfo.GetHints())
+
+	queryInfo, err = BuildQueryInfo(schema, "FloatVectorField", 10, "", ``)
+	require.NoError(t, err)
+	assert.Empty(t, queryInfo.GetSearchParams())
+
+	for _, c := range []struct {
+		field      string
+		topK       int64
+		metricType string
+		params     string
+	}{
+		{"FloatVectorField", 1000, "L2", `{}`},
+		{"FloatVectorField", 10, "HAMMING", `{}`},
+		{"FloatVectorField", 10, "L2", `{`},
+		{"FloatVectorField", 10, "L2", `{"nprobe": 0}`},
+		{"FloatVectorField", 10, "L2", `{"nprobe": 1.5}`},
+		{"FloatVectorField", 10, "L2", `{"ef": "64"}`},
+		{"FloatVectorField", 10, "L2", `{"range_filter": 1}`},
+		{"FloatVectorField", 10, "L2", `{"radius": 1, "range_filter": 2}`},
+		{"FloatVectorField", 10, "IP", `{"radius": 1, "range_filter": 0.5}`},
+		{"FloatVectorField", 10, "", `{"radius": 1, "range_filter": 0.5}`},
+		{"FloatVectorField", 10, "L2", `{"hints": "no_such_hint"}`},
+		{"FloatVectorField", 10, "L2", `{"hints": 1}`},
+		{"Int64Field", 10, "L2", `{}`},
+	} {
+		_, err := BuildQueryInfo(schema, c.field, c.topK, c.metricType, c.params, WithTopKLimit(100))
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid, c.params)
+	}
+	_, err = BuildQueryInfo(schema, "NotExistField", 10, "L2", `{}`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package planparserv2
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// ParserCapabilities describes what expressions the parser accepts, so that clients can enable their features
+// dynamically instead of guessing them from the server version.
+type ParserCapabilities struct {
+	// Operators are the supported operators, in their lower case spelling.
+	Operators []string
+	// Functions are the supported functions, like "array_contains" or "text_match".
+	Functions []string
+	// LiteralForms are the supported forms of literals, like "hex_integer" or "template".
+	LiteralForms []string
+	// DisabledOperators are the operators disabled by the deployment, named like the features of ValidateExpr.
+	DisabledOperators []string
+	Limits            ParserLimits
+}
+
+// ParserLimits are the limits of the parser, 0 means unlimited.
+type ParserLimits struct {
+	// MaxPlanSize is the maximum serialized size of plans in bytes.
+	MaxPlanSize int64
+	// TopKLimit is the maximum topk of searches, offset included.
+	TopKLimit int64
+	// MaxMacroDepth is the maximum depth of nested macros.
+	MaxMacroDepth int
+}
+
+var (
+	supportedOperators = []string{
+		"==", "!=", "<", "<=", ">", ">=", "in", "not in", "like",
+		"and", "or", "not", "+", "-", "*", "/", "%", "**",
+		"is null", "is not null", "exists",
+	}
+	supportedFunctions = []string{
+		"json_contains", "json_contains_all", "json_contains_any",
+		"array_contains", "array_contains_all", "array_contains_any", "array_length",
+		"text_match", "phrase_match", "random_sample", queryStringFunctionName, primaryKeyFunctionName,
+		intervalFunctionName,
+	}
+	supportedLiteralForms = []string{
+		"integer", "hex_integer", "octal_integer", "binary_integer", "float", "hex_float",
+		"string", "boolean", "array", "null", "template", "timestamp",
+	}
+)
+
+// Capabilities returns the capabilities of the parser for the given options, such as the functions
+// enabled by WithSystemFields or WithPartitionTargets, and the limits set by WithMaxPlanSize.
+// The operators disabled by collection properties aren't included.
+func Capabilities(opts ...ParseOption) *ParserCapabilities {
+	options := newParseOptions(opts...)
+	functions := append([]string{}, supportedFunctions...)
+	if options.systemFields {
+		functions = append(functions, lo.Keys(systemFieldFunctions)...)
+	}
+	if options.requestContext != nil {
+		functions = append(functions, lo.Keys(contextFunctions)...)
+		functions = append(functions, nowFunctionName)
+	}
+	if options.partitionTargets != nil {
+		functions = append(functions, partitionFunctionName)
+	}
+	if options.valueSetRefs && valueSetResolver.Load() != nil {
+		functions = append(functions, valueSetRefFunctionName)
+	}
+	sort.Strings(functions)
+
+	disabled := lo.Uniq(lo.Compact(lo.Map(options.disabledOperators, func(operator string, _ int) string {
+		return strings.ToLower(strings.TrimSpace(operator))
+	})))
+	sort.Strings(disabled)
+	return &ParserCapabilities{
+		Operators:         append([]string{}, supportedOperators...),
+		Functions:         functions,
+		LiteralForms:      append([]string{}, supportedLiteralForms...),
+		DisabledOperators: disabled,
+		Limits: ParserLimits{
+			MaxPlanSize:   max(options.maxPlanSize, 0),
+			TopKLimit:     max(options.topKLimit, 0),
+			MaxMacroDepth: maxMacroDepth,
+		},
+	}
+}
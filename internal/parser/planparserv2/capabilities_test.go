@@ -0,0 +1,37 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilities(t *testing.T) {
+	caps := Capabilities()
+	assert.Contains(t, caps.Operators, "like")
+	assert.Contains(t, caps.Functions, "array_contains")
+	assert.Contains(t, caps.Functions, "pk")
+	assert.NotContains(t, caps.Functions, "timestamp")
+	assert.NotContains(t, caps.Functions, "current_user")
+	assert.NotContains(t, caps.Functions, "partition")
+	assert.Contains(t, caps.LiteralForms, "template")
+	assert.Empty(t, caps.DisabledOperators)
+	assert.Equal(t, int64(0), caps.Limits.MaxPlanSize)
+	assert.Equal(t, maxMacroDepth, caps.Limits.MaxMacroDepth)
+
+	var targets []string
+	caps = Capabilities(WithSystemFields(true), WithRequestContext(RequestContext{}), WithPartitionTargets(&targets),
+		WithDisabledOperators(" LIKE", "like", "call"), WithMaxPlanSize(1024), WithTopKLimit(16384))
+	assert.Subset(t, caps.Functions, []string{"timestamp", "row_id", "current_user", "current_db", "request_time", "now", "partition"})
+	assert.IsNonDecreasing(t, caps.Functions)
+	assert.Equal(t, []string{"call", "like"}, caps.DisabledOperators)
+	assert.Equal(t, ParserLimits{MaxPlanSize: 1024, TopKLimit: 16384, MaxMacroDepth: maxMacroDepth}, caps.Limits)
+
+	// the advertised functions are accepted by the parser.
+	helper := newTestSchemaHelper(t)
+	for _, exprStr := range []string{`timestamp() > 1`, `current_user() == VarCharField`, `partition() in ["p"]`} {
+		_, err := ParseExpr(helper, exprStr, nil, WithSystemFields(true), WithRequestContext(RequestContext{User: "u"}), WithPartitionTargets(&targets))
+		require.NoError(t, err, exprStr)
+	}
+}
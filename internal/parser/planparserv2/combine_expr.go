@@ -0,0 +1,74 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// CombineExpr parses the extra expression only, and joins it to the already parsed base expression with the logical
+// op, so that layered filters, like a system predicate and the predicate of the user, don't re-parse the concatenated
+// string. The base expression is checked against the schema, and is copied instead of being modified.
+func CombineExpr(schema *typeutil.SchemaHelper, baseExpr *planpb.Expr, extraExprStr string, op planpb.BinaryExpr_BinaryOp,
+	exprTemplateValues map[string]*schemapb.TemplateValue, opts ...ParseOption,
+) (*planpb.Expr, error) {
+	if op != planpb.BinaryExpr_LogicalAnd && op != planpb.BinaryExpr_LogicalOr {
+		return nil, fmt.Errorf("invalid logical op to combine expressions: %s", op.String())
+	}
+	if baseExpr != nil {
+		if err := checkPredicate(schema, baseExpr); err != nil {
+			return nil, fmt.Errorf("invalid base expression: %w", err)
+		}
+	}
+	if isEmptyExpression(extraExprStr) {
+		if baseExpr == nil {
+			return alwaysTrueExpr(), nil
+		}
+		return proto.Clone(baseExpr).(*planpb.Expr), nil
+	}
+	extra, err := ParseExpr(schema, extraExprStr, exprTemplateValues, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if baseExpr == nil {
+		return extra, nil
+	}
+	if isAlwaysTrueExpr(baseExpr) {
+		if op == planpb.BinaryExpr_LogicalAnd {
+			return extra, nil
+		}
+		return alwaysTrueExpr(), nil
+	}
+	return &planpb.Expr{
+		Expr: &planpb.Expr_BinaryExpr{
+			BinaryExpr: &planpb.BinaryExpr{
+				Left:  proto.Clone(baseExpr).(*planpb.Expr),
+				Right: extra,
+				Op:    op,
+			},
+		},
+	}, nil
+}
+
+// checkPredicate checks that the parsed expression is a predicate on the fields of the schema.
+func checkPredicate(schema *typeutil.SchemaHelper, expr *planpb.Expr) error {
+	switch realExpr := expr.GetExpr().(type) {
+	case nil:
+		return fmt.Errorf("empty expression")
+	case *planpb.Expr_ValueExpr:
+		if !IsBool(realExpr.ValueExpr.GetValue()) {
+			return fmt.Errorf("value %s is not a predicate", realExpr.ValueExpr.GetValue().String())
+		}
+	case *planpb.Expr_ColumnExpr:
+		if realExpr.ColumnExpr.GetInfo().GetDataType() != schemapb.DataType_Bool {
+			return fmt.Errorf("column of type %s is not a predicate", realExpr.ColumnExpr.GetInfo().GetDataType())
+		}
+	case *planpb.Expr_BinaryArithExpr:
+		return fmt.Errorf("arithmetic expression is not a predicate")
+	}
+	return newExprReportCollector(schema, &ExprReport{}).collect(expr)
+}
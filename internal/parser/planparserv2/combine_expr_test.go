@@ -0,0 +1,84 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestCombineExpr(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	base, err := ParseExpr(schema, `Int64Field > 10`, nil)
+	require.NoError(t, err)
+	baseCopy := proto.Clone(base)
+
+	for _, op := range []planpb.BinaryExpr_BinaryOp{planpb.BinaryExpr_LogicalAnd, planpb.BinaryExpr_LogicalOr} {
+		combined, err := CombineExpr(schema, base, `VarCharField == "a" or FloatField < 1.5`, op, nil)
+		require.NoError(t, err)
+		logical := "and"
+		if op == planpb.BinaryExpr_LogicalOr {
+			logical = "or"
+		}
+		expected, err := ParseExpr(schema, `(Int64Field > 10) `+logical+` (VarCharField == "a" or FloatField < 1.5)`, nil)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(expected, combined), op.String())
+	}
+	assert.True(t, proto.Equal(baseCopy, base))
+
+	combined, err := CombineExpr(schema, base, `Int32Field == {value}`, planpb.BinaryExpr_LogicalAnd,
+		map[string]*schemapb.TemplateValue{"value": generateTemplateValue(schemapb.DataType_Int64, int64(1))})
+	require.NoError(t, err)
+	assert.NotNil(t, combined.GetBinaryExpr().GetRight().GetUnaryRangeExpr())
+
+	combined, err = CombineExpr(schema, base, "  ", planpb.BinaryExpr_LogicalAnd, nil)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(base, combined))
+
+	combined, err = CombineExpr(schema, nil, `Int64Field > 1`, planpb.BinaryExpr_LogicalAnd, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, combined.GetUnaryRangeExpr())
+
+	combined, err = CombineExpr(schema, nil, "", planpb.BinaryExpr_LogicalAnd, nil)
+	require.NoError(t, err)
+	assert.True(t, isAlwaysTrueExpr(combined))
+
+	combined, err = CombineExpr(schema, alwaysTrueExpr(), `Int64Field > 1`, planpb.BinaryExpr_LogicalAnd, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, combined.GetUnaryRangeExpr())
+
+	combined, err = CombineExpr(schema, alwaysTrueExpr(), `Int64Field > 1`, planpb.BinaryExpr_LogicalOr, nil)
+	require.NoError(t, err)
+	assert.True(t, isAlwaysTrueExpr(combined))
+}
+
+func TestCombineExpr_Invalid(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	base, err := ParseExpr(schema, `Int64Field > 10`, nil)
+	require.NoError(t, err)
+
+	_, err = CombineExpr(schema, base, `Int64Field > 1`, planpb.BinaryExpr_Invalid, nil)
+	assert.Error(t, err)
+
+	_, err = CombineExpr(schema, base, `Int64Field + 1`, planpb.BinaryExpr_LogicalAnd, nil)
+	assert.Error(t, err)
+
+	_, err = CombineExpr(schema, base, `VarCharField > 1`, planpb.BinaryExpr_LogicalAnd, nil)
+	assert.Error(t, err)
+
+	_, err = CombineExpr(schema, &planpb.Expr{}, `Int64Field > 1`, planpb.BinaryExpr_LogicalAnd, nil)
+	assert.Error(t, err)
+
+	arith := &planpb.Expr{Expr: &planpb.Expr_BinaryArithExpr{BinaryArithExpr: &planpb.BinaryArithExpr{}}}
+	_, err = CombineExpr(schema, arith, `Int64Field > 1`, planpb.BinaryExpr_LogicalAnd, nil)
+	assert.Error(t, err)
+
+	unknown := proto.Clone(base).(*planpb.Expr)
+	unknown.GetUnaryRangeExpr().GetColumnInfo().FieldId = 999
+	_, err = CombineExpr(schema, unknown, `Int64Field > 1`, planpb.BinaryExpr_LogicalAnd, nil)
+	assert.Error(t, err)
+}
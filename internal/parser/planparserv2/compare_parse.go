@@ -0,0 +1,156 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// PlanComparison is the result of CompareParse.
+type PlanComparison struct {
+	// Legacy is the plan built by the visitor alone, without the rewrites of the plans.
+	Legacy *planpb.Expr
+	// Current is the plan built by ParseExpr.
+	Current     *planpb.Expr
+	Differences []*PlanDifference
+}
+
+// Identical returns true if both plans are the same.
+func (c *PlanComparison) Identical() bool {
+	return len(c.Differences) == 0
+}
+
+// PlanDifference is a node of the plans which differs, like "binary_expr.left.term_expr.values[1]".
+type PlanDifference struct {
+	Path    string
+	Legacy  string
+	Current string
+}
+
+func (d *PlanDifference) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Path, d.Legacy, d.Current)
+}
+
+// CompareParse parses the expression like the legacy parser, which only translated the syntax tree, and like
+// the current one, which rewrites the plans: term values are sorted and deduplicated, contradictions collapsed,
+// conjunctions reordered and so on. It reports where the plans differ, so that operators can check that upgrading
+// doesn't change the semantics of their stored expressions. Audit hooks are not called.
+func CompareParse(exprStr string, schema *typeutil.SchemaHelper, opts ...ParseOption) (*PlanComparison, error) {
+	legacy, err := parseExpr(schema, exprStr, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	current, err := ParseExpr(schema, exprStr, nil, append(opts, func(options *parseOptions) {
+		options.skipAudit = true
+	})...)
+	if err != nil {
+		return nil, err
+	}
+	comparison := &PlanComparison{Legacy: legacy, Current: current}
+	diffMessages("", legacy.ProtoReflect(), current.ProtoReflect(), &comparison.Differences)
+	return comparison, nil
+}
+
+func diffMessages(path string, legacy, current protoreflect.Message, diffs *[]*PlanDifference) {
+	fields := legacy.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		fieldPath := joinPlanPath(path, string(field.Name()))
+		hasLegacy, hasCurrent := legacy.Has(field), current.Has(field)
+		if !hasLegacy && !hasCurrent {
+			continue
+		}
+		if hasLegacy != hasCurrent {
+			*diffs = append(*diffs, &PlanDifference{
+				Path:    fieldPath,
+				Legacy:  formatPlanField(legacy, field),
+				Current: formatPlanField(current, field),
+			})
+			continue
+		}
+		switch {
+		case field.IsList():
+			legacyList, currentList := legacy.Get(field).List(), current.Get(field).List()
+			for j := 0; j < max(legacyList.Len(), currentList.Len()); j++ {
+				elementPath := fmt.Sprintf("%s[%d]", fieldPath, j)
+				if j >= legacyList.Len() || j >= currentList.Len() {
+					*diffs = append(*diffs, &PlanDifference{
+						Path:    elementPath,
+						Legacy:  formatPlanListElement(legacyList, j, field),
+						Current: formatPlanListElement(currentList, j, field),
+					})
+				} else if field.Message() != nil {
+					diffMessages(elementPath, legacyList.Get(j).Message(), currentList.Get(j).Message(), diffs)
+				} else if !legacyList.Get(j).Equal(currentList.Get(j)) {
+					*diffs = append(*diffs, &PlanDifference{
+						Path:    elementPath,
+						Legacy:  formatPlanValue(legacyList.Get(j), field),
+						Current: formatPlanValue(currentList.Get(j), field),
+					})
+				}
+			}
+		case field.IsMap():
+			if !legacy.Get(field).Equal(current.Get(field)) {
+				*diffs = append(*diffs, &PlanDifference{
+					Path:    fieldPath,
+					Legacy:  formatPlanField(legacy, field),
+					Current: formatPlanField(current, field),
+				})
+			}
+		case field.Message() != nil:
+			diffMessages(fieldPath, legacy.Get(field).Message(), current.Get(field).Message(), diffs)
+		default:
+			if !legacy.Get(field).Equal(current.Get(field)) {
+				*diffs = append(*diffs, &PlanDifference{
+					Path:    fieldPath,
+					Legacy:  formatPlanField(legacy, field),
+					Current: formatPlanField(current, field),
+				})
+			}
+		}
+	}
+}
+
+func joinPlanPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func formatPlanField(message protoreflect.Message, field protoreflect.FieldDescriptor) string {
+	if !message.Has(field) {
+		return "<none>"
+	}
+	if field.IsList() || field.IsMap() {
+		return fmt.Sprint(message.Get(field))
+	}
+	return formatPlanValue(message.Get(field), field)
+}
+
+func formatPlanListElement(list protoreflect.List, i int, field protoreflect.FieldDescriptor) string {
+	if i >= list.Len() {
+		return "<none>"
+	}
+	return formatPlanValue(list.Get(i), field)
+}
+
+func formatPlanValue(value protoreflect.Value, field protoreflect.FieldDescriptor) string {
+	switch {
+	case field.Message() != nil:
+		return prototext.MarshalOptions{}.Format(value.Message().Interface())
+	case field.Enum() != nil:
+		if enum := field.Enum().Values().ByNumber(value.Enum()); enum != nil {
+			return string(enum.Name())
+		}
+		return fmt.Sprint(value.Enum())
+	case field.Kind() == protoreflect.StringKind:
+		return fmt.Sprintf("%q", value.String())
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
@@ -0,0 +1,52 @@
+package planparserv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareParse(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	comparison, err := CompareParse(`Int64Field > 1`, helper)
+	require.NoError(t, err)
+	assert.True(t, comparison.Identical())
+
+	comparison, err = CompareParse(`Int64Field in [3, 1, 3]`, helper)
+	require.NoError(t, err)
+	require.False(t, comparison.Identical())
+	assert.Equal(t, "term_expr.values[0].int64_val", comparison.Differences[0].Path)
+	assert.Equal(t, "3", comparison.Differences[0].Legacy)
+	assert.Equal(t, "1", comparison.Differences[0].Current)
+	assert.Equal(t, "term_expr.values[2]", comparison.Differences[len(comparison.Differences)-1].Path)
+	assert.Equal(t, "<none>", comparison.Differences[len(comparison.Differences)-1].Current)
+
+	comparison, err = CompareParse(`Int64Field > 5 and Int64Field < 2`, helper)
+	require.NoError(t, err)
+	require.False(t, comparison.Identical())
+	paths := make(map[string]*PlanDifference)
+	for _, diff := range comparison.Differences {
+		paths[diff.Path] = diff
+	}
+	require.Contains(t, paths, "binary_expr")
+	assert.Equal(t, "<none>", paths["binary_expr"].Current)
+	require.Contains(t, paths, "unary_expr")
+	assert.Equal(t, "<none>", paths["unary_expr"].Legacy)
+	assert.True(t, IsTriviallyFalse(comparison.Current))
+
+	_, err = CompareParse(`Int64Field >`, helper)
+	assert.Error(t, err)
+
+	// dry runs are not audited.
+	audited := false
+	SetAuditHook(func(ctx context.Context, event *AuditEvent) {
+		audited = true
+	})
+	defer SetAuditHook(nil)
+	_, err = CompareParse(`Int64Field > 1`, helper)
+	require.NoError(t, err)
+	assert.False(t, audited)
+}
@@ -0,0 +1,192 @@
+package planparserv2
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// CompiledFilter is a filter expression parsed once and applied to rows with EvalExpr, such as by change stream
+// subscriptions filtering the insert records with the same filter language as queries.
+type CompiledFilter struct {
+	exprStr string
+	expr    *planpb.Expr
+	// patterns are the like patterns of the filter compiled once, instead of being looked up for every row.
+	patterns map[string]*regexp.Regexp
+}
+
+// CompileFilter parses the filter like ParseExpr, and rejects the expressions which can't be evaluated without
+// a query node, so that they fail when the filter is compiled instead of on the first record.
+func CompileFilter(schema *typeutil.SchemaHelper, exprStr string, exprTemplateValues map[string]*schemapb.TemplateValue, opts ...ParseOption) (*CompiledFilter, error) {
+	expr, err := ParseExpr(schema, exprStr, exprTemplateValues, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEvaluable(expr); err != nil {
+		return nil, fmt.Errorf("cannot compile filter: %s, error: %w", displayExpr(exprStr), err)
+	}
+	patterns, err := compileLikePatterns(expr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile filter: %s, error: %w", displayExpr(exprStr), err)
+	}
+	return &CompiledFilter{exprStr: exprStr, expr: expr, patterns: patterns}, nil
+}
+
+// Expr returns the plan of the filter.
+func (f *CompiledFilter) Expr() *planpb.Expr {
+	return f.expr
+}
+
+func (f *CompiledFilter) String() string {
+	return displayExpr(f.exprStr)
+}
+
+// Match returns whether the row, which maps the field ids to their values as described by EvalExpr, matches the filter.
+func (f *CompiledFilter) Match(row map[int64]interface{}) (bool, error) {
+	return evalRow(f.expr, row, f.patterns)
+}
+
+// MatchInsert returns which rows of the insert record match the filter.
+func (f *CompiledFilter) MatchInsert(insert *msgpb.InsertRequest) ([]bool, error) {
+	return f.MatchColumns(insert.GetFieldsData(), int(insert.GetNumRows()))
+}
+
+// MatchColumns returns which rows of the columnar field data match the filter. The data of nullable fields hold
+// a value for every row, whether it's valid or not, like the data of insert records.
+func (f *CompiledFilter) MatchColumns(fieldsData []*schemapb.FieldData, numRows int) ([]bool, error) {
+	matches := make([]bool, numRows)
+	row := make(map[int64]interface{}, len(fieldsData))
+	for i := 0; i < numRows; i++ {
+		for _, field := range fieldsData {
+			if typeutil.IsVectorType(field.GetType()) {
+				continue
+			}
+			value, err := fieldDataValue(field, i)
+			if err != nil {
+				return nil, err
+			}
+			row[field.GetFieldId()] = value
+		}
+		match, err := f.Match(row)
+		if err != nil {
+			return nil, fmt.Errorf("cannot filter row %d: %w", i, err)
+		}
+		matches[i] = match
+	}
+	return matches, nil
+}
+
+// fieldDataValue returns the value of the field data at the row, in the form expected by EvalExpr.
+func fieldDataValue(field *schemapb.FieldData, i int) (interface{}, error) {
+	if validData := field.GetValidData(); len(validData) > 0 {
+		if i >= len(validData) {
+			return nil, fmt.Errorf("field %s has %d rows, less than %d", field.GetFieldName(), len(validData), i+1)
+		}
+		if !validData[i] {
+			return nil, nil
+		}
+	}
+	var values interface{}
+	switch field.GetType() {
+	case schemapb.DataType_Bool:
+		values = field.GetScalars().GetBoolData().GetData()
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		values = field.GetScalars().GetIntData().GetData()
+	case schemapb.DataType_Int64:
+		values = field.GetScalars().GetLongData().GetData()
+	case schemapb.DataType_Float:
+		values = field.GetScalars().GetFloatData().GetData()
+	case schemapb.DataType_Double:
+		values = field.GetScalars().GetDoubleData().GetData()
+	case schemapb.DataType_String, schemapb.DataType_VarChar, schemapb.DataType_Text:
+		values = field.GetScalars().GetStringData().GetData()
+	case schemapb.DataType_JSON:
+		values = field.GetScalars().GetJsonData().GetData()
+	case schemapb.DataType_Array:
+		arrays := field.GetScalars().GetArrayData().GetData()
+		if i >= len(arrays) {
+			return nil, fmt.Errorf("field %s has %d rows, less than %d", field.GetFieldName(), len(arrays), i+1)
+		}
+		return scalarFieldValues(arrays[i])
+	default:
+		return nil, fmt.Errorf("field %s of type %s can't be filtered", field.GetFieldName(), field.GetType())
+	}
+	column, err := normalizeColumn(values)
+	if err != nil {
+		return nil, err
+	}
+	if i >= len(column) {
+		return nil, fmt.Errorf("field %s has %d rows, less than %d", field.GetFieldName(), len(column), i+1)
+	}
+	return column[i], nil
+}
+
+// normalizeColumn converts the typed slice of a column to a slice of values, json values are kept as raw bytes.
+func normalizeColumn(values interface{}) ([]interface{}, error) {
+	if jsons, ok := values.([][]byte); ok {
+		column := make([]interface{}, len(jsons))
+		for i, data := range jsons {
+			column[i] = data
+		}
+		return column, nil
+	}
+	column, err := normalizeRowValue(values)
+	if err != nil {
+		return nil, err
+	}
+	return column.([]interface{}), nil
+}
+
+// scalarFieldValues returns the elements of an array value.
+func scalarFieldValues(array *schemapb.ScalarField) (interface{}, error) {
+	switch data := array.GetData().(type) {
+	case *schemapb.ScalarField_BoolData:
+		return data.BoolData.GetData(), nil
+	case *schemapb.ScalarField_IntData:
+		return data.IntData.GetData(), nil
+	case *schemapb.ScalarField_LongData:
+		return data.LongData.GetData(), nil
+	case *schemapb.ScalarField_FloatData:
+		return data.FloatData.GetData(), nil
+	case *schemapb.ScalarField_DoubleData:
+		return data.DoubleData.GetData(), nil
+	case *schemapb.ScalarField_StringData:
+		return data.StringData.GetData(), nil
+	case nil:
+		return []interface{}{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported array data %T", data)
+	}
+}
+
+// checkEvaluable returns an error if EvalExpr can't evaluate the expression.
+func checkEvaluable(expr *planpb.Expr) error {
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryExpr:
+		return checkEvaluable(realExpr.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		if err := checkEvaluable(realExpr.BinaryExpr.GetLeft()); err != nil {
+			return err
+		}
+		return checkEvaluable(realExpr.BinaryExpr.GetRight())
+	case *planpb.Expr_UnaryRangeExpr:
+		switch op := realExpr.UnaryRangeExpr.GetOp(); op {
+		case planpb.OpType_TextMatch, planpb.OpType_PhraseMatch:
+			return fmt.Errorf("%s needs the analyzer of a query node", op.String())
+		}
+		return nil
+	case *planpb.Expr_RandomSampleExpr:
+		return fmt.Errorf("random_sample can't be evaluated on rows")
+	case *planpb.Expr_CallExpr:
+		if _, ok := rowFunctions[realExpr.CallExpr.GetFunctionName()]; !ok {
+			return fmt.Errorf("function %s can't be evaluated without a query node", realExpr.CallExpr.GetFunctionName())
+		}
+		return nil
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,91 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/testutils"
+)
+
+func TestCompiledFilter(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+	fieldID := func(name string) int64 {
+		field, err := helper.GetFieldFromName(name)
+		require.NoError(t, err)
+		return field.GetFieldID()
+	}
+
+	filter, err := CompileFilter(helper, `Int64Field > 1 and (JSONField["a"] == "x" or array_contains(ArrayField, 7)) and VarCharField != "skip"`, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, filter.Expr().GetBinaryExpr())
+
+	match, err := filter.Match(map[int64]interface{}{
+		fieldID("Int64Field"):   int64(2),
+		fieldID("JSONField"):    []byte(`{"a": "x"}`),
+		fieldID("ArrayField"):   []int64{},
+		fieldID("VarCharField"): "keep",
+	})
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	varChar := testutils.GenerateScalarFieldData(schemapb.DataType_VarChar, "VarCharField", 4)
+	varChar.GetScalars().GetStringData().Data = []string{"keep", "keep", "skip", "keep"}
+	varChar.ValidData = []bool{true, true, true, false}
+	varChar.FieldId = fieldID("VarCharField")
+	insert := &msgpb.InsertRequest{
+		NumRows: 4,
+		FieldsData: []*schemapb.FieldData{
+			{
+				Type: schemapb.DataType_Int64, FieldName: "Int64Field", FieldId: fieldID("Int64Field"),
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{2, 0, 2, 2}}},
+				}},
+			},
+			{
+				Type: schemapb.DataType_JSON, FieldName: "JSONField", FieldId: fieldID("JSONField"),
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_JsonData{JsonData: &schemapb.JSONArray{Data: [][]byte{
+						[]byte(`{"a": "y"}`), []byte(`{"a": "x"}`), []byte(`{"a": "x"}`), []byte(`{"a": "x"}`),
+					}}},
+				}},
+			},
+			{
+				Type: schemapb.DataType_Array, FieldName: "ArrayField", FieldId: fieldID("ArrayField"),
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_ArrayData{ArrayData: &schemapb.ArrayArray{
+						ElementType: schemapb.DataType_Int64,
+						Data: []*schemapb.ScalarField{
+							{Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{1, 7}}}},
+							{Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{}}}},
+							{Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{}}}},
+							{Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{}}}},
+						},
+					}},
+				}},
+			},
+			varChar,
+			testutils.GenerateVectorFieldData(schemapb.DataType_FloatVector, "FloatVectorField", 4, 8),
+		},
+	}
+	matches, err := filter.MatchInsert(insert)
+	require.NoError(t, err)
+	// the last row is null, which isn't different from "skip".
+	assert.Equal(t, []bool{true, false, false, false}, matches)
+
+	_, err = filter.MatchColumns(insert.GetFieldsData()[:1], 4)
+	assert.ErrorContains(t, err, "no value")
+	_, err = filter.MatchColumns(insert.GetFieldsData(), 5)
+	assert.Error(t, err)
+
+	for _, exprStr := range []string{
+		`Int64Field > 1 and random_sample(0.5)`,
+		`text_match(VarCharField, "milvus")`,
+	} {
+		_, err = CompileFilter(helper, exprStr, nil)
+		assert.Error(t, err, exprStr)
+	}
+}
@@ -0,0 +1,101 @@
+package planparserv2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// ConstraintKeyPrefix is the prefix of the collection properties defining constraints. The property
+// `expr.constraint.valid_price` defines a filter like `price >= 0 and not empty(name)`, which the rows
+// inserted or imported into the collection must match.
+const ConstraintKeyPrefix = "expr.constraint."
+
+// Constraint is a named filter the rows of a collection must match.
+type Constraint struct {
+	Name   string
+	Filter *CompiledFilter
+}
+
+// Constraints are the constraints of a collection, sorted by name.
+type Constraints []*Constraint
+
+func constraintsOf(properties []*commonpb.KeyValuePair) map[string]string {
+	constraints := make(map[string]string)
+	for _, kv := range properties {
+		if name, ok := strings.CutPrefix(kv.GetKey(), ConstraintKeyPrefix); ok {
+			constraints[name] = kv.GetValue()
+		}
+	}
+	return constraints
+}
+
+// DefinesConstraints returns whether the properties define any constraint.
+func DefinesConstraints(properties []*commonpb.KeyValuePair) bool {
+	return len(constraintsOf(properties)) > 0
+}
+
+// ValidateConstraints checks the constraints defined by the properties against the schema, before they're set.
+func ValidateConstraints(schema *typeutil.SchemaHelper, properties []*commonpb.KeyValuePair) error {
+	_, err := compileConstraints(schema, constraintsOf(properties))
+	return err
+}
+
+// CompileConstraints compiles the constraints defined by the properties of the collection, nil if there are none.
+func CompileConstraints(schema *typeutil.SchemaHelper) (Constraints, error) {
+	return compileConstraints(schema, constraintsOf(schema.GetSchema().GetProperties()))
+}
+
+func compileConstraints(schema *typeutil.SchemaHelper, defined map[string]string) (Constraints, error) {
+	var constraints Constraints
+	for name, exprStr := range defined {
+		if !hintNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid constraint name %s", name)
+		}
+		filter, err := CompileFilter(schema, exprStr, nil, asSystemFilter())
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %s: %w", name, err)
+		}
+		constraints = append(constraints, &Constraint{Name: name, Filter: filter})
+	}
+	sort.Slice(constraints, func(i, j int) bool {
+		return constraints[i].Name < constraints[j].Name
+	})
+	return constraints, nil
+}
+
+// CheckRow returns an error for the first constraint the row doesn't match, the row maps the field ids to
+// their values as described by EvalExpr.
+func (c Constraints) CheckRow(row map[int64]interface{}) error {
+	for _, constraint := range c {
+		match, err := constraint.Filter.Match(row)
+		if err != nil {
+			return fmt.Errorf("cannot check constraint %s: %w", constraint.Name, err)
+		}
+		if !match {
+			return merr.WrapErrParameterInvalidMsg("row violates constraint %s: %s", constraint.Name, constraint.Filter)
+		}
+	}
+	return nil
+}
+
+// CheckColumns returns an error for the first row of the columnar field data which doesn't match a constraint.
+func (c Constraints) CheckColumns(fieldsData []*schemapb.FieldData, numRows int) error {
+	for _, constraint := range c {
+		matches, err := constraint.Filter.MatchColumns(fieldsData, numRows)
+		if err != nil {
+			return fmt.Errorf("cannot check constraint %s: %w", constraint.Name, err)
+		}
+		for i, match := range matches {
+			if !match {
+				return merr.WrapErrParameterInvalidMsg("row %d violates constraint %s: %s", i, constraint.Name, constraint.Filter)
+			}
+		}
+	}
+	return nil
+}
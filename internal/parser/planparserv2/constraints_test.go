@@ -0,0 +1,107 @@
+package planparserv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestConstraints(t *testing.T) {
+	schema := newTestSchema(true)
+	schema.Properties = []*commonpb.KeyValuePair{
+		{Key: ConstraintKeyPrefix + "valid_name", Value: `not empty(VarCharField) and not starts_with(VarCharField, "_")`},
+		{Key: ConstraintKeyPrefix + "positive", Value: `Int64Field >= 0`},
+		{Key: "other", Value: "1"},
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+	assert.True(t, DefinesConstraints(schema.GetProperties()))
+	assert.False(t, DefinesConstraints(schema.GetProperties()[2:]))
+
+	constraints, err := CompileConstraints(helper)
+	require.NoError(t, err)
+	require.Len(t, constraints, 2)
+	assert.Equal(t, "positive", constraints[0].Name)
+
+	int64Field, _ := helper.GetFieldFromName("Int64Field")
+	varCharField, _ := helper.GetFieldFromName("VarCharField")
+	assert.NoError(t, constraints.CheckRow(map[int64]interface{}{int64Field.GetFieldID(): int64(1), varCharField.GetFieldID(): "a"}))
+	err = constraints.CheckRow(map[int64]interface{}{int64Field.GetFieldID(): int64(-1), varCharField.GetFieldID(): "a"})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	assert.ErrorContains(t, err, "positive")
+	assert.ErrorContains(t, constraints.CheckRow(map[int64]interface{}{int64Field.GetFieldID(): int64(1), varCharField.GetFieldID(): ""}), "valid_name")
+	assert.ErrorContains(t, constraints.CheckRow(map[int64]interface{}{int64Field.GetFieldID(): int64(1), varCharField.GetFieldID(): "_a"}), "valid_name")
+
+	fieldsData := []*schemapb.FieldData{
+		{
+			Type: schemapb.DataType_Int64, FieldId: int64Field.GetFieldID(),
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{1, 2}}},
+			}},
+		},
+		{
+			Type: schemapb.DataType_VarChar, FieldId: varCharField.GetFieldID(),
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"a", ""}}},
+			}},
+		},
+	}
+	assert.NoError(t, constraints.CheckColumns(fieldsData, 1))
+	assert.ErrorContains(t, constraints.CheckColumns(fieldsData, 2), "row 1 violates constraint valid_name")
+
+	for _, properties := range [][]*commonpb.KeyValuePair{
+		{{Key: ConstraintKeyPrefix + "bad name", Value: `Int64Field > 0`}},
+		{{Key: ConstraintKeyPrefix + "unknown", Value: `Int64Field >`}},
+		{{Key: ConstraintKeyPrefix + "sample", Value: `random_sample(0.5)`}},
+		{{Key: ConstraintKeyPrefix + "not_predicate", Value: `Int64Field + 1`}},
+	} {
+		assert.Error(t, ValidateConstraints(helper, properties), properties[0].GetValue())
+	}
+	assert.NoError(t, ValidateConstraints(helper, []*commonpb.KeyValuePair{{Key: ConstraintKeyPrefix + "a", Value: `Int64Field in [1, 2]`}}))
+
+	constraints, err = CompileConstraints(newTestSchemaHelper(t))
+	require.NoError(t, err)
+	assert.Nil(t, constraints)
+	assert.NoError(t, constraints.CheckRow(nil))
+}
+
+func TestConstraintsSkipRequestHooks(t *testing.T) {
+	schema := newTestSchema(true)
+	schema.Properties = []*commonpb.KeyValuePair{
+		{Key: ConstraintKeyPrefix + "positive", Value: `Int64Field >= 0`},
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+	helper = helper.WithEpoch("db", 1, 1)
+
+	RegisterRowLevelPolicy(1, "", `Int64Field > 100`)
+	defer UnregisterRowLevelPolicy(1, "")
+	RegisterExprInterceptor("deny", ExprInterceptorFunc(func(ctx context.Context, schema *typeutil.SchemaHelper, expr *planpb.Expr) (*planpb.Expr, error) {
+		return nil, errors.New("denied by interceptor")
+	}))
+	defer UnregisterExprInterceptor("deny")
+	SetFieldAuthorizer(func(ctx context.Context, collectionID int64, fieldID int64) error {
+		return merr.WrapErrPrivilegeNotPermitted("field %d", fieldID)
+	})
+	defer SetFieldAuthorizer(nil)
+	audited := 0
+	SetAuditHook(func(ctx context.Context, event *AuditEvent) {
+		audited++
+	})
+	defer SetAuditHook(nil)
+
+	constraints, err := CompileConstraints(helper)
+	require.NoError(t, err)
+	int64Field, _ := helper.GetFieldFromName("Int64Field")
+	assert.NoError(t, constraints.CheckRow(map[int64]interface{}{int64Field.GetFieldID(): int64(1)}))
+	assert.Zero(t, audited)
+}
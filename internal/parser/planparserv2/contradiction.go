@@ -0,0 +1,216 @@
+package planparserv2
+
+import (
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func alwaysFalseExpr() *planpb.Expr {
+	return &planpb.Expr{
+		Expr: &planpb.Expr_UnaryExpr{
+			UnaryExpr: &planpb.UnaryExpr{
+				Op:    planpb.UnaryExpr_Not,
+				Child: alwaysTrueExpr(),
+			},
+		},
+	}
+}
+
+// collapseContradictions replaces the conjunctions which can't be satisfied by any value of a column,
+// such as `a > 5 and a in [1, 2]` or `s like "x%" and s like "y%"`, with an always false expression,
+// so that segments are not scanned for them.
+func collapseContradictions(expr *planpb.Expr) *planpb.Expr {
+	return collapseContradictionsWithin(expr, nil)
+}
+
+// collapseContradictionsWithin collapses the contradictions like collapseContradictions, the values of the
+// columns being restricted to the declared bounds, so that `age > 500` can't be satisfied if age is at most 150.
+func collapseContradictionsWithin(expr *planpb.Expr, bounds map[int64]*fieldRange) *planpb.Expr {
+	binary := expr.GetBinaryExpr()
+	if binary == nil {
+		if len(bounds) != 0 && addConstraint(boundConstraints(bounds), expr) {
+			return alwaysFalseExpr()
+		}
+		return expr
+	}
+	left, right := collapseContradictionsWithin(binary.GetLeft(), bounds), collapseContradictionsWithin(binary.GetRight(), bounds)
+	if binary.GetOp() == planpb.BinaryExpr_LogicalOr {
+		switch {
+		case IsTriviallyFalse(left):
+			return right
+		case IsTriviallyFalse(right):
+			return left
+		}
+	} else {
+		if IsTriviallyFalse(left) || IsTriviallyFalse(right) {
+			return alwaysFalseExpr()
+		}
+		constraints := boundConstraints(bounds)
+		for _, conjunct := range append(flattenConjunction(left), flattenConjunction(right)...) {
+			if addConstraint(constraints, conjunct) {
+				return alwaysFalseExpr()
+			}
+		}
+	}
+	if left == binary.GetLeft() && right == binary.GetRight() {
+		return expr
+	}
+	return &planpb.Expr{
+		Expr: &planpb.Expr_BinaryExpr{
+			BinaryExpr: &planpb.BinaryExpr{
+				Left:  left,
+				Right: right,
+				Op:    binary.GetOp(),
+			},
+		},
+	}
+}
+
+// boundConstraints returns the constraints of the columns with declared bounds.
+func boundConstraints(bounds map[int64]*fieldRange) map[int64]*columnConstraint {
+	constraints := make(map[int64]*columnConstraint, len(bounds))
+	for fieldID, bound := range bounds {
+		constraints[fieldID] = &columnConstraint{lower: bound.min, upper: bound.max, lowerInclusive: true, upperInclusive: true}
+	}
+	return constraints
+}
+
+func flattenConjunction(expr *planpb.Expr) []*planpb.Expr {
+	if binary := expr.GetBinaryExpr(); binary != nil && binary.GetOp() == planpb.BinaryExpr_LogicalAnd {
+		return append(flattenConjunction(binary.GetLeft()), flattenConjunction(binary.GetRight())...)
+	}
+	return []*planpb.Expr{expr}
+}
+
+// columnConstraint is the set of values of a column allowed by a conjunction.
+type columnConstraint struct {
+	lower, upper                   *planpb.GenericValue
+	lowerInclusive, upperInclusive bool
+	// values is nil if the column is not restricted to a list of values.
+	values   []*planpb.GenericValue
+	prefixes []string
+}
+
+// addConstraint adds the comparison to the constraint of its column, and returns true if it contradicts the others.
+// Only scalar fields are tracked, since the comparisons on JSON and arrays depend on the types of the values.
+func addConstraint(constraints map[int64]*columnConstraint, expr *planpb.Expr) bool {
+	var info *planpb.ColumnInfo
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryRangeExpr:
+		info = realExpr.UnaryRangeExpr.GetColumnInfo()
+	case *planpb.Expr_BinaryRangeExpr:
+		info = realExpr.BinaryRangeExpr.GetColumnInfo()
+	case *planpb.Expr_TermExpr:
+		info = realExpr.TermExpr.GetColumnInfo()
+	default:
+		return false
+	}
+	if len(info.GetNestedPath()) != 0 || info.GetDataType() == schemapb.DataType_JSON || info.GetDataType() == schemapb.DataType_Array {
+		return false
+	}
+	c, ok := constraints[info.GetFieldId()]
+	if !ok {
+		c = &columnConstraint{}
+		constraints[info.GetFieldId()] = c
+	}
+
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryRangeExpr:
+		value := realExpr.UnaryRangeExpr.GetValue()
+		switch realExpr.UnaryRangeExpr.GetOp() {
+		case planpb.OpType_Equal:
+			c.restrictValues([]*planpb.GenericValue{value})
+		case planpb.OpType_GreaterThan, planpb.OpType_GreaterEqual:
+			c.restrictLower(value, realExpr.UnaryRangeExpr.GetOp() == planpb.OpType_GreaterEqual)
+		case planpb.OpType_LessThan, planpb.OpType_LessEqual:
+			c.restrictUpper(value, realExpr.UnaryRangeExpr.GetOp() == planpb.OpType_LessEqual)
+		case planpb.OpType_PrefixMatch:
+			c.prefixes = append(c.prefixes, value.GetStringVal())
+		default:
+			return false
+		}
+	case *planpb.Expr_BinaryRangeExpr:
+		e := realExpr.BinaryRangeExpr
+		c.restrictLower(e.GetLowerValue(), e.GetLowerInclusive())
+		c.restrictUpper(e.GetUpperValue(), e.GetUpperInclusive())
+	case *planpb.Expr_TermExpr:
+		c.restrictValues(realExpr.TermExpr.GetValues())
+	}
+	return c.isEmpty()
+}
+
+func (c *columnConstraint) restrictValues(values []*planpb.GenericValue) {
+	if c.values == nil {
+		c.values = values
+		return
+	}
+	intersection := make([]*planpb.GenericValue, 0)
+	for _, value := range c.values {
+		for _, other := range values {
+			if valueIs(Equal(value, other)) {
+				intersection = append(intersection, value)
+				break
+			}
+		}
+	}
+	c.values = intersection
+}
+
+func (c *columnConstraint) restrictLower(value *planpb.GenericValue, inclusive bool) {
+	if c.lower == nil || valueIs(Greater(value, c.lower)) || (valueIs(Equal(value, c.lower)) && !inclusive) {
+		c.lower, c.lowerInclusive = value, inclusive
+	}
+}
+
+func (c *columnConstraint) restrictUpper(value *planpb.GenericValue, inclusive bool) {
+	if c.upper == nil || valueIs(Less(value, c.upper)) || (valueIs(Equal(value, c.upper)) && !inclusive) {
+		c.upper, c.upperInclusive = value, inclusive
+	}
+}
+
+func (c *columnConstraint) allows(value *planpb.GenericValue) bool {
+	if c.lower != nil && (valueIs(Less(value, c.lower)) || (!c.lowerInclusive && valueIs(Equal(value, c.lower)))) {
+		return false
+	}
+	if c.upper != nil && (valueIs(Greater(value, c.upper)) || (!c.upperInclusive && valueIs(Equal(value, c.upper)))) {
+		return false
+	}
+	for _, prefix := range c.prefixes {
+		if IsString(value) && !strings.HasPrefix(value.GetStringVal(), prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *columnConstraint) isEmpty() bool {
+	if c.values != nil {
+		for _, value := range c.values {
+			if c.allows(value) {
+				return false
+			}
+		}
+		return true
+	}
+	if c.lower != nil && c.upper != nil {
+		if valueIs(Less(c.upper, c.lower)) || (valueIs(Equal(c.upper, c.lower)) && !(c.lowerInclusive && c.upperInclusive)) {
+			return true
+		}
+	}
+	for i, prefix := range c.prefixes {
+		for _, other := range c.prefixes[i+1:] {
+			if !strings.HasPrefix(prefix, other) && !strings.HasPrefix(other, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// valueIs returns true if the result of a constant comparison is true, and false if it's false or the
+// values are not comparable.
+func valueIs(ret *ExprWithType) bool {
+	return ret != nil && ret.expr.GetValueExpr().GetValue().GetBoolVal()
+}
@@ -0,0 +1,52 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpr_Contradictions(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	for _, exprStr := range []string{
+		`Int64Field > 5 and Int64Field in [1, 2]`,
+		`Int64Field in [1, 2] and Int64Field == 3`,
+		`Int64Field > 5 and Int64Field < 3`,
+		`Int64Field >= 5 and Int64Field < 5`,
+		`Int64Field > 5 and Int32Field > 1 and Int64Field <= 5`,
+		`1 < Int64Field < 10 and Int64Field > 20`,
+		`DoubleField == 1.5 and DoubleField > 2`,
+		`VarCharField like "x%" and VarCharField like "y%"`,
+		`VarCharField like "ab%" and VarCharField in ["ac", "b"]`,
+		`(Int64Field > 5 and Int64Field < 3) and Int32Field > 1`,
+		`(Int64Field > 5 and Int64Field < 3) or (Int32Field in [1] and Int32Field in [2])`,
+	} {
+		expr, err := ParseExpr(schema, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		assert.True(t, IsTriviallyFalse(expr), exprStr)
+	}
+
+	for _, exprStr := range []string{
+		`Int64Field > 5 and Int64Field in [1, 6]`,
+		`Int64Field >= 5 and Int64Field <= 5`,
+		`Int64Field > 5 and Int32Field < 3`,
+		`Int64Field > 5 or Int64Field < 3`,
+		`VarCharField like "ab%" and VarCharField like "a%"`,
+		`VarCharField like "ab%" and VarCharField in ["abc"]`,
+		`VarCharField like "%x" and VarCharField like "%y"`,
+		`JSONField["a"] > 5 and JSONField["a"] < 3`,
+		`ArrayField[0] > 5 and ArrayField[0] < 3`,
+		`not (Int64Field > 5) and Int64Field > 5`,
+	} {
+		expr, err := ParseExpr(schema, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		assert.False(t, IsTriviallyFalse(expr), exprStr)
+	}
+
+	// contradicting branches of a disjunction are dropped.
+	expr, err := ParseExpr(schema, `(Int64Field > 5 and Int64Field < 3) or Int32Field > 1`, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+}
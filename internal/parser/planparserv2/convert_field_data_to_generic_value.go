@@ -134,6 +134,9 @@ func ConvertToGenericValue(templateName string, templateValue *schemapb.Template
 			},
 		}, nil
 	case *schemapb.TemplateValue_ArrayVal:
+		if isVectorTemplateValue(templateValue) {
+			return nil, fmt.Errorf("the value of template variable {%s} is a vector, which can't be used as a scalar", templateName)
+		}
 		return convertArrayValue(templateName, templateValue.GetArrayVal())
 	default:
 		return nil, fmt.Errorf("expression elements can only be scalars")
@@ -143,6 +146,10 @@ func ConvertToGenericValue(templateName string, templateValue *schemapb.Template
 func UnmarshalExpressionValues(input map[string]*schemapb.TemplateValue) (map[string]*planpb.GenericValue, error) {
 	result := make(map[string]*planpb.GenericValue, len(input))
 	for name, value := range input {
+		// vectors are taken by the vector expressions from the template values directly.
+		if isVectorTemplateValue(value) {
+			continue
+		}
 		rv, err := ConvertToGenericValue(name, value)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,132 @@
+package planparserv2
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+var defaultValueCompareFns = map[planpb.OpType]func(a, b *planpb.GenericValue) *ExprWithType{
+	planpb.OpType_GreaterThan:  Greater,
+	planpb.OpType_GreaterEqual: GreaterEqual,
+	planpb.OpType_LessThan:     Less,
+	planpb.OpType_LessEqual:    LessEqual,
+	planpb.OpType_Equal:        Equal,
+	planpb.OpType_NotEqual:     NotEqual,
+}
+
+// applyDefaultValues rewrites the comparisons on nullable fields with a default value, so that null rows
+// behave as if they held the default value. The result of comparing the default value is known at parse
+// time, so `a > 1` becomes `(a > 1 or a is null)` if it holds for the default value, otherwise
+// `(a > 1 and a is not null)`, and is evaluated consistently by segments with or without the column.
+func applyDefaultValues(schema *typeutil.SchemaHelper, expr *planpb.Expr) *planpb.Expr {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryExpr:
+		e.UnaryExpr.Child = applyDefaultValues(schema, e.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		e.BinaryExpr.Left = applyDefaultValues(schema, e.BinaryExpr.GetLeft())
+		e.BinaryExpr.Right = applyDefaultValues(schema, e.BinaryExpr.GetRight())
+	case *planpb.Expr_RandomSampleExpr:
+		if e.RandomSampleExpr.GetPredicate() != nil {
+			e.RandomSampleExpr.Predicate = applyDefaultValues(schema, e.RandomSampleExpr.GetPredicate())
+		}
+	case *planpb.Expr_UnaryRangeExpr:
+		defaultValue := getDefaultValue(schema, e.UnaryRangeExpr.GetColumnInfo())
+		if fn, ok := defaultValueCompareFns[e.UnaryRangeExpr.GetOp()]; ok && defaultValue != nil {
+			if matched, ok := evalDefaultValue(fn(defaultValue, e.UnaryRangeExpr.GetValue())); ok {
+				return withDefaultValue(expr, e.UnaryRangeExpr.GetColumnInfo(), matched)
+			}
+		}
+	case *planpb.Expr_BinaryRangeExpr:
+		defaultValue := getDefaultValue(schema, e.BinaryRangeExpr.GetColumnInfo())
+		if defaultValue == nil {
+			return expr
+		}
+		lowerFn, upperFn := Greater, Less
+		if e.BinaryRangeExpr.GetLowerInclusive() {
+			lowerFn = GreaterEqual
+		}
+		if e.BinaryRangeExpr.GetUpperInclusive() {
+			upperFn = LessEqual
+		}
+		lower, ok1 := evalDefaultValue(lowerFn(defaultValue, e.BinaryRangeExpr.GetLowerValue()))
+		upper, ok2 := evalDefaultValue(upperFn(defaultValue, e.BinaryRangeExpr.GetUpperValue()))
+		if ok1 && ok2 {
+			return withDefaultValue(expr, e.BinaryRangeExpr.GetColumnInfo(), lower && upper)
+		}
+	case *planpb.Expr_TermExpr:
+		defaultValue := getDefaultValue(schema, e.TermExpr.GetColumnInfo())
+		if defaultValue == nil {
+			return expr
+		}
+		matched := false
+		for _, value := range e.TermExpr.GetValues() {
+			equal, ok := evalDefaultValue(Equal(defaultValue, value))
+			if !ok {
+				return expr
+			}
+			matched = matched || equal
+		}
+		return withDefaultValue(expr, e.TermExpr.GetColumnInfo(), matched)
+	}
+	return expr
+}
+
+func getDefaultValue(schema *typeutil.SchemaHelper, info *planpb.ColumnInfo) *planpb.GenericValue {
+	if len(info.GetNestedPath()) != 0 {
+		return nil
+	}
+	field, err := schema.GetFieldFromID(info.GetFieldId())
+	if err != nil || !field.GetNullable() {
+		return nil
+	}
+	switch data := field.GetDefaultValue().GetData().(type) {
+	case *schemapb.ValueField_BoolData:
+		return NewBool(data.BoolData)
+	case *schemapb.ValueField_IntData:
+		return NewInt(int64(data.IntData))
+	case *schemapb.ValueField_LongData:
+		return NewInt(data.LongData)
+	case *schemapb.ValueField_FloatData:
+		return NewFloat(float64(data.FloatData))
+	case *schemapb.ValueField_DoubleData:
+		return NewFloat(data.DoubleData)
+	case *schemapb.ValueField_StringData:
+		return NewString(data.StringData)
+	default:
+		return nil
+	}
+}
+
+func evalDefaultValue(ret *ExprWithType) (bool, bool) {
+	if ret == nil {
+		return false, false
+	}
+	return ret.expr.GetValueExpr().GetValue().GetBoolVal(), true
+}
+
+func withDefaultValue(expr *planpb.Expr, info *planpb.ColumnInfo, matched bool) *planpb.Expr {
+	op, nullOp := planpb.BinaryExpr_LogicalAnd, planpb.NullExpr_IsNotNull
+	if matched {
+		op, nullOp = planpb.BinaryExpr_LogicalOr, planpb.NullExpr_IsNull
+	}
+	return &planpb.Expr{
+		Expr: &planpb.Expr_BinaryExpr{
+			BinaryExpr: &planpb.BinaryExpr{
+				Left: expr,
+				Right: &planpb.Expr{
+					Expr: &planpb.Expr_NullExpr{
+						NullExpr: &planpb.NullExpr{
+							ColumnInfo: proto.Clone(info).(*planpb.ColumnInfo),
+							Op:         nullOp,
+						},
+					},
+				},
+				Op: op,
+			},
+		},
+		IsTemplate: expr.GetIsTemplate(),
+	}
+}
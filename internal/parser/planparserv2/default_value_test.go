@@ -0,0 +1,66 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestParseExpr_DefaultValueForNull(t *testing.T) {
+	schema := newTestSchema(true)
+	schema.Fields = append(schema.Fields,
+		&schemapb.FieldSchema{
+			FieldID: 1000, Name: "Level", DataType: schemapb.DataType_Int64, Nullable: true,
+			DefaultValue: &schemapb.ValueField{Data: &schemapb.ValueField_LongData{LongData: 10}},
+		},
+		&schemapb.FieldSchema{
+			FieldID: 1001, Name: "Color", DataType: schemapb.DataType_VarChar, Nullable: true,
+			DefaultValue: &schemapb.ValueField{Data: &schemapb.ValueField_StringData{StringData: "red"}},
+		},
+	)
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+	opt := WithDefaultValueForNull(true)
+
+	checkNullOp := func(exprStr string, op planpb.BinaryExpr_BinaryOp, nullOp planpb.NullExpr_NullOp) {
+		expr, err := ParseExpr(helper, exprStr, nil, opt)
+		require.NoError(t, err, exprStr)
+		binary := expr.GetBinaryExpr()
+		require.NotNil(t, binary, exprStr)
+		assert.Equal(t, op, binary.GetOp(), exprStr)
+		assert.Equal(t, nullOp, binary.GetRight().GetNullExpr().GetOp(), exprStr)
+	}
+	checkNullOp(`Level > 5`, planpb.BinaryExpr_LogicalOr, planpb.NullExpr_IsNull)
+	checkNullOp(`Level > 50`, planpb.BinaryExpr_LogicalAnd, planpb.NullExpr_IsNotNull)
+	checkNullOp(`5 < Level <= 10`, planpb.BinaryExpr_LogicalOr, planpb.NullExpr_IsNull)
+	checkNullOp(`5 < Level < 10`, planpb.BinaryExpr_LogicalAnd, planpb.NullExpr_IsNotNull)
+	checkNullOp(`Color in ["red", "blue"]`, planpb.BinaryExpr_LogicalOr, planpb.NullExpr_IsNull)
+	checkNullOp(`Color != "red"`, planpb.BinaryExpr_LogicalAnd, planpb.NullExpr_IsNotNull)
+
+	expr, err := ParseExpr(helper, `not (Level == 10) and Int64Field > 1`, nil, opt)
+	require.NoError(t, err)
+	rewritten := expr.GetBinaryExpr().GetLeft().GetUnaryExpr().GetChild().GetBinaryExpr()
+	require.NotNil(t, rewritten)
+	assert.Equal(t, planpb.NullExpr_IsNull, rewritten.GetRight().GetNullExpr().GetOp())
+	assert.NotNil(t, expr.GetBinaryExpr().GetRight().GetUnaryRangeExpr())
+
+	// template values are filled before the rewrite.
+	expr, err = ParseExpr(helper, `Level == {v}`, map[string]*schemapb.TemplateValue{
+		"v": {Val: &schemapb.TemplateValue_Int64Val{Int64Val: 11}},
+	}, opt)
+	require.NoError(t, err)
+	assert.Equal(t, planpb.NullExpr_IsNotNull, expr.GetBinaryExpr().GetRight().GetNullExpr().GetOp())
+
+	// fields without default value, or without the option, are not rewritten.
+	expr, err = ParseExpr(helper, `Int64Field > 5`, nil, opt)
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+	expr, err = ParseExpr(helper, `Level > 5`, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+}
@@ -0,0 +1,68 @@
+package planparserv2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// DisabledOperatorsKey is the collection property listing the operators disabled for the collection,
+// separated by commas, in addition to the ones disabled by WithDisabledOperators for the deployment.
+const DisabledOperatorsKey = "expr.disabled_operators"
+
+// ErrOperatorDisabled is returned when an expression uses a disabled operator.
+var ErrOperatorDisabled = errors.New("operator disabled")
+
+// WithDisabledOperators disables operators in expressions. The operators are named like the features
+// reported by ValidateExpr, such as "like", "regex" for like patterns which are not prefix matches,
+// "json_contains", "random_sample" or "call:<function>", and "call" disables all the functions.
+func WithDisabledOperators(operators ...string) ParseOption {
+	return func(options *parseOptions) {
+		options.disabledOperators = append(options.disabledOperators, operators...)
+	}
+}
+
+func getDisabledOperators(schema *typeutil.SchemaHelper, options *parseOptions) typeutil.Set[string] {
+	disabled := typeutil.NewSet[string]()
+	add := func(operators ...string) {
+		for _, operator := range operators {
+			if operator = strings.ToLower(strings.TrimSpace(operator)); operator != "" {
+				disabled.Insert(operator)
+			}
+		}
+	}
+	add(options.disabledOperators...)
+	for _, kv := range schema.GetSchema().GetProperties() {
+		if kv.GetKey() == DisabledOperatorsKey {
+			add(strings.Split(kv.GetValue(), ",")...)
+		}
+	}
+	return disabled
+}
+
+func checkDisabledOperators(schema *typeutil.SchemaHelper, expr *planpb.Expr, options *parseOptions) error {
+	disabled := getDisabledOperators(schema, options)
+	if disabled.Len() == 0 {
+		return nil
+	}
+	collector := newExprReportCollector(schema, &ExprReport{})
+	if err := collector.collect(expr); err != nil {
+		return err
+	}
+	features := make([]string, 0, len(collector.features))
+	for feature := range collector.features {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	for _, feature := range features {
+		if disabled.Contain(feature) || (strings.HasPrefix(feature, "call:") && disabled.Contain("call")) {
+			return fmt.Errorf("%w: %s is disabled on collection %s", ErrOperatorDisabled, feature, schema.GetCollectionName())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestParseExpr_DisabledOperators(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	disabled := WithDisabledOperators("regex", " Random_Sample ", "")
+	for _, exprStr := range []string{
+		`VarCharField like "%a%"`,
+		`Int64Field > 1 and not (VarCharField like "a%b")`,
+		`Int64Field > 1 && random_sample(0.1)`,
+	} {
+		_, err := ParseExpr(schema, exprStr, nil, disabled)
+		assert.ErrorIs(t, err, ErrOperatorDisabled, exprStr)
+	}
+	for _, exprStr := range []string{`VarCharField like "a%"`, `Int64Field > 1`} {
+		_, err := ParseExpr(schema, exprStr, nil, disabled)
+		assert.NoError(t, err, exprStr)
+	}
+	_, err := ParseExpr(schema, `VarCharField like "a%"`, nil, WithDisabledOperators("like"))
+	assert.ErrorContains(t, err, "like is disabled on collection test")
+
+	// collection properties add to the deployment wide operators.
+	collection := newTestSchema(true)
+	collection.Properties = append(collection.Properties, &commonpb.KeyValuePair{Key: DisabledOperatorsKey, Value: "json_contains, in"})
+	helper, err := typeutil.CreateSchemaHelper(collection)
+	require.NoError(t, err)
+	_, err = ParseExpr(helper, `json_contains(JSONField["a"], 1)`, nil)
+	assert.ErrorIs(t, err, ErrOperatorDisabled)
+	_, err = ParseExpr(helper, `Int64Field in [1, 2]`, nil)
+	assert.ErrorIs(t, err, ErrOperatorDisabled)
+	_, err = ParseExpr(helper, `VarCharField like "%a%"`, nil, disabled)
+	assert.ErrorIs(t, err, ErrOperatorDisabled)
+	_, err = ParseExpr(helper, `Int64Field > 1`, nil, disabled)
+	assert.NoError(t, err)
+}
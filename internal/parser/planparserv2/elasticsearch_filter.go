@@ -0,0 +1,317 @@
+package planparserv2
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// ConvertElasticsearchQuery translates an Elasticsearch query, typically a bool query composed of
+// must/filter/should/must_not clauses with term, terms, range, exists and match_phrase leaves,
+// into a plan expression. Like ConvertMongoFilter, the query is lowered into the native expression
+// syntax and parsed with ParseExpr.
+func ConvertElasticsearchQuery(schema *typeutil.SchemaHelper, query []byte) (*planpb.Expr, error) {
+	exprStr, err := ElasticsearchQueryToExprString(query)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExpr(schema, exprStr, nil)
+}
+
+// ElasticsearchQueryToExprString translates an Elasticsearch query into the native expression string.
+// Both the bare query and the request body form `{"query": {...}}` are accepted.
+func ElasticsearchQueryToExprString(query []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewBuffer(query))
+	decoder.UseNumber()
+	var doc map[string]interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return "", fmt.Errorf("invalid elasticsearch query: %s", err)
+	}
+	if inner, ok := doc["query"]; ok && len(doc) == 1 {
+		if doc, ok = inner.(map[string]interface{}); !ok {
+			return "", fmt.Errorf("invalid elasticsearch query: query must be an object")
+		}
+	}
+	expr, err := convertESQuery(doc)
+	if err != nil {
+		return "", err
+	}
+	if expr == "true" {
+		return "", nil
+	}
+	return expr, nil
+}
+
+func convertESQuery(doc map[string]interface{}) (string, error) {
+	if len(doc) != 1 {
+		return "", fmt.Errorf("elasticsearch query clause must contain exactly one query type, got %d", len(doc))
+	}
+	for queryType, body := range doc {
+		switch queryType {
+		case "bool":
+			return convertESBool(body)
+		case "match_all":
+			return "true", nil
+		case "term", "terms", "range", "exists", "match_phrase":
+			return convertESLeaf(queryType, body)
+		default:
+			return "", fmt.Errorf("unsupported elasticsearch query type: %s", queryType)
+		}
+	}
+	return "", nil
+}
+
+// esClauses accepts both a single clause object and an array of clauses, as Elasticsearch does.
+func esClauses(occur string, value interface{}) ([]string, error) {
+	var clauses []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		clauses = v
+	case map[string]interface{}:
+		clauses = []interface{}{v}
+	default:
+		return nil, fmt.Errorf("elasticsearch bool clause %s must be an object or an array", occur)
+	}
+	exprs := make([]string, 0, len(clauses))
+	for _, clause := range clauses {
+		doc, ok := clause.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("elasticsearch bool clause %s must contain query objects", occur)
+		}
+		expr, err := convertESQuery(doc)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+func convertESBool(body interface{}) (string, error) {
+	doc, ok := body.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("elasticsearch bool query must be an object")
+	}
+	var required, should []string
+	// must_not doesn't count here, while match_all does.
+	hasMustClause := false
+	minimumShouldMatch := -1
+	for _, key := range sortedKeys(doc) {
+		switch key {
+		case "must", "filter":
+			exprs, err := esClauses(key, doc[key])
+			if err != nil {
+				return "", err
+			}
+			hasMustClause = hasMustClause || len(exprs) > 0
+			for _, expr := range exprs {
+				// match_all inside must/filter imposes no restriction.
+				if expr != "true" {
+					required = append(required, expr)
+				}
+			}
+		case "must_not":
+			exprs, err := esClauses(key, doc[key])
+			if err != nil {
+				return "", err
+			}
+			for _, expr := range exprs {
+				required = append(required, negateExpr(expr))
+			}
+		case "should":
+			exprs, err := esClauses(key, doc[key])
+			if err != nil {
+				return "", err
+			}
+			should = exprs
+		case "minimum_should_match":
+			number, ok := doc[key].(json.Number)
+			if !ok {
+				return "", fmt.Errorf("only integer minimum_should_match is supported")
+			}
+			n, err := number.Int64()
+			if err != nil || n < 0 || n > 1 {
+				return "", fmt.Errorf("only minimum_should_match of 0 or 1 is supported, got %s", number)
+			}
+			minimumShouldMatch = int(n)
+		case "boost", "_name":
+			// scoring only, no effect on filtering.
+		default:
+			return "", fmt.Errorf("unsupported elasticsearch bool clause: %s", key)
+		}
+	}
+
+	// In filter context, should clauses are required only when there are no must/filter clauses,
+	// unless minimum_should_match says otherwise.
+	if minimumShouldMatch < 0 {
+		minimumShouldMatch = 0
+		if !hasMustClause {
+			minimumShouldMatch = 1
+		}
+	}
+	if len(should) > 0 && minimumShouldMatch > 0 {
+		required = append(required, joinExprs(should, "or"))
+	}
+	return joinExprs(required, "and"), nil
+}
+
+// esFieldBody extracts the single field and its parameters from leaf queries like {"term": {"field": ...}}.
+func esFieldBody(queryType string, body interface{}) (string, interface{}, error) {
+	doc, ok := body.(map[string]interface{})
+	if !ok {
+		return "", nil, fmt.Errorf("elasticsearch %s query must be an object", queryType)
+	}
+	var fields []string
+	for _, key := range sortedKeys(doc) {
+		if key != "boost" && key != "_name" {
+			fields = append(fields, key)
+		}
+	}
+	if len(fields) != 1 {
+		return "", nil, fmt.Errorf("elasticsearch %s query must target exactly one field", queryType)
+	}
+	return fields[0], doc[fields[0]], nil
+}
+
+func convertESLeaf(queryType string, body interface{}) (string, error) {
+	if queryType == "exists" {
+		doc, ok := body.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("elasticsearch exists query must be an object")
+		}
+		field, ok := doc["field"].(string)
+		if !ok {
+			return "", fmt.Errorf("elasticsearch exists query requires a field")
+		}
+		identifier, err := dottedPathToIdentifier(field)
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(field, ".") {
+			return "exists " + identifier, nil
+		}
+		return identifier + " is not null", nil
+	}
+
+	field, params, err := esFieldBody(queryType, body)
+	if err != nil {
+		return "", err
+	}
+	identifier, err := dottedPathToIdentifier(field)
+	if err != nil {
+		return "", err
+	}
+
+	switch queryType {
+	case "term":
+		value := params
+		if doc, ok := params.(map[string]interface{}); ok {
+			value = doc["value"]
+		}
+		literal, err := formatJSONLiteral(value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s == %s", identifier, literal), nil
+	case "terms":
+		if _, ok := params.([]interface{}); !ok {
+			return "", fmt.Errorf("elasticsearch terms query requires an array of values")
+		}
+		literal, err := formatJSONLiteral(params)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s in %s", identifier, literal), nil
+	case "range":
+		return convertESRange(identifier, params)
+	case "match_phrase":
+		query, slop := params, interface{}(nil)
+		if doc, ok := params.(map[string]interface{}); ok {
+			query, slop = doc["query"], doc["slop"]
+		}
+		text, ok := query.(string)
+		if !ok {
+			return "", fmt.Errorf("elasticsearch match_phrase query requires a string query")
+		}
+		if slop == nil {
+			return fmt.Sprintf("phrase_match(%s, %s)", identifier, strconv.Quote(text)), nil
+		}
+		slopLiteral, err := formatJSONLiteral(slop)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("phrase_match(%s, %s, %s)", identifier, strconv.Quote(text), slopLiteral), nil
+	}
+	return "", fmt.Errorf("unsupported elasticsearch query type: %s", queryType)
+}
+
+var esRangeOps = map[string]string{
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+}
+
+func convertESRange(identifier string, params interface{}) (string, error) {
+	doc, ok := params.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("elasticsearch range query requires bound parameters")
+	}
+	bounds := make(map[string]string, len(doc))
+	for _, key := range sortedKeys(doc) {
+		if _, ok := esRangeOps[key]; !ok {
+			if key == "boost" {
+				continue
+			}
+			return "", fmt.Errorf("unsupported elasticsearch range parameter: %s", key)
+		}
+		literal, err := formatJSONLiteral(doc[key])
+		if err != nil {
+			return "", err
+		}
+		bounds[key] = literal
+	}
+
+	lowerOp, lower := "", ""
+	for _, op := range []string{"gt", "gte"} {
+		if literal, ok := bounds[op]; ok {
+			if lowerOp != "" {
+				return "", fmt.Errorf("elasticsearch range query has more than one lower bound")
+			}
+			lowerOp, lower = op, literal
+		}
+	}
+	upperOp, upper := "", ""
+	for _, op := range []string{"lt", "lte"} {
+		if literal, ok := bounds[op]; ok {
+			if upperOp != "" {
+				return "", fmt.Errorf("elasticsearch range query has more than one upper bound")
+			}
+			upperOp, upper = op, literal
+		}
+	}
+
+	switch {
+	case lowerOp != "" && upperOp != "":
+		// lower < field < upper is parsed into a single binary range.
+		return fmt.Sprintf("%s %s %s %s %s", lower, esRangeOps[reverseESRangeOp(lowerOp)], identifier, esRangeOps[upperOp], upper), nil
+	case lowerOp != "":
+		return fmt.Sprintf("%s %s %s", identifier, esRangeOps[lowerOp], lower), nil
+	case upperOp != "":
+		return fmt.Sprintf("%s %s %s", identifier, esRangeOps[upperOp], upper), nil
+	default:
+		return "", fmt.Errorf("elasticsearch range query requires at least one bound")
+	}
+}
+
+func reverseESRangeOp(op string) string {
+	if op == "gt" {
+		return "lt"
+	}
+	return "lte"
+}
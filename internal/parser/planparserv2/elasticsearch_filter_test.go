@@ -0,0 +1,86 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElasticsearchQueryToExprString(t *testing.T) {
+	cases := []struct {
+		query string
+		expr  string
+	}{
+		{`{"match_all": {}}`, ``},
+		{`{"query": {"term": {"VarCharField": "abc"}}}`, `VarCharField == "abc"`},
+		{`{"term": {"Int64Field": {"value": 3, "boost": 2}}}`, `Int64Field == 3`},
+		{`{"terms": {"JSONField.a": [1, 2]}}`, `JSONField["a"] in [1, 2]`},
+		{`{"range": {"Int64Field": {"gte": 1, "lt": 10}}}`, `1 <= Int64Field < 10`},
+		{`{"range": {"DoubleField": {"gt": 1.5}}}`, `DoubleField > 1.5`},
+		{`{"exists": {"field": "Int64Field"}}`, `Int64Field is not null`},
+		{`{"exists": {"field": "JSONField.a"}}`, `exists JSONField["a"]`},
+		{`{"match_phrase": {"VarCharField": "hello world"}}`, `phrase_match(VarCharField, "hello world")`},
+		{`{"match_phrase": {"VarCharField": {"query": "hello", "slop": 2}}}`, `phrase_match(VarCharField, "hello", 2)`},
+		{
+			`{"bool": {"must": [{"term": {"Int64Field": 1}}, {"match_all": {}}], "must_not": {"term": {"BoolField": true}}}}`,
+			`(Int64Field == 1 and not (BoolField == true))`,
+		},
+		{
+			`{"bool": {"should": [{"term": {"Int64Field": 1}}, {"term": {"Int64Field": 2}}]}}`,
+			`(Int64Field == 1 or Int64Field == 2)`,
+		},
+		{
+			`{"bool": {"filter": {"term": {"Int64Field": 1}}, "should": [{"term": {"Int32Field": 2}}]}}`,
+			`Int64Field == 1`,
+		},
+		{
+			`{"bool": {"filter": {"term": {"Int64Field": 1}}, "should": [{"term": {"Int32Field": 2}}], "minimum_should_match": 1}}`,
+			`(Int64Field == 1 and Int32Field == 2)`,
+		},
+		{
+			`{"bool": {"must_not": {"term": {"BoolField": true}}, "should": [{"term": {"Int64Field": 1}}, {"term": {"Int64Field": 2}}]}}`,
+			`(not (BoolField == true) and (Int64Field == 1 or Int64Field == 2))`,
+		},
+		{
+			`{"bool": {"must": {"match_all": {}}, "should": [{"term": {"Int64Field": 1}}]}}`,
+			``,
+		},
+	}
+	for _, c := range cases {
+		expr, err := ElasticsearchQueryToExprString([]byte(c.query))
+		assert.NoError(t, err, c.query)
+		assert.Equal(t, c.expr, expr, c.query)
+	}
+
+	invalidQueries := []string{
+		`[]`,
+		`{"match": {"a": "b"}}`,
+		`{"term": {"a": 1}, "terms": {"b": [1]}}`,
+		`{"term": {"a": 1, "b": 2}}`,
+		`{"terms": {"a": 1}}`,
+		`{"range": {"a": {}}}`,
+		`{"range": {"a": {"gt": 1, "gte": 2}}}`,
+		`{"range": {"a": {"format": "x"}}}`,
+		`{"exists": {}}`,
+		`{"match_phrase": {"a": 1}}`,
+		`{"bool": {"must": 1}}`,
+		`{"bool": {"minimum_should_match": 2}}`,
+		`{"bool": {"minimum_should_match": "50%"}}`,
+		`{"bool": {"unknown": []}}`,
+	}
+	for _, query := range invalidQueries {
+		_, err := ElasticsearchQueryToExprString([]byte(query))
+		assert.Error(t, err, query)
+	}
+}
+
+func TestConvertElasticsearchQuery(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	expr, err := ConvertElasticsearchQuery(helper, []byte(`{"bool": {"filter": [{"range": {"Int64Field": {"gt": 1, "lte": 5}}}]}}`))
+	assert.NoError(t, err)
+	assert.NotNil(t, expr.GetBinaryRangeExpr())
+
+	_, err = ConvertElasticsearchQuery(helper, []byte(`{"term": {"BoolField": "abc"}}`))
+	assert.Error(t, err)
+}
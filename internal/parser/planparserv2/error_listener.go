@@ -18,7 +18,7 @@ type errorListenerImpl struct {
 }
 
 func (l *errorListenerImpl) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{}, line, column int, msg string, e antlr.RecognitionException) {
-	l.err = fmt.Errorf("line " + strconv.Itoa(line) + ":" + strconv.Itoa(column) + " " + msg)
+	l.err = withErrorCode(ErrCodeSyntax, fmt.Errorf("line "+strconv.Itoa(line)+":"+strconv.Itoa(column)+" "+msg))
 }
 
 func (l *errorListenerImpl) Error() error {
@@ -0,0 +1,600 @@
+package planparserv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// EvalExpr evaluates a parsed expression against a row, with the semantics of segcore, so that tests, import
+// validators and CDC filters can filter rows without a query node. The row maps the field ids to their values:
+//   - nil for null,
+//   - bool, the integer and float types, or string for scalar fields,
+//   - a slice for array fields,
+//   - the raw []byte or the decoded value for json fields.
+//
+// Like segcore, a predicate on null is false, and so is its negation. Text matches, random samples and the functions
+// other than the ones of rowFunctions can't be evaluated without a query node and are rejected.
+func EvalExpr(expr *planpb.Expr, row map[int64]interface{}) (bool, error) {
+	return evalRow(expr, row, nil)
+}
+
+// evalRow evaluates the expression against the row, the like patterns are looked up in patterns before the cache.
+func evalRow(expr *planpb.Expr, row map[int64]interface{}, patterns map[string]*regexp.Regexp) (bool, error) {
+	result, valid, err := (&rowEvaluator{row: row, patterns: patterns}).eval(expr)
+	if err != nil {
+		return false, err
+	}
+	return result && valid, nil
+}
+
+// rowFunctions are the filter functions of segcore which EvalExpr evaluates.
+var rowFunctions = map[string]func(params []interface{}) (bool, error){
+	"empty": func(params []interface{}) (bool, error) {
+		if len(params) != 1 {
+			return false, fmt.Errorf("empty takes 1 argument, but got %d", len(params))
+		}
+		s, ok := params[0].(string)
+		return ok && s == "", nil
+	},
+	"starts_with": func(params []interface{}) (bool, error) {
+		if len(params) != 2 {
+			return false, fmt.Errorf("starts_with takes 2 arguments, but got %d", len(params))
+		}
+		s, ok := params[0].(string)
+		prefix, prefixOk := params[1].(string)
+		return ok && prefixOk && strings.HasPrefix(s, prefix), nil
+	},
+}
+
+type rowEvaluator struct {
+	row      map[int64]interface{}
+	patterns map[string]*regexp.Regexp
+}
+
+// eval returns the result of the predicate, and whether it is valid, which is false for the predicates on null.
+func (e *rowEvaluator) eval(expr *planpb.Expr) (bool, bool, error) {
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_AlwaysTrueExpr:
+		return true, true, nil
+	case *planpb.Expr_UnaryExpr:
+		result, valid, err := e.eval(realExpr.UnaryExpr.GetChild())
+		if err != nil {
+			return false, false, err
+		}
+		return !result && valid, valid, nil
+	case *planpb.Expr_BinaryExpr:
+		left, leftValid, err := e.eval(realExpr.BinaryExpr.GetLeft())
+		if err != nil {
+			return false, false, err
+		}
+		right, rightValid, err := e.eval(realExpr.BinaryExpr.GetRight())
+		if err != nil {
+			return false, false, err
+		}
+		if realExpr.BinaryExpr.GetOp() == planpb.BinaryExpr_LogicalAnd {
+			return left && right, leftValid || rightValid, nil
+		}
+		return left || right, leftValid || rightValid, nil
+	case *planpb.Expr_ValueExpr:
+		value := realExpr.ValueExpr.GetValue()
+		if !IsBool(value) {
+			return false, false, fmt.Errorf("value %s is not a boolean", value.String())
+		}
+		return value.GetBoolVal(), true, nil
+	case *planpb.Expr_ColumnExpr:
+		value, found, valid, err := e.operand(realExpr.ColumnExpr.GetInfo())
+		if err != nil || !valid {
+			return false, valid, err
+		}
+		b, ok := value.(bool)
+		return found && ok && b, true, nil
+	case *planpb.Expr_UnaryRangeExpr:
+		return e.evalUnaryRange(realExpr.UnaryRangeExpr)
+	case *planpb.Expr_BinaryRangeExpr:
+		return e.evalBinaryRange(realExpr.BinaryRangeExpr)
+	case *planpb.Expr_TermExpr:
+		return e.evalTerm(realExpr.TermExpr)
+	case *planpb.Expr_CompareExpr:
+		return e.evalCompare(realExpr.CompareExpr)
+	case *planpb.Expr_BinaryArithOpEvalRangeExpr:
+		return e.evalArithRange(realExpr.BinaryArithOpEvalRangeExpr)
+	case *planpb.Expr_ExistsExpr:
+		value, found, valid, err := e.operand(realExpr.ExistsExpr.GetInfo())
+		if err != nil || !valid {
+			return false, valid, err
+		}
+		return found && value != nil, true, nil
+	case *planpb.Expr_NullExpr:
+		raw, err := e.field(realExpr.NullExpr.GetColumnInfo())
+		if err != nil {
+			return false, false, err
+		}
+		return (raw == nil) == (realExpr.NullExpr.GetOp() == planpb.NullExpr_IsNull), true, nil
+	case *planpb.Expr_JsonContainsExpr:
+		return e.evalContains(realExpr.JsonContainsExpr)
+	case *planpb.Expr_CallExpr:
+		return e.evalCall(realExpr.CallExpr)
+	default:
+		return false, false, fmt.Errorf("expression %T can't be evaluated without a query node", expr.GetExpr())
+	}
+}
+
+func (e *rowEvaluator) evalUnaryRange(expr *planpb.UnaryRangeExpr) (bool, bool, error) {
+	if expr.GetValue() == nil {
+		return false, false, fmt.Errorf("template variable %s is not filled", expr.GetTemplateVariableName())
+	}
+	value, found, valid, err := e.operand(expr.GetColumnInfo())
+	if err != nil || !valid {
+		return false, valid, err
+	}
+	op := expr.GetOp()
+	if !found {
+		return op == planpb.OpType_NotEqual, true, nil
+	}
+	operand := genericValueOf(expr.GetValue())
+	switch op {
+	case planpb.OpType_PrefixMatch, planpb.OpType_PostfixMatch, planpb.OpType_Match:
+		s, ok := value.(string)
+		if !ok {
+			return false, true, nil
+		}
+		pattern := operand.(string)
+		switch op {
+		case planpb.OpType_PrefixMatch:
+			return strings.HasPrefix(s, pattern), true, nil
+		case planpb.OpType_PostfixMatch:
+			return strings.HasSuffix(s, pattern), true, nil
+		}
+		matcher, ok := e.patterns[pattern]
+		if !ok {
+			var err error
+			if matcher, err = CompileLikePattern(pattern); err != nil {
+				return false, false, err
+			}
+		}
+		return matcher.MatchString(s), true, nil
+	case planpb.OpType_TextMatch, planpb.OpType_PhraseMatch:
+		return false, false, fmt.Errorf("%s needs the analyzer of a query node", op.String())
+	}
+	result, err := compareWithOp(op, value, operand)
+	return result, true, err
+}
+
+func (e *rowEvaluator) evalBinaryRange(expr *planpb.BinaryRangeExpr) (bool, bool, error) {
+	if expr.GetLowerValue() == nil || expr.GetUpperValue() == nil {
+		return false, false, fmt.Errorf("template variables of range are not filled")
+	}
+	value, found, valid, err := e.operand(expr.GetColumnInfo())
+	if err != nil || !valid || !found {
+		return false, valid, err
+	}
+	lowerOp, upperOp := planpb.OpType_GreaterThan, planpb.OpType_LessThan
+	if expr.GetLowerInclusive() {
+		lowerOp = planpb.OpType_GreaterEqual
+	}
+	if expr.GetUpperInclusive() {
+		upperOp = planpb.OpType_LessEqual
+	}
+	lower, err := compareWithOp(lowerOp, value, genericValueOf(expr.GetLowerValue()))
+	if err != nil || !lower {
+		return false, true, err
+	}
+	upper, err := compareWithOp(upperOp, value, genericValueOf(expr.GetUpperValue()))
+	return upper, true, err
+}
+
+func (e *rowEvaluator) evalTerm(expr *planpb.TermExpr) (bool, bool, error) {
+	value, found, valid, err := e.operand(expr.GetColumnInfo())
+	if err != nil || !valid || !found {
+		return false, valid, err
+	}
+	for _, element := range expr.GetValues() {
+		if equalValues(value, genericValueOf(element)) {
+			return true, true, nil
+		}
+	}
+	return false, true, nil
+}
+
+func (e *rowEvaluator) evalCompare(expr *planpb.CompareExpr) (bool, bool, error) {
+	left, leftFound, leftValid, err := e.operand(expr.GetLeftColumnInfo())
+	if err != nil || !leftValid {
+		return false, false, err
+	}
+	right, rightFound, rightValid, err := e.operand(expr.GetRightColumnInfo())
+	if err != nil || !rightValid {
+		return false, false, err
+	}
+	if !leftFound || !rightFound {
+		return false, true, nil
+	}
+	result, err := compareWithOp(expr.GetOp(), left, right)
+	return result, true, err
+}
+
+func (e *rowEvaluator) evalArithRange(expr *planpb.BinaryArithOpEvalRangeExpr) (bool, bool, error) {
+	if expr.GetValue() == nil || (expr.GetArithOp() != planpb.ArithOpType_ArrayLength && expr.GetRightOperand() == nil) {
+		return false, false, fmt.Errorf("template variables of arithmetic are not filled")
+	}
+	value, found, valid, err := e.operand(expr.GetColumnInfo())
+	if err != nil || !valid {
+		return false, valid, err
+	}
+	if !found {
+		return expr.GetOp() == planpb.OpType_NotEqual, true, nil
+	}
+	var computed interface{}
+	if expr.GetArithOp() == planpb.ArithOpType_ArrayLength {
+		array, ok := value.([]interface{})
+		if !ok {
+			return false, true, nil
+		}
+		computed = int64(len(array))
+	} else {
+		var ok bool
+		computed, ok = arith(expr.GetArithOp(), value, genericValueOf(expr.GetRightOperand()))
+		if !ok {
+			return expr.GetOp() == planpb.OpType_NotEqual, true, nil
+		}
+	}
+	result, err := compareWithOp(expr.GetOp(), computed, genericValueOf(expr.GetValue()))
+	return result, true, err
+}
+
+func (e *rowEvaluator) evalContains(expr *planpb.JSONContainsExpr) (bool, bool, error) {
+	value, found, valid, err := e.operand(expr.GetColumnInfo())
+	if err != nil || !valid || !found {
+		return false, valid, err
+	}
+	array, ok := value.([]interface{})
+	if !ok {
+		return false, true, nil
+	}
+	contains := func(element interface{}) bool {
+		for _, v := range array {
+			if equalValues(v, element) {
+				return true
+			}
+		}
+		return false
+	}
+	switch expr.GetOp() {
+	case planpb.JSONContainsExpr_Contains, planpb.JSONContainsExpr_ContainsAny:
+		for _, element := range expr.GetElements() {
+			if contains(genericValueOf(element)) {
+				return true, true, nil
+			}
+		}
+		return false, true, nil
+	case planpb.JSONContainsExpr_ContainsAll:
+		for _, element := range expr.GetElements() {
+			if !contains(genericValueOf(element)) {
+				return false, true, nil
+			}
+		}
+		return true, true, nil
+	default:
+		return false, false, fmt.Errorf("invalid json contains op: %s", expr.GetOp().String())
+	}
+}
+
+func (e *rowEvaluator) evalCall(expr *planpb.CallExpr) (bool, bool, error) {
+	function, ok := rowFunctions[expr.GetFunctionName()]
+	if !ok {
+		return false, false, fmt.Errorf("function %s can't be evaluated without a query node", expr.GetFunctionName())
+	}
+	params := make([]interface{}, 0, len(expr.GetFunctionParameters()))
+	for _, param := range expr.GetFunctionParameters() {
+		switch p := param.GetExpr().(type) {
+		case *planpb.Expr_ColumnExpr:
+			value, found, valid, err := e.operand(p.ColumnExpr.GetInfo())
+			if err != nil || !valid {
+				return false, valid, err
+			}
+			if !found {
+				return false, true, nil
+			}
+			params = append(params, value)
+		case *planpb.Expr_ValueExpr:
+			params = append(params, genericValueOf(p.ValueExpr.GetValue()))
+		default:
+			return false, false, fmt.Errorf("argument %T of function %s can't be evaluated", param.GetExpr(), expr.GetFunctionName())
+		}
+	}
+	result, err := function(params)
+	return result, true, err
+}
+
+// field returns the normalized value of the field of the column, nil if it is null.
+func (e *rowEvaluator) field(info *planpb.ColumnInfo) (interface{}, error) {
+	raw, ok := e.row[info.GetFieldId()]
+	if !ok {
+		return nil, fmt.Errorf("row has no value of field %d", info.GetFieldId())
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	if data, ok := raw.([]byte); ok && typeutil.IsJSONType(info.GetDataType()) {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid json of field %d: %w", info.GetFieldId(), err)
+		}
+	}
+	return normalizeRowValue(raw)
+}
+
+// operand returns the value read by the column following its nested path, whether the path exists, and whether
+// the field is not null.
+func (e *rowEvaluator) operand(info *planpb.ColumnInfo) (interface{}, bool, bool, error) {
+	value, err := e.field(info)
+	if err != nil || value == nil {
+		return nil, false, false, err
+	}
+	for _, key := range info.GetNestedPath() {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			element, ok := v[key]
+			if !ok {
+				return nil, false, true, nil
+			}
+			value = element
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false, true, nil
+			}
+			value = v[index]
+		default:
+			return nil, false, true, nil
+		}
+	}
+	return value, true, true, nil
+}
+
+// normalizeRowValue converts the value to nil, bool, int64, float64, string, []interface{} or map[string]interface{}.
+func normalizeRowValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil, bool, int64, float64, string:
+		return v, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case *schemapb.ScalarField:
+		values, err := scalarFieldValues(v)
+		if err != nil {
+			return nil, err
+		}
+		return normalizeRowValue(values)
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			n, err := normalizeRowValue(element)
+			if err != nil {
+				return nil, err
+			}
+			normalized[key] = n
+		}
+		return normalized, nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Slice, reflect.Array:
+		normalized := make([]interface{}, rv.Len())
+		for i := range normalized {
+			n, err := normalizeRowValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			normalized[i] = n
+		}
+		return normalized, nil
+	default:
+		return nil, fmt.Errorf("unsupported value %v of type %T", value, value)
+	}
+}
+
+func genericValueOf(value *planpb.GenericValue) interface{} {
+	switch v := value.GetVal().(type) {
+	case *planpb.GenericValue_BoolVal:
+		return v.BoolVal
+	case *planpb.GenericValue_Int64Val:
+		return v.Int64Val
+	case *planpb.GenericValue_FloatVal:
+		return v.FloatVal
+	case *planpb.GenericValue_StringVal:
+		return v.StringVal
+	case *planpb.GenericValue_ArrayVal:
+		array := make([]interface{}, len(v.ArrayVal.GetArray()))
+		for i, element := range v.ArrayVal.GetArray() {
+			array[i] = genericValueOf(element)
+		}
+		return array
+	default:
+		return nil
+	}
+}
+
+// compareValues compares two values of the same kind, numbers are compared as floats unless both are integers.
+func compareValues(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return compareOrdered(x, y), true
+		case float64:
+			return compareOrdered(float64(x), y), true
+		}
+	case float64:
+		switch y := b.(type) {
+		case int64:
+			return compareOrdered(x, float64(y)), true
+		case float64:
+			return compareOrdered(x, y), true
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			if x == y {
+				return 0, true
+			}
+			if !x {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, false
+}
+
+func compareOrdered[T int64 | float64](x, y T) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func equalValues(a, b interface{}) bool {
+	if x, ok := a.([]interface{}); ok {
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalValues(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	c, ok := compareValues(a, b)
+	return ok && c == 0
+}
+
+// compareWithOp compares the value with the operand, values of different kinds are only not equal.
+func compareWithOp(op planpb.OpType, value, operand interface{}) (bool, error) {
+	switch op {
+	case planpb.OpType_Equal:
+		return equalValues(value, operand), nil
+	case planpb.OpType_NotEqual:
+		return !equalValues(value, operand), nil
+	}
+	c, ok := compareValues(value, operand)
+	if !ok {
+		return false, nil
+	}
+	switch op {
+	case planpb.OpType_GreaterThan:
+		return c > 0, nil
+	case planpb.OpType_GreaterEqual:
+		return c >= 0, nil
+	case planpb.OpType_LessThan:
+		return c < 0, nil
+	case planpb.OpType_LessEqual:
+		return c <= 0, nil
+	default:
+		return false, fmt.Errorf("unsupported op %s", op.String())
+	}
+}
+
+// arith computes `value op operand`, in integers if both are integers, and false if they aren't numbers or the
+// operand is a zero divisor.
+func arith(op planpb.ArithOpType, value, operand interface{}) (interface{}, bool) {
+	x, xInt := value.(int64)
+	y, yInt := operand.(int64)
+	if xInt && yInt {
+		switch op {
+		case planpb.ArithOpType_Add:
+			return x + y, true
+		case planpb.ArithOpType_Sub:
+			return x - y, true
+		case planpb.ArithOpType_Mul:
+			return x * y, true
+		case planpb.ArithOpType_Div:
+			return x / y, y != 0
+		case planpb.ArithOpType_Mod:
+			return x % y, y != 0
+		}
+		return nil, false
+	}
+	fx, ok := numberToFloat(value)
+	if !ok {
+		return nil, false
+	}
+	fy, ok := numberToFloat(operand)
+	if !ok {
+		return nil, false
+	}
+	switch op {
+	case planpb.ArithOpType_Add:
+		return fx + fy, true
+	case planpb.ArithOpType_Sub:
+		return fx - fy, true
+	case planpb.ArithOpType_Mul:
+		return fx * fy, true
+	case planpb.ArithOpType_Div:
+		return fx / fy, fy != 0
+	case planpb.ArithOpType_Mod:
+		return math.Mod(fx, fy), fy != 0
+	}
+	return nil, false
+}
+
+func numberToFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// likeRegexp translates a like pattern to a regular expression, `%` matches any characters, `_` matches
+// one character, and a backslash escapes the next character.
+func likeRegexp(pattern string) (*regexp.Regexp, error) {
+	var builder strings.Builder
+	builder.WriteString(`(?s)^`)
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			builder.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case r == '%':
+			builder.WriteString(`.*`)
+		case r == '_':
+			builder.WriteString(`.`)
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	builder.WriteString(`$`)
+	return regexp.Compile(builder.String())
+}
@@ -0,0 +1,117 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestEvalExpr(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+	row := make(map[int64]interface{})
+	for name, value := range map[string]interface{}{
+		"Int64Field":   int64(10),
+		"Int32Field":   int32(3),
+		"FloatField":   float32(1.5),
+		"DoubleField":  2.5,
+		"BoolField":    true,
+		"VarCharField": "milvus_db",
+		"JSONField":    []byte(`{"a": 1, "b": {"c": "x"}, "tags": ["red", "blue"], "n": null, "f": 1.5}`),
+		"ArrayField":   []int64{1, 2, 3},
+		"StringField":  nil,
+		"$meta":        []byte(`{"color": "red"}`),
+	} {
+		field, err := helper.GetFieldFromName(name)
+		require.NoError(t, err)
+		row[field.GetFieldID()] = value
+	}
+	eval := func(exprStr string) bool {
+		expr, err := ParseExpr(helper, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		result, err := EvalExpr(expr, row)
+		require.NoError(t, err, exprStr)
+		return result
+	}
+
+	for _, exprStr := range []string{
+		`Int64Field == 10`,
+		`Int64Field > 5 and Int32Field <= 3`,
+		`Int64Field in [1, 10]`,
+		`not (Int64Field in [1, 2])`,
+		`1 < Int64Field <= 10`,
+		`Int64Field + 5 == 15`,
+		`Int64Field % 4 == 2`,
+		`FloatField > 1 and DoubleField < 3.0`,
+		`Int64Field > DoubleField`,
+		`BoolField is true`,
+		`BoolField == true`,
+		`VarCharField like "milvus%"`,
+		`VarCharField like "%_db"`,
+		`VarCharField like "m%v_s%"`,
+		`JSONField["a"] == 1`,
+		`JSONField["a"] < 1.5`,
+		`JSONField["f"] > 1`,
+		`JSONField["b"]["c"] == "x"`,
+		`JSONField["missing"] != 1`,
+		`JSONField["tags"][0] == "red"`,
+		`exists JSONField["a"]`,
+		`json_contains(JSONField["tags"], "blue")`,
+		`json_contains_all(JSONField["tags"], ["red", "blue"])`,
+		`json_contains_any(JSONField["tags"], ["green", "blue"])`,
+		`array_contains(ArrayField, 2)`,
+		`array_length(ArrayField) == 3`,
+		`ArrayField[1] == 2`,
+		`StringField is null`,
+		`color == "red"`,
+		`Int64Field == 1 or VarCharField != "a"`,
+	} {
+		assert.True(t, eval(exprStr), exprStr)
+	}
+
+	for _, exprStr := range []string{
+		`Int64Field != 10`,
+		`Int64Field in [1, 2]`,
+		`JSONField["missing"] == 1`,
+		`JSONField["missing"] > 1`,
+		`exists JSONField["missing"]`,
+		`exists JSONField["n"]`,
+		`JSONField["b"]["c"] > 1`,
+		`JSONField["tags"][5] == "red"`,
+		`VarCharField like "db%"`,
+		`ArrayField[5] == 1`,
+		`array_contains_all(ArrayField, [1, 4])`,
+		// predicates on null are false, and so are their negations.
+		`StringField == "a"`,
+		`not (StringField == "a")`,
+		`StringField is not null`,
+		`StringField == "a" and Int64Field == 10`,
+	} {
+		assert.False(t, eval(exprStr), exprStr)
+	}
+	assert.True(t, eval(`StringField == "a" or Int64Field == 10`))
+
+	// the values aren't checked against the schema.
+	expr, err := ParseExpr(helper, `Int8Field > 1`, nil)
+	require.NoError(t, err)
+	_, err = EvalExpr(expr, row)
+	assert.ErrorContains(t, err, "no value")
+
+	expr, err = ParseExpr(helper, `Int64Field > 1 and random_sample(0.1)`, nil)
+	require.NoError(t, err)
+	_, err = EvalExpr(expr, row)
+	assert.Error(t, err)
+
+	report, err := ValidateExpr(helper, `Int64Field > {v}`)
+	require.NoError(t, err)
+	assert.Equal(t, schemapb.DataType_Int64, report.TemplateSlots[0].DataType)
+	expr, err = ParseExpr(helper, `Int64Field > {v}`, map[string]*schemapb.TemplateValue{
+		"v": generateTemplateValue(schemapb.DataType_Int64, int64(5)),
+	})
+	require.NoError(t, err)
+	result, err := EvalExpr(expr, row)
+	require.NoError(t, err)
+	assert.True(t, result)
+}
@@ -0,0 +1,208 @@
+package planparserv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+const (
+	scanBudgetHint     = "scan_budget"
+	timeBudgetHint     = "time_budget"
+	sampleOnBudgetHint = "sample_on_budget"
+
+	// filterBudgetKey is the search param which carries the execution budget to query nodes, such as
+	// `"filter_budget": {"scan_rows": 100000, "timeout_ms": 500, "on_exceeded": "sample"}`.
+	filterBudgetKey = "filter_budget"
+
+	budgetAbort  = "abort"
+	budgetSample = "sample"
+)
+
+// budgetHints are the hints giving the execution budget of the filter, like WithExecutionBudget.
+var budgetHints = map[string]struct{}{
+	scanBudgetHint:     {},
+	timeBudgetHint:     {},
+	sampleOnBudgetHint: {},
+}
+
+// ExecutionBudget bounds the evaluation of the filter on query nodes, so that a bad filter can't trigger
+// unbounded scans. The zero value of a bound is unbounded.
+type ExecutionBudget struct {
+	// ScanRows is the number of rows the filter may scan.
+	ScanRows int64
+	// Timeout is the time the filter may run.
+	Timeout time.Duration
+	// Sample degrades the evaluation to a random sample of the rows once the budget is exhausted, instead of aborting.
+	Sample bool
+}
+
+// WithExecutionBudget gives the execution budget of the filter. The hints `/*+ scan_budget(100000),
+// time_budget(500ms), sample_on_budget */` give the budget too, and can only tighten the one of this option.
+func WithExecutionBudget(budget ExecutionBudget) ParseOption {
+	return func(options *parseOptions) {
+		options.executionBudget = &budget
+	}
+}
+
+// ParseExecutionBudget returns the execution budget given by the hints of the expression and WithExecutionBudget,
+// and nil if there is none. Search plans carry the budget in their search params, the other requests have to
+// enforce it themselves, such as by the deadline of the request.
+func ParseExecutionBudget(exprStr string, opts ...ParseOption) (*ExecutionBudget, error) {
+	hints, _, err := ParseHints(exprStr)
+	if err != nil {
+		return nil, err
+	}
+	budget, err := budgetFromHints(hints)
+	if err != nil {
+		return nil, err
+	}
+	return mergeBudgets(newParseOptions(opts...).executionBudget, budget), nil
+}
+
+func budgetFromHints(hints []*Hint) (*ExecutionBudget, error) {
+	var budget *ExecutionBudget
+	for _, hint := range hints {
+		if _, ok := budgetHints[hint.Name]; !ok {
+			continue
+		}
+		if budget == nil {
+			budget = &ExecutionBudget{}
+		}
+		switch hint.Name {
+		case scanBudgetHint:
+			if len(hint.Args) != 1 {
+				return nil, fmt.Errorf("hint %s takes the number of rows", hint.Name)
+			}
+			rows, err := strconv.ParseInt(hint.Args[0], 10, 64)
+			if err != nil || rows <= 0 {
+				return nil, fmt.Errorf("hint %s takes a positive number of rows, but got: %s", hint.Name, hint.Args[0])
+			}
+			budget.ScanRows = rows
+		case timeBudgetHint:
+			if len(hint.Args) != 1 {
+				return nil, fmt.Errorf("hint %s takes a duration", hint.Name)
+			}
+			timeout, err := time.ParseDuration(hint.Args[0])
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("hint %s takes a positive duration such as 500ms, but got: %s", hint.Name, hint.Args[0])
+			}
+			budget.Timeout = timeout
+		case sampleOnBudgetHint:
+			if len(hint.Args) != 0 {
+				return nil, fmt.Errorf("hint %s takes no argument", hint.Name)
+			}
+			budget.Sample = true
+		}
+	}
+	return budget, nil
+}
+
+// mergeBudgets returns the tighter bounds of both budgets, the evaluation is degraded only if both allow it.
+func mergeBudgets(a, b *ExecutionBudget) *ExecutionBudget {
+	if a == nil || b == nil {
+		if a == nil {
+			return b
+		}
+		return a
+	}
+	tighter := func(x, y int64) int64 {
+		if x == 0 || (y != 0 && y < x) {
+			return y
+		}
+		return x
+	}
+	return &ExecutionBudget{
+		ScanRows: tighter(a.ScanRows, b.ScanRows),
+		Timeout:  time.Duration(tighter(int64(a.Timeout), int64(b.Timeout))),
+		Sample:   a.Sample && b.Sample,
+	}
+}
+
+// applyExecutionBudget declares the budget in the search params of the query info, merged with the one
+// already given by the search params.
+func applyExecutionBudget(budget *ExecutionBudget, queryInfo *planpb.QueryInfo) error {
+	params := make(map[string]interface{})
+	if queryInfo.GetSearchParams() != "" {
+		if err := json.Unmarshal([]byte(queryInfo.GetSearchParams()), &params); err != nil {
+			return fmt.Errorf("invalid search params: %w", err)
+		}
+	}
+	if value, ok := params[filterBudgetKey]; ok {
+		existing, err := parseFilterBudget(value)
+		if err != nil {
+			return err
+		}
+		budget = mergeBudgets(existing, budget)
+	}
+	params[filterBudgetKey] = budget.params()
+	searchParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	queryInfo.SearchParams = string(searchParams)
+	return nil
+}
+
+func (b *ExecutionBudget) params() map[string]interface{} {
+	params := map[string]interface{}{"on_exceeded": budgetAbort}
+	if b.Sample {
+		params["on_exceeded"] = budgetSample
+	}
+	if b.ScanRows > 0 {
+		params["scan_rows"] = b.ScanRows
+	}
+	if b.Timeout > 0 {
+		params["timeout_ms"] = b.Timeout.Milliseconds()
+	}
+	return params
+}
+
+// parseFilterBudget parses the filter_budget search param.
+func parseFilterBudget(value interface{}) (*ExecutionBudget, error) {
+	params, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, merr.WrapErrParameterInvalidMsg("search param %s must be an object, got %v", filterBudgetKey, value)
+	}
+	budget := &ExecutionBudget{}
+	for key, value := range params {
+		switch key {
+		case "scan_rows", "timeout_ms":
+			n, err := budgetNumber(value)
+			if err != nil || n <= 0 || n != float64(int64(n)) {
+				return nil, merr.WrapErrParameterInvalidMsg("%s.%s must be a positive integer, got %v", filterBudgetKey, key, value)
+			}
+			if key == "scan_rows" {
+				budget.ScanRows = int64(n)
+			} else {
+				budget.Timeout = time.Duration(n) * time.Millisecond
+			}
+		case "on_exceeded":
+			switch value {
+			case budgetAbort:
+			case budgetSample:
+				budget.Sample = true
+			default:
+				return nil, merr.WrapErrParameterInvalidMsg("%s.%s must be %s or %s, got %v", filterBudgetKey, key, budgetAbort, budgetSample, value)
+			}
+		default:
+			return nil, merr.WrapErrParameterInvalidMsg("unknown key %s of search param %s", key, filterBudgetKey)
+		}
+	}
+	return budget, nil
+}
+
+func budgetNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case json.Number:
+		return v.Float64()
+	default:
+		return 0, fmt.Errorf("not a number: %v", value)
+	}
+}
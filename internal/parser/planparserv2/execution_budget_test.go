@@ -0,0 +1,76 @@
+package planparserv2
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+func TestExecutionBudget(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	budget, err := ParseExecutionBudget(`/*+ scan_budget(100000), time_budget(1.5s), sample_on_budget */ Int64Field > 1`)
+	require.NoError(t, err)
+	assert.Equal(t, &ExecutionBudget{ScanRows: 100000, Timeout: 1500 * time.Millisecond, Sample: true}, budget)
+
+	budget, err = ParseExecutionBudget(`Int64Field > 1`)
+	require.NoError(t, err)
+	assert.Nil(t, budget)
+
+	// the hints only tighten the budget of the option.
+	budget, err = ParseExecutionBudget(`/*+ scan_budget(1000), time_budget(10s), sample_on_budget */ Int64Field > 1`,
+		WithExecutionBudget(ExecutionBudget{ScanRows: 5000, Timeout: time.Second}))
+	require.NoError(t, err)
+	assert.Equal(t, &ExecutionBudget{ScanRows: 1000, Timeout: time.Second}, budget)
+
+	for _, exprStr := range []string{
+		`/*+ scan_budget */ Int64Field > 1`,
+		`/*+ scan_budget(-1) */ Int64Field > 1`,
+		`/*+ scan_budget(1.5) */ Int64Field > 1`,
+		`/*+ time_budget(10) */ Int64Field > 1`,
+		`/*+ sample_on_budget(a) */ Int64Field > 1`,
+	} {
+		_, err = ParseExecutionBudget(exprStr)
+		assert.Error(t, err, exprStr)
+		_, err = CreateRetrievePlan(schema, exprStr, nil)
+		assert.Error(t, err, exprStr)
+	}
+
+	// search plans carry the budget in their search params.
+	plan, err := CreateSearchPlan(schema, `/*+ scan_budget(1000), iterative_filter */ Int64Field > 1`, "FloatVectorField",
+		&planpb.QueryInfo{Topk: 10, SearchParams: `{"nprobe":10,"filter_budget":{"scan_rows":500,"timeout_ms":200}}`}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "iterative_filter", plan.GetVectorAnns().GetQueryInfo().GetHints())
+	params := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal([]byte(plan.GetVectorAnns().GetQueryInfo().GetSearchParams()), &params))
+	assert.Equal(t, map[string]interface{}{"scan_rows": 500.0, "timeout_ms": 200.0, "on_exceeded": "abort"}, params[filterBudgetKey])
+	assert.Equal(t, 10.0, params["nprobe"])
+
+	plan, err = CreateSearchPlan(schema, ``, "FloatVectorField", nil, nil,
+		WithExecutionBudget(ExecutionBudget{Timeout: time.Second, Sample: true}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"filter_budget":{"timeout_ms":1000,"on_exceeded":"sample"}}`, plan.GetVectorAnns().GetQueryInfo().GetSearchParams())
+
+	// query plans accept the hints.
+	_, err = CreateRetrievePlan(schema, `/*+ scan_budget(1000) */ Int64Field > 1`, nil)
+	assert.NoError(t, err)
+
+	queryInfo, err := BuildQueryInfo(schema, "FloatVectorField", 10, "L2", `{"filter_budget":{"scan_rows":10,"on_exceeded":"sample"}}`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"filter_budget":{"scan_rows":10,"on_exceeded":"sample"}}`, queryInfo.GetSearchParams())
+	for _, searchParams := range []string{
+		`{"filter_budget":10}`,
+		`{"filter_budget":{"scan_rows":0}}`,
+		`{"filter_budget":{"on_exceeded":"skip"}}`,
+		`{"filter_budget":{"rows":10}}`,
+	} {
+		_, err = BuildQueryInfo(schema, "FloatVectorField", 10, "L2", searchParams)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid, searchParams)
+	}
+}
@@ -0,0 +1,87 @@
+package planparserv2
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// feedbackWeight is the weight of a new report in the moving averages of the feedback of a predicate.
+const feedbackWeight = 0.3
+
+// iterativeFilterSelectivity is the observed selectivity above which search plans use iterative filtering,
+// which is cheaper than building the bitset when most entities pass the filter.
+const iterativeFilterSelectivity = 0.5
+
+// ExecutionFeedback is the actual selectivity and latency of a predicate executed by a query node.
+type ExecutionFeedback struct {
+	Selectivity float64
+	Latency     time.Duration
+}
+
+type feedbackKey struct {
+	collectionID int64
+	predicate    string
+}
+
+var (
+	// feedbackCache holds the moving averages of reported feedback, and expires like exprCache.
+	feedbackCache = expirable.NewLRU[feedbackKey, ExecutionFeedback](1024, nil, time.Minute*10)
+	feedbackMu    sync.Mutex
+)
+
+// canonicalPredicate is the key of a predicate in the feedback cache. Both the proxy and query nodes
+// can compute it from the plan.
+func canonicalPredicate(expr *planpb.Expr) (string, bool) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(expr)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// ReportExecutionFeedback records the actual selectivity and latency of a predicate, or a sub-expression of it,
+// executed on the collection. Later plans use the feedback to choose the search hints and the order of conjunctions.
+func ReportExecutionFeedback(collectionID int64, predicate *planpb.Expr, feedback ExecutionFeedback) {
+	key, ok := canonicalPredicate(predicate)
+	if !ok {
+		return
+	}
+	feedbackMu.Lock()
+	defer feedbackMu.Unlock()
+	k := feedbackKey{collectionID: collectionID, predicate: key}
+	if old, ok := feedbackCache.Get(k); ok {
+		feedback.Selectivity = old.Selectivity + feedbackWeight*(feedback.Selectivity-old.Selectivity)
+		feedback.Latency = old.Latency + time.Duration(feedbackWeight*float64(feedback.Latency-old.Latency))
+	}
+	feedbackCache.Add(k, feedback)
+}
+
+// GetExecutionFeedback returns the averaged feedback reported for the predicate on the collection.
+func GetExecutionFeedback(collectionID int64, predicate *planpb.Expr) (ExecutionFeedback, bool) {
+	key, ok := canonicalPredicate(predicate)
+	if !ok {
+		return ExecutionFeedback{}, false
+	}
+	return feedbackCache.Get(feedbackKey{collectionID: collectionID, predicate: key})
+}
+
+func hasExecutionFeedback() bool {
+	return feedbackCache.Len() > 0
+}
+
+// applyFeedbackHints enables iterative filtering for the predicates observed to pass most entities,
+// unless the hints are given by the caller.
+func applyFeedbackHints(collectionID int64, predicate *planpb.Expr, queryInfo *planpb.QueryInfo) {
+	if predicate == nil || queryInfo == nil || queryInfo.GetHints() != "" || !hasExecutionFeedback() {
+		return
+	}
+	feedback, ok := GetExecutionFeedback(collectionID, predicate)
+	if ok && feedback.Selectivity >= iterativeFilterSelectivity {
+		queryInfo.Hints = searchHints["iterative_filter"]
+	}
+}
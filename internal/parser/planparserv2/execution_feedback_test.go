@@ -0,0 +1,80 @@
+package planparserv2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestExecutionFeedback(t *testing.T) {
+	schema := newTestSchemaHelper(t).WithEpoch("db", 1, 1)
+	defer feedbackCache.Purge()
+
+	expr, err := ParseExpr(schema, `Int64Field > 1`, nil)
+	require.NoError(t, err)
+	_, ok := GetExecutionFeedback(1, expr)
+	assert.False(t, ok)
+
+	ReportExecutionFeedback(1, expr, ExecutionFeedback{Selectivity: 0.2, Latency: 10 * time.Millisecond})
+	ReportExecutionFeedback(1, expr, ExecutionFeedback{Selectivity: 1, Latency: 20 * time.Millisecond})
+	feedback, ok := GetExecutionFeedback(1, expr)
+	require.True(t, ok)
+	assert.InDelta(t, 0.44, feedback.Selectivity, 1e-9)
+	assert.Equal(t, 13*time.Millisecond, feedback.Latency)
+	_, ok = GetExecutionFeedback(2, expr)
+	assert.False(t, ok)
+
+	// the same predicate parsed again shares the feedback, other values don't.
+	again, err := ParseExpr(schema, `Int64Field  >  1`, nil)
+	require.NoError(t, err)
+	_, ok = GetExecutionFeedback(1, again)
+	assert.True(t, ok)
+	other, err := ParseExpr(schema, `Int64Field > 2`, nil)
+	require.NoError(t, err)
+	_, ok = GetExecutionFeedback(1, other)
+	assert.False(t, ok)
+}
+
+func TestCreateSearchPlan_FeedbackHints(t *testing.T) {
+	schema := newTestSchemaHelper(t).WithEpoch("db", 1, 1)
+	defer feedbackCache.Purge()
+
+	plan, err := CreateSearchPlan(schema, `Int64Field > 1`, "FloatVectorField", &planpb.QueryInfo{}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, plan.GetVectorAnns().GetQueryInfo().GetHints())
+
+	predicate := plan.GetVectorAnns().GetPredicates()
+	ReportExecutionFeedback(1, predicate, ExecutionFeedback{Selectivity: 0.9})
+	plan, err = CreateSearchPlan(schema, `Int64Field > 1`, "FloatVectorField", &planpb.QueryInfo{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "iterative_filter", plan.GetVectorAnns().GetQueryInfo().GetHints())
+
+	// hints given by the caller are kept.
+	plan, err = CreateSearchPlan(schema, `/*+ no_iterative_filter */ Int64Field > 1`, "FloatVectorField", &planpb.QueryInfo{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "disable", plan.GetVectorAnns().GetQueryInfo().GetHints())
+
+	// selective predicates are not iteratively filtered.
+	ReportExecutionFeedback(1, predicate, ExecutionFeedback{Selectivity: 0})
+	ReportExecutionFeedback(1, predicate, ExecutionFeedback{Selectivity: 0})
+	plan, err = CreateSearchPlan(schema, `Int64Field > 1`, "FloatVectorField", &planpb.QueryInfo{}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, plan.GetVectorAnns().GetQueryInfo().GetHints())
+}
+
+func TestParseExpr_FeedbackOrder(t *testing.T) {
+	schema := newTestSchemaHelper(t).WithEpoch("db", 1, 1)
+	defer feedbackCache.Purge()
+
+	slow, err := ParseExpr(schema, `Int32Field > 1`, nil)
+	require.NoError(t, err)
+	ReportExecutionFeedback(1, slow, ExecutionFeedback{Selectivity: 0.001})
+
+	expr, err := ParseExpr(schema, `Int64Field == 1 and Int32Field > 1`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(104), expr.GetBinaryExpr().GetLeft().GetUnaryRangeExpr().GetColumnInfo().GetFieldId())
+}
@@ -0,0 +1,52 @@
+package planparserv2
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// Features reported by ExprFeatures in addition to the ones of ValidateExpr.
+const (
+	ExprFeatureJSONPath = "json_path"
+	ExprFeatureArray    = "array"
+	ExprFeatureTemplate = "template"
+	ExprFeatureCall     = "call"
+)
+
+// ExprFeatures returns the sorted distinct features a parsed expression uses, for usage telemetry. They are the
+// features reported by ValidateExpr, with all the functions reported as "call" to keep them few, plus "json_path",
+// "array" and "template" for the expressions reading json paths or array fields, or built from templates.
+func ExprFeatures(schema *typeutil.SchemaHelper, expr *planpb.Expr) ([]string, error) {
+	collector := newExprReportCollector(schema, &ExprReport{})
+	if err := collector.collect(expr); err != nil {
+		return nil, err
+	}
+	features := make(map[string]struct{}, len(collector.features))
+	for feature := range collector.features {
+		if strings.HasPrefix(feature, ExprFeatureCall+":") {
+			feature = ExprFeatureCall
+		}
+		features[feature] = struct{}{}
+	}
+	for _, field := range collector.report.Fields {
+		if typeutil.IsJSONType(field.DataType) && len(field.NestedPath) != 0 {
+			features[ExprFeatureJSONPath] = struct{}{}
+		}
+		if typeutil.IsArrayType(field.DataType) {
+			features[ExprFeatureArray] = struct{}{}
+		}
+	}
+	if expr.GetIsTemplate() || len(collector.report.TemplateSlots) != 0 {
+		features[ExprFeatureTemplate] = struct{}{}
+	}
+
+	ret := make([]string, 0, len(features))
+	for feature := range features {
+		ret = append(ret, feature)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
@@ -0,0 +1,36 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestExprFeatures(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	cases := []struct {
+		expr     string
+		features []string
+	}{
+		{`Int64Field > 1`, []string{"compare"}},
+		{`VarCharField like "%a_c"`, []string{"like", "regex"}},
+		{`JSONField["a"]["b"] == 1 and VarCharField like "abc%"`, []string{ExprFeatureJSONPath, "compare", "like", "logical"}},
+		{`array_contains(ArrayField, 1)`, []string{ExprFeatureArray, "json_contains"}},
+		{`array_length(ArrayField) == 1`, []string{ExprFeatureArray, "array_length"}},
+		{`Int64Field in {v}`, []string{"in", ExprFeatureTemplate}},
+	}
+	for _, c := range cases {
+		expr, err := ParseExpr(helper, c.expr, map[string]*schemapb.TemplateValue{
+			"v": generateTemplateValue(schemapb.DataType_Array, generateTemplateArrayValue(schemapb.DataType_Int64, []int64{1})),
+		})
+		require.NoError(t, err, c.expr)
+		features, err := ExprFeatures(helper, expr)
+		require.NoError(t, err, c.expr)
+		assert.ElementsMatch(t, c.features, features, c.expr)
+		assert.IsNonDecreasing(t, features, c.expr)
+	}
+}
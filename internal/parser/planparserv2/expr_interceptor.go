@@ -0,0 +1,95 @@
+package planparserv2
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// ExprInterceptor inspects a parsed expression before the plan is built. It returns the expression
+// to continue with, which may be rewritten, or an error to reject the request.
+type ExprInterceptor interface {
+	Intercept(ctx context.Context, schema *typeutil.SchemaHelper, expr *planpb.Expr) (*planpb.Expr, error)
+}
+
+// ExprInterceptorFunc adapts a function to ExprInterceptor.
+type ExprInterceptorFunc func(ctx context.Context, schema *typeutil.SchemaHelper, expr *planpb.Expr) (*planpb.Expr, error)
+
+func (f ExprInterceptorFunc) Intercept(ctx context.Context, schema *typeutil.SchemaHelper, expr *planpb.Expr) (*planpb.Expr, error) {
+	return f(ctx, schema, expr)
+}
+
+// globalInterceptors is the collection id of the interceptors applied to all collections.
+const globalInterceptors int64 = -1
+
+type interceptorKey struct {
+	collectionID int64
+	name         string
+}
+
+var (
+	interceptorsMu sync.RWMutex
+	interceptors   = make(map[interceptorKey]ExprInterceptor)
+)
+
+// RegisterExprInterceptor registers an interceptor applied to all collections, replacing the one of the same name.
+func RegisterExprInterceptor(name string, interceptor ExprInterceptor) {
+	RegisterCollectionExprInterceptor(globalInterceptors, name, interceptor)
+}
+
+// RegisterCollectionExprInterceptor registers an interceptor applied to the collection, identified by the
+// collection id the schema helper is tagged with by WithEpoch.
+func RegisterCollectionExprInterceptor(collectionID int64, name string, interceptor ExprInterceptor) {
+	interceptorsMu.Lock()
+	defer interceptorsMu.Unlock()
+	interceptors[interceptorKey{collectionID: collectionID, name: name}] = interceptor
+}
+
+// UnregisterExprInterceptor removes the interceptor applied to all collections.
+func UnregisterExprInterceptor(name string) {
+	UnregisterCollectionExprInterceptor(globalInterceptors, name)
+}
+
+// UnregisterCollectionExprInterceptor removes the interceptor applied to the collection.
+func UnregisterCollectionExprInterceptor(collectionID int64, name string) {
+	interceptorsMu.Lock()
+	defer interceptorsMu.Unlock()
+	delete(interceptors, interceptorKey{collectionID: collectionID, name: name})
+}
+
+// getExprInterceptors returns the global interceptors followed by the ones of the collection, each ordered by name.
+func getExprInterceptors(collectionID int64) []ExprInterceptor {
+	interceptorsMu.RLock()
+	defer interceptorsMu.RUnlock()
+	keys := make([]interceptorKey, 0)
+	for key := range interceptors {
+		if key.collectionID == globalInterceptors || key.collectionID == collectionID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i].collectionID == globalInterceptors) != (keys[j].collectionID == globalInterceptors) {
+			return keys[i].collectionID == globalInterceptors
+		}
+		return keys[i].name < keys[j].name
+	})
+	ret := make([]ExprInterceptor, 0, len(keys))
+	for _, key := range keys {
+		ret = append(ret, interceptors[key])
+	}
+	return ret
+}
+
+func applyExprInterceptors(ctx context.Context, schema *typeutil.SchemaHelper, expr *planpb.Expr) (*planpb.Expr, error) {
+	for _, interceptor := range getExprInterceptors(schema.GetCollectionID()) {
+		var err error
+		expr, err = interceptor.Intercept(ctx, schema, expr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expr, nil
+}
@@ -0,0 +1,61 @@
+package planparserv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestParseExpr_ExprInterceptor(t *testing.T) {
+	schema := newTestSchemaHelper(t).WithEpoch("db", 1, 1)
+	errRegexScan := errors.New("full collection regex scan is not allowed")
+
+	var calls []string
+	RegisterExprInterceptor("b_block_regex", ExprInterceptorFunc(func(ctx context.Context, schema *typeutil.SchemaHelper, expr *planpb.Expr) (*planpb.Expr, error) {
+		calls = append(calls, "b_block_regex")
+		if expr.GetUnaryRangeExpr().GetOp() == planpb.OpType_Match {
+			return nil, errRegexScan
+		}
+		return expr, nil
+	}))
+	defer UnregisterExprInterceptor("b_block_regex")
+	RegisterExprInterceptor("a_log", ExprInterceptorFunc(func(ctx context.Context, schema *typeutil.SchemaHelper, expr *planpb.Expr) (*planpb.Expr, error) {
+		calls = append(calls, "a_log")
+		return expr, nil
+	}))
+	defer UnregisterExprInterceptor("a_log")
+	RegisterCollectionExprInterceptor(1, "0_rewrite", ExprInterceptorFunc(func(ctx context.Context, schema *typeutil.SchemaHelper, expr *planpb.Expr) (*planpb.Expr, error) {
+		calls = append(calls, "0_rewrite")
+		return alwaysTrueExpr(), nil
+	}))
+
+	_, err := ParseExpr(schema, `VarCharField like "%a%"`, nil)
+	assert.ErrorIs(t, err, errRegexScan)
+	assert.Equal(t, []string{"a_log", "b_block_regex"}, calls)
+
+	// global interceptors run before the ones of the collection.
+	calls = nil
+	expr, err := ParseExpr(schema, `VarCharField like "a%"`, nil)
+	require.NoError(t, err)
+	assert.True(t, isAlwaysTrueExpr(expr))
+	assert.Equal(t, []string{"a_log", "b_block_regex", "0_rewrite"}, calls)
+
+	// the interceptors of other collections don't apply.
+	calls = nil
+	expr, err = ParseExpr(newTestSchemaHelper(t), `VarCharField like "a%"`, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+	assert.Equal(t, []string{"a_log", "b_block_regex"}, calls)
+
+	UnregisterCollectionExprInterceptor(1, "0_rewrite")
+	calls = nil
+	_, err = ParseExpr(schema, `VarCharField like "a%"`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a_log", "b_block_regex"}, calls)
+}
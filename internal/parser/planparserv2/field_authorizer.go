@@ -0,0 +1,33 @@
+package planparserv2
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// FieldAuthorizer is consulted whenever the visitor resolves a field, with the request context set by WithContext.
+// It rejects filtering on the field by returning an error, usually wrapping merr.ErrPrivilegeNotPermitted,
+// which is returned to the caller of ParseExpr as is.
+type FieldAuthorizer func(ctx context.Context, collectionID int64, fieldID int64) error
+
+var fieldAuthorizer atomic.Pointer[FieldAuthorizer]
+
+// SetFieldAuthorizer sets the hook used to authorize the fields read by expressions. A nil authorizer allows all fields.
+func SetFieldAuthorizer(authorizer FieldAuthorizer) {
+	if authorizer == nil {
+		fieldAuthorizer.Store(nil)
+		return
+	}
+	fieldAuthorizer.Store(&authorizer)
+}
+
+func authorizeField(ctx context.Context, schema *typeutil.SchemaHelper, field *schemapb.FieldSchema) error {
+	authorizer := fieldAuthorizer.Load()
+	if authorizer == nil {
+		return nil
+	}
+	return (*authorizer)(ctx, schema.GetCollectionID(), field.GetFieldID())
+}
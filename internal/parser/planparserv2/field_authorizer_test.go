@@ -0,0 +1,50 @@
+package planparserv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+type testRoleKey struct{}
+
+func TestParseExpr_FieldAuthorizer(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	varcharField, err := schema.GetFieldFromName("VarCharField")
+	require.NoError(t, err)
+	dynamicField, err := schema.GetDynamicField()
+	require.NoError(t, err)
+
+	SetFieldAuthorizer(func(ctx context.Context, collectionID int64, fieldID int64) error {
+		role, _ := ctx.Value(testRoleKey{}).(string)
+		if role != "admin" && (fieldID == varcharField.GetFieldID() || fieldID == dynamicField.GetFieldID()) {
+			return merr.WrapErrPrivilegeNotPermitted("role %s cannot filter on field %d", role, fieldID)
+		}
+		return nil
+	})
+	defer SetFieldAuthorizer(nil)
+
+	guest := WithContext(context.WithValue(context.Background(), testRoleKey{}, "guest"))
+	admin := WithContext(context.WithValue(context.Background(), testRoleKey{}, "admin"))
+	for _, exprStr := range []string{
+		`VarCharField == "a"`,
+		`Int64Field > 1 and VarCharField like "a%"`,
+		`text_match(VarCharField, "a")`,
+		`$meta["a"] > 1`,
+		`a > 1`,
+	} {
+		_, err = ParseExpr(schema, exprStr, nil, guest)
+		assert.ErrorIs(t, err, merr.ErrPrivilegeNotPermitted, exprStr)
+	}
+	_, err = ParseExpr(schema, `Int64Field > 1`, nil, guest)
+	assert.NoError(t, err)
+	_, err = ParseExpr(schema, `VarCharField == "a" and a > 1`, nil, admin)
+	assert.NoError(t, err)
+	// without request context.
+	_, err = ParseExpr(schema, `VarCharField == "a"`, nil)
+	assert.ErrorIs(t, err, merr.ErrPrivilegeNotPermitted)
+}
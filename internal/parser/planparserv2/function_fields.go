@@ -0,0 +1,47 @@
+package planparserv2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// checkFunctionOutput rejects the output fields of the schema functions which can't be filtered. The scalar
+// outputs are queryable like any other field, while the vectors of BM25 and embedding functions are only searched.
+func checkFunctionOutput(schema *typeutil.SchemaHelper, field *schemapb.FieldSchema) error {
+	if !field.GetIsFunctionOutput() {
+		return nil
+	}
+	function, err := schema.GetFunctionByOutputField(field)
+	if err != nil {
+		return fmt.Errorf("field %s is the output of a function which doesn't exist", field.GetName())
+	}
+	if !typeutil.IsVectorType(field.GetDataType()) {
+		return nil
+	}
+	inputs := strings.Join(function.GetInputFieldNames(), ", ")
+	if function.GetType() == schemapb.FunctionType_BM25 && len(function.GetInputFieldNames()) == 1 {
+		return fmt.Errorf("field %s is the output of the BM25 function %s, which can't be filtered, use text_match(%s, ...) on its input field instead",
+			field.GetName(), function.GetName(), inputs)
+	}
+	return fmt.Errorf("field %s is the output of the %s function %s, which can't be filtered, filter on its input fields %s instead",
+		field.GetName(), function.GetType().String(), function.GetName(), inputs)
+}
+
+// describeFunctionInput explains what the functions reading the field are for, so that the errors on the input
+// fields of functions don't look like the functions have no effect.
+func describeFunctionInput(schema *typeutil.SchemaHelper, field *schemapb.FieldSchema) string {
+	var descriptions []string
+	for _, function := range schema.GetSchema().GetFunctions() {
+		for _, input := range function.GetInputFieldNames() {
+			if input == field.GetName() {
+				descriptions = append(descriptions, fmt.Sprintf("the %s function %s only serves searches on %s",
+					function.GetType().String(), function.GetName(), strings.Join(function.GetOutputFieldNames(), ", ")))
+				break
+			}
+		}
+	}
+	return strings.Join(descriptions, ", ")
+}
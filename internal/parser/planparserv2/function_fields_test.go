@@ -0,0 +1,46 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestFunctionFields(t *testing.T) {
+	schema, err := typeutil.CreateSchemaHelper(&schemapb.CollectionSchema{
+		Name: "test",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "id", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "text", DataType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{{Key: "max_length", Value: "256"}}},
+			{FieldID: 102, Name: "sparse", DataType: schemapb.DataType_SparseFloatVector, IsFunctionOutput: true},
+			{FieldID: 103, Name: "embedding", DataType: schemapb.DataType_FloatVector, IsFunctionOutput: true,
+				TypeParams: []*commonpb.KeyValuePair{{Key: "dim", Value: "4"}}},
+			{FieldID: 104, Name: "length", DataType: schemapb.DataType_Int64, IsFunctionOutput: true},
+			{FieldID: 105, Name: "orphan", DataType: schemapb.DataType_Int64, IsFunctionOutput: true},
+		},
+		Functions: []*schemapb.FunctionSchema{
+			{Name: "bm25", Type: schemapb.FunctionType_BM25, InputFieldNames: []string{"text"}, OutputFieldNames: []string{"sparse"}, OutputFieldIds: []int64{102}},
+			{Name: "embed", Type: schemapb.FunctionType_TextEmbedding, InputFieldNames: []string{"text"}, OutputFieldNames: []string{"embedding"}, OutputFieldIds: []int64{103}},
+			{Name: "count", Type: schemapb.FunctionType_Unknown, InputFieldNames: []string{"text"}, OutputFieldNames: []string{"length"}, OutputFieldIds: []int64{104}},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = ParseExpr(schema, `length > 10 and text == "a"`, nil)
+	assert.NoError(t, err)
+
+	_, err = ParseExpr(schema, `sparse == 1`, nil)
+	assert.ErrorContains(t, err, "field sparse is the output of the BM25 function bm25, which can't be filtered, use text_match(text, ...)")
+	_, err = ParseExpr(schema, `embedding == 1`, nil)
+	assert.ErrorContains(t, err, "field embedding is the output of the TextEmbedding function embed")
+	_, err = ParseExpr(schema, `orphan > 1`, nil)
+	assert.ErrorContains(t, err, "function which doesn't exist")
+
+	_, err = ParseExpr(schema, `text_match(text, "a")`, nil)
+	assert.ErrorContains(t, err, "field text does not enable text match, the BM25 function bm25 only serves searches on sparse")
+}
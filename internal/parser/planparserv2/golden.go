@@ -0,0 +1,189 @@
+package planparserv2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// GoldenPlan encodes the plan as a stable text for golden file comparisons, so that the suites embedding milvus can
+// detect the plan changes across upgrades. Unlike prototext, whose output is deliberately unstable, the encoding only
+// changes with the plan: the field IDs are resolved to the field names, the fields are written in the order of their
+// numbers, the output fields and map entries are sorted and the search params are written as canonical json.
+func GoldenPlan(schema *typeutil.SchemaHelper, plan *planpb.PlanNode) (string, error) {
+	return golden(schema, plan)
+}
+
+// GoldenExpr encodes the expression like GoldenPlan.
+func GoldenExpr(schema *typeutil.SchemaHelper, expr *planpb.Expr) (string, error) {
+	return golden(schema, expr)
+}
+
+func golden(schema *typeutil.SchemaHelper, message proto.Message) (string, error) {
+	w := &goldenWriter{schema: schema}
+	if message.ProtoReflect().IsValid() {
+		w.writeMessage(message.ProtoReflect(), 0)
+	}
+	if w.err != nil {
+		return "", w.err
+	}
+	return w.buf.String(), nil
+}
+
+type goldenWriter struct {
+	schema *typeutil.SchemaHelper
+	buf    strings.Builder
+	err    error
+}
+
+func (w *goldenWriter) writeLine(depth int, format string, args ...interface{}) {
+	w.buf.WriteString(strings.Repeat("  ", depth))
+	fmt.Fprintf(&w.buf, format, args...)
+	w.buf.WriteByte('\n')
+}
+
+func (w *goldenWriter) writeMessage(message protoreflect.Message, depth int) {
+	descriptors := message.Descriptor().Fields()
+	fields := make([]protoreflect.FieldDescriptor, 0, descriptors.Len())
+	for i := 0; i < descriptors.Len(); i++ {
+		if field := descriptors.Get(i); message.Has(field) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Number() < fields[j].Number()
+	})
+
+	for _, field := range fields {
+		name := string(field.Name())
+		value := message.Get(field)
+		switch {
+		case isFieldIDField(field):
+			w.writeFieldNames(field, value, depth)
+		case field.IsMap():
+			w.writeMap(name, field, value.Map(), depth)
+		case field.IsList():
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				w.writeValue(name, field, list.Get(i), depth)
+			}
+		default:
+			w.writeValue(name, field, value, depth)
+		}
+	}
+}
+
+func (w *goldenWriter) writeValue(name string, field protoreflect.FieldDescriptor, value protoreflect.Value, depth int) {
+	if field.Message() != nil {
+		w.writeLine(depth, "%s {", name)
+		w.writeMessage(value.Message(), depth+1)
+		w.writeLine(depth, "}")
+		return
+	}
+	w.writeLine(depth, "%s: %s", name, w.formatScalar(field, value))
+}
+
+func (w *goldenWriter) writeMap(name string, field protoreflect.FieldDescriptor, entries protoreflect.Map, depth int) {
+	keys := make([]protoreflect.MapKey, 0, entries.Len())
+	entries.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	for _, key := range keys {
+		w.writeValue(fmt.Sprintf("%s[%s]", name, w.formatScalar(field.MapKey(), key.Value())), field.MapValue(), entries.Get(key), depth)
+	}
+}
+
+// writeFieldNames writes the field IDs as field names, `output_field_ids` as `output_fields` for example.
+func (w *goldenWriter) writeFieldNames(field protoreflect.FieldDescriptor, value protoreflect.Value, depth int) {
+	name := string(field.Name())
+	if !field.IsList() {
+		w.writeLine(depth, "%s: %s", strings.TrimSuffix(name, "_id"), w.fieldName(value.Int()))
+		return
+	}
+	list := value.List()
+	names := make([]string, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		names = append(names, w.fieldName(list.Get(i).Int()))
+	}
+	sort.Strings(names)
+	w.writeLine(depth, "%s: [%s]", strings.TrimSuffix(name, "_ids")+"s", strings.Join(names, ", "))
+}
+
+func (w *goldenWriter) fieldName(fieldID int64) string {
+	switch {
+	case fieldID < 0:
+		// unset, like the group by field of a search without grouping.
+		return strconv.FormatInt(fieldID, 10)
+	case fieldID == common.RowIDField:
+		return strconv.Quote(common.RowIDFieldName)
+	case fieldID == common.TimeStampField:
+		return strconv.Quote(common.TimeStampFieldName)
+	}
+	field, err := w.schema.GetFieldFromID(fieldID)
+	if err != nil {
+		if w.err == nil {
+			w.err = fmt.Errorf("cannot encode plan: %w", err)
+		}
+		return strconv.FormatInt(fieldID, 10)
+	}
+	return strconv.Quote(field.GetName())
+}
+
+func (w *goldenWriter) formatScalar(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch field.Kind() {
+	case protoreflect.EnumKind:
+		if enum := field.Enum().Values().ByNumber(value.Enum()); enum != nil {
+			return string(enum.Name())
+		}
+		return strconv.FormatInt(int64(value.Enum()), 10)
+	case protoreflect.StringKind:
+		if field.Name() == "search_params" {
+			return strconv.Quote(canonicalJSON(value.String()))
+		}
+		return strconv.Quote(value.String())
+	case protoreflect.BytesKind:
+		return strconv.Quote(string(value.Bytes()))
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64)
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// isFieldIDField returns whether the field holds the IDs of schema fields, like `field_id` and `output_field_ids`.
+func isFieldIDField(field protoreflect.FieldDescriptor) bool {
+	if field.Kind() != protoreflect.Int64Kind || field.IsMap() {
+		return false
+	}
+	name := string(field.Name())
+	if field.IsList() {
+		return strings.HasSuffix(name, "field_ids")
+	}
+	return strings.HasSuffix(name, "field_id")
+}
+
+// canonicalJSON returns the json with sorted keys and without spaces, or the string itself if it's not json.
+func canonicalJSON(s string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(s), &value); err != nil {
+		return s
+	}
+	bs, err := json.Marshal(value)
+	if err != nil {
+		return s
+	}
+	return string(bs)
+}
@@ -0,0 +1,73 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestGoldenPlan(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	plan, err := CreateRetrievePlan(schema, "Int64Field > 1", nil)
+	require.NoError(t, err)
+	plan.OutputFieldIds = []int64{103, 1, 101}
+	text, err := GoldenPlan(schema, plan)
+	require.NoError(t, err)
+	assert.Equal(t, `output_fields: ["BoolField", "Int16Field", "Timestamp"]
+query {
+  predicates {
+    unary_range_expr {
+      column_info {
+        field: "Int64Field"
+        data_type: Int64
+      }
+      op: GreaterThan
+      value {
+        int64_val: 1
+      }
+    }
+  }
+}
+`, text)
+
+	search := func(searchParams string) string {
+		plan, err := CreateSearchPlan(schema, "VarCharField == 'a'", "FloatVectorField", &planpb.QueryInfo{
+			Topk:           10,
+			MetricType:     "L2",
+			SearchParams:   searchParams,
+			GroupByFieldId: -1,
+		}, nil)
+		require.NoError(t, err)
+		text, err := GoldenPlan(schema, plan)
+		require.NoError(t, err)
+		return text
+	}
+	text = search(`{"nprobe": 8, "ef": 16}`)
+	assert.Contains(t, text, "  field: \"FloatVectorField\"\n")
+	assert.Contains(t, text, "    group_by_field: -1\n")
+	assert.Contains(t, text, `    search_params: "{\"ef\":16,\"nprobe\":8}"`)
+	assert.Equal(t, text, search(`{"ef":16,"nprobe":8}`))
+
+	plan.OutputFieldIds = []int64{12345}
+	_, err = GoldenPlan(schema, plan)
+	assert.Error(t, err)
+}
+
+func TestGoldenExpr(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	expr, err := ParseExpr(schema, "VarCharField in ['b', 'a']", nil)
+	require.NoError(t, err)
+	text, err := GoldenExpr(schema, expr)
+	require.NoError(t, err)
+	assert.Contains(t, text, "term_expr {\n")
+	assert.Contains(t, text, "    field: \"VarCharField\"\n")
+
+	text, err = GoldenExpr(schema, nil)
+	require.NoError(t, err)
+	assert.Empty(t, text)
+}
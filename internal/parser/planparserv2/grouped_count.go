@@ -0,0 +1,75 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// GroupedCountPlan counts the entities matching an expression per value of a scalar field, like
+// `SELECT field, count(*) ... GROUP BY field`. The plan retrieves the group by field of the matched
+// entities, whose values are counted by Count.
+type GroupedCountPlan struct {
+	Plan         *planpb.PlanNode
+	GroupByField *schemapb.FieldSchema
+}
+
+// CreateGroupedCountPlan creates the plan counting entities matching the expression per value of the group by field.
+func CreateGroupedCountPlan(schema *typeutil.SchemaHelper, exprStr string, groupByFieldName string,
+	exprTemplateValues map[string]*schemapb.TemplateValue, opts ...ParseOption,
+) (*GroupedCountPlan, error) {
+	field, err := schema.GetFieldFromName(groupByFieldName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkGroupByType(field); err != nil {
+		return nil, err
+	}
+	plan, err := CreateRetrievePlan(schema, exprStr, exprTemplateValues, opts...)
+	if err != nil {
+		return nil, err
+	}
+	plan.OutputFieldIds = []int64{field.GetFieldID()}
+	return &GroupedCountPlan{Plan: plan, GroupByField: field}, nil
+}
+
+// Count adds the number of entities of each value of the retrieved group by field to counts.
+// Null values are counted under the nil key.
+func (p *GroupedCountPlan) Count(data *schemapb.FieldData, counts map[interface{}]int64) error {
+	if data.GetFieldId() != p.GroupByField.GetFieldID() {
+		return fmt.Errorf("field %d is not the group by field %s", data.GetFieldId(), p.GroupByField.GetName())
+	}
+	var values []interface{}
+	scalars := data.GetScalars()
+	switch p.GroupByField.GetDataType() {
+	case schemapb.DataType_Bool:
+		for _, v := range scalars.GetBoolData().GetData() {
+			values = append(values, v)
+		}
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		for _, v := range scalars.GetIntData().GetData() {
+			values = append(values, int64(v))
+		}
+	case schemapb.DataType_Int64:
+		for _, v := range scalars.GetLongData().GetData() {
+			values = append(values, v)
+		}
+	default:
+		for _, v := range scalars.GetStringData().GetData() {
+			values = append(values, v)
+		}
+	}
+	validData := data.GetValidData()
+	if len(validData) != 0 && len(validData) != len(values) {
+		return fmt.Errorf("length of valid data %d doesn't match the number of values %d", len(validData), len(values))
+	}
+	for i, v := range values {
+		if len(validData) != 0 && !validData[i] {
+			v = nil
+		}
+		counts[v]++
+	}
+	return nil
+}
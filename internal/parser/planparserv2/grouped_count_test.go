@@ -0,0 +1,54 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestCreateGroupedCountPlan(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	plan, err := CreateGroupedCountPlan(schema, `Int64Field > 1`, "VarCharField", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{121}, plan.Plan.GetOutputFieldIds())
+	assert.NotNil(t, plan.Plan.GetQuery().GetPredicates().GetUnaryRangeExpr())
+
+	counts := make(map[interface{}]int64)
+	data := &schemapb.FieldData{
+		FieldId: 121,
+		Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"a", "b", "a", ""}}},
+		}},
+		ValidData: []bool{true, true, true, false},
+	}
+	require.NoError(t, plan.Count(data, counts))
+	require.NoError(t, plan.Count(data, counts))
+	assert.Equal(t, map[interface{}]int64{"a": 4, "b": 2, nil: 2}, counts)
+
+	data.ValidData = []bool{true}
+	assert.Error(t, plan.Count(data, counts))
+	data.FieldId = 105
+	assert.Error(t, plan.Count(data, counts))
+
+	plan, err = CreateGroupedCountPlan(schema, ``, "Int8Field", nil)
+	require.NoError(t, err)
+	counts = make(map[interface{}]int64)
+	require.NoError(t, plan.Count(&schemapb.FieldData{
+		FieldId: 102,
+		Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: []int32{1, 2, 1}}},
+		}},
+	}, counts))
+	assert.Equal(t, map[interface{}]int64{int64(1): 2, int64(2): 1}, counts)
+
+	for _, field := range []string{"FloatField", "JSONField", "FloatVectorField", "NotExistField"} {
+		_, err := CreateGroupedCountPlan(schema, ``, field, nil)
+		assert.Error(t, err, field)
+	}
+	_, err = CreateGroupedCountPlan(schema, `Int64Field >`, "VarCharField", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package planparserv2
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// Hint is an optimizer or executor hint given by a `/*+ ... */` comment at the start of an expression,
+// such as `/*+ iterative_filter, no_index(color) */`.
+type Hint struct {
+	Name string
+	Args []string
+}
+
+var (
+	hintNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	// hintArgPattern also accepts numbers and durations, such as `scan_budget(100000)` or `time_budget(1.5s)`.
+	hintArgPattern = regexp.MustCompile(`^[a-zA-Z0-9_.]+$`)
+)
+
+// refreshCacheHint forces re-parsing the expression and overwrites its cache entry, like WithCacheRefresh.
+const refreshCacheHint = "refresh_cache"
+
+// parserHints are the hints consumed by the parser, which are not carried by plans.
+var parserHints = map[string]struct{}{
+	refreshCacheHint: {},
+}
+
+// searchHints maps the hints supported by search plans to the value of QueryInfo.Hints.
+var searchHints = map[string]string{
+	"iterative_filter":    "iterative_filter",
+	"no_iterative_filter": "disable",
+}
+
+// ParseHints splits the leading hint comment from an expression, and returns the hints with the rest of the expression.
+func ParseHints(exprStr string) ([]*Hint, string, error) {
+	trimmed := strings.TrimLeft(exprStr, " \t\r\n")
+	if !strings.HasPrefix(trimmed, "/*+") {
+		return nil, exprStr, nil
+	}
+	end := strings.Index(trimmed, "*/")
+	if end < 0 {
+		return nil, "", fmt.Errorf("hint comment is not closed: %s", displayExpr(exprStr))
+	}
+	// the rest is trimmed to share the cache entry with the expression without hints.
+	body, rest := trimmed[len("/*+"):end], strings.TrimLeft(trimmed[end+len("*/"):], " \t\r\n")
+
+	hints := make([]*Hint, 0)
+	for len(strings.TrimSpace(body)) > 0 {
+		var item string
+		// commas inside the arguments don't split hints.
+		if open, comma := strings.Index(body, "("), strings.Index(body, ","); open >= 0 && (comma < 0 || open < comma) {
+			closing := strings.Index(body, ")")
+			if closing < open {
+				return nil, "", fmt.Errorf("invalid hint: %s", strings.TrimSpace(body))
+			}
+			item, body = body[:closing+1], body[closing+1:]
+			if next := strings.TrimSpace(body); next != "" && !strings.HasPrefix(next, ",") {
+				return nil, "", fmt.Errorf("invalid hint: %s", strings.TrimSpace(item+body))
+			}
+			body = strings.TrimPrefix(strings.TrimSpace(body), ",")
+		} else if comma >= 0 {
+			item, body = body[:comma], body[comma+1:]
+		} else {
+			item, body = body, ""
+		}
+		hint, err := parseHint(strings.TrimSpace(item))
+		if err != nil {
+			return nil, "", err
+		}
+		hints = append(hints, hint)
+	}
+	return hints, rest, nil
+}
+
+func parseHint(item string) (*Hint, error) {
+	hint := &Hint{Name: item}
+	if open := strings.Index(item, "("); open >= 0 {
+		hint.Name = strings.TrimSpace(item[:open])
+		for _, arg := range strings.Split(item[open+1:len(item)-1], ",") {
+			if arg = strings.TrimSpace(arg); !hintArgPattern.MatchString(arg) {
+				return nil, fmt.Errorf("invalid argument of hint %s: %q", hint.Name, arg)
+			}
+			hint.Args = append(hint.Args, arg)
+		}
+	}
+	if !hintNamePattern.MatchString(hint.Name) {
+		return nil, fmt.Errorf("invalid hint: %q", item)
+	}
+	hint.Name = strings.ToLower(hint.Name)
+	return hint, nil
+}
+
+// applySearchHints sets the hints of the expression to the query info of a search plan,
+// hints which the plan cannot carry are rejected instead of being ignored silently.
+func applySearchHints(hints []*Hint, queryInfo *planpb.QueryInfo) error {
+	for _, hint := range hints {
+		if _, ok := parserHints[hint.Name]; ok {
+			continue
+		}
+		if _, ok := budgetHints[hint.Name]; ok {
+			continue
+		}
+		value, ok := searchHints[hint.Name]
+		if !ok || len(hint.Args) != 0 {
+			return fmt.Errorf("hint %s is not supported by search", hint.Name)
+		}
+		if queryInfo.GetHints() != "" && queryInfo.GetHints() != value {
+			return fmt.Errorf("hint %s conflicts with the hints %s of search params", hint.Name, queryInfo.GetHints())
+		}
+		queryInfo.Hints = value
+	}
+	return nil
+}
+
+func hasHint(hints []*Hint, name string) bool {
+	for _, hint := range hints {
+		if hint.Name == name {
+			return true
+		}
+	}
+	return false
+}
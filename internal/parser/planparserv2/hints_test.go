@@ -0,0 +1,69 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestParseHints(t *testing.T) {
+	hints, rest, err := ParseHints(` /*+ iterative_filter, NO_INDEX(color, size), hash_join */ Int64Field > 1`)
+	require.NoError(t, err)
+	assert.Equal(t, []*Hint{
+		{Name: "iterative_filter"},
+		{Name: "no_index", Args: []string{"color", "size"}},
+		{Name: "hash_join"},
+	}, hints)
+	assert.Equal(t, "Int64Field > 1", rest)
+
+	hints, rest, err = ParseHints(`Int64Field > 1`)
+	require.NoError(t, err)
+	assert.Empty(t, hints)
+	assert.Equal(t, `Int64Field > 1`, rest)
+
+	hints, rest, err = ParseHints(`/*+ */`)
+	require.NoError(t, err)
+	assert.Empty(t, hints)
+	assert.Equal(t, ``, rest)
+
+	for _, exprStr := range []string{
+		`/*+ iterative_filter Int64Field > 1`,
+		`/*+ a b */ Int64Field > 1`,
+		`/*+ a, , b */ Int64Field > 1`,
+		`/*+ no_index(color */ Int64Field > 1`,
+		`/*+ no_index() */ Int64Field > 1`,
+		`/*+ no_index(a) b */ Int64Field > 1`,
+		`/*+ no_index("a") */ Int64Field > 1`,
+	} {
+		_, _, err = ParseHints(exprStr)
+		assert.Error(t, err, exprStr)
+	}
+}
+
+func TestCreatePlan_Hints(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	plan, err := CreateSearchPlan(schema, `/*+ iterative_filter */ Int64Field > 1`, "FloatVectorField", &planpb.QueryInfo{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "iterative_filter", plan.GetVectorAnns().GetQueryInfo().GetHints())
+	assert.NotNil(t, plan.GetVectorAnns().GetPredicates().GetUnaryRangeExpr())
+
+	plan, err = CreateSearchPlan(schema, `/*+ no_iterative_filter */`, "FloatVectorField", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "disable", plan.GetVectorAnns().GetQueryInfo().GetHints())
+
+	_, err = CreateSearchPlan(schema, `/*+ iterative_filter */ Int64Field > 1`, "FloatVectorField", &planpb.QueryInfo{Hints: "disable"}, nil)
+	assert.ErrorContains(t, err, "conflicts")
+	_, err = CreateSearchPlan(schema, `/*+ no_index(Int64Field) */ Int64Field > 1`, "FloatVectorField", &planpb.QueryInfo{}, nil)
+	assert.ErrorContains(t, err, "hint no_index is not supported by search")
+
+	_, err = CreateRetrievePlan(schema, `/*+ iterative_filter */ Int64Field > 1`, nil)
+	assert.ErrorContains(t, err, "not supported by query")
+	_, err = ParseExpr(schema, `/*+ iterative_filter */ Int64Field > 1`, nil)
+	assert.NoError(t, err)
+	_, err = ParseExpr(schema, `/*+ iterative_filter Int64Field > 1`, nil)
+	assert.Error(t, err)
+}
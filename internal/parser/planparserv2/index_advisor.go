@@ -0,0 +1,111 @@
+package planparserv2
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// IndexSuggestion is an index recommended to serve the predicates of past expressions requiring a brute-force scan.
+type IndexSuggestion struct {
+	// FieldName is the field to build the index on.
+	FieldName string
+	// JSONPath is the json_path parameter of the index, empty unless it is built on a json path.
+	JSONPath string
+	// IndexType is the recommended index type.
+	IndexType string
+	// Hits is the number of past predicates the index would serve.
+	Hits int
+	// Kinds are the sorted distinct kinds of these predicates, such as "equal" or "range".
+	Kinds []string
+	// Example is one of these predicates.
+	Example string
+}
+
+// SuggestIndexes aggregates the predicates of past expressions which can't be served by the existing indexes,
+// and recommends one index for each field (or json path) they read, hottest first. At most limit suggestions
+// are returned, all of them if limit is not positive. Expressions which no longer parse against the schema are
+// skipped, and template variables don't need to be filled.
+func SuggestIndexes(schema *typeutil.SchemaHelper, exprHistory []string, indexInfos []*indexpb.IndexInfo, limit int) ([]*IndexSuggestion, error) {
+	analyzer := newIndexCoverageAnalyzer(schema, indexInfos)
+	for _, exprStr := range exprHistory {
+		predicate := getExpr(handleExpr(schema, exprStr))
+		if predicate == nil {
+			continue
+		}
+		if err := analyzer.analyze(predicate.expr); err != nil {
+			return nil, err
+		}
+	}
+
+	var order []string
+	candidates := make(map[string][]*PredicateCoverage)
+	for _, coverage := range analyzer.coverages {
+		if coverage.Covered() || len(coverage.SuggestedIndexTypes) == 0 {
+			continue
+		}
+		path := jsonPathOf(coverage.Field)
+		if _, ok := candidates[path]; !ok {
+			order = append(order, path)
+		}
+		candidates[path] = append(candidates[path], coverage)
+	}
+
+	suggestions := make([]*IndexSuggestion, 0, len(order))
+	for _, path := range order {
+		suggestions = append(suggestions, suggestIndex(candidates[path]))
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Hits > suggestions[j].Hits
+	})
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// suggestIndex picks the index type serving most of the uncovered predicates of a field. Ties are broken by
+// the preference of the first predicate.
+func suggestIndex(coverages []*PredicateCoverage) *IndexSuggestion {
+	var indexTypes []string
+	hits := make(map[string]int)
+	for _, coverage := range coverages {
+		for _, indexType := range coverage.SuggestedIndexTypes {
+			if _, ok := hits[indexType]; !ok {
+				indexTypes = append(indexTypes, indexType)
+			}
+			hits[indexType]++
+		}
+	}
+	best := indexTypes[0]
+	for _, indexType := range indexTypes[1:] {
+		if hits[indexType] > hits[best] {
+			best = indexType
+		}
+	}
+
+	field := coverages[0].Field
+	suggestion := &IndexSuggestion{
+		FieldName: field.FieldName,
+		IndexType: best,
+		Hits:      hits[best],
+	}
+	if len(field.NestedPath) != 0 {
+		suggestion.JSONPath = jsonPathOf(field)
+	}
+	for _, coverage := range coverages {
+		if !slices.Contains(coverage.SuggestedIndexTypes, best) {
+			continue
+		}
+		if suggestion.Example == "" {
+			suggestion.Example = coverage.Predicate
+		}
+		if !slices.Contains(suggestion.Kinds, coverage.Kind) {
+			suggestion.Kinds = append(suggestion.Kinds, coverage.Kind)
+		}
+	}
+	sort.Strings(suggestion.Kinds)
+	return suggestion
+}
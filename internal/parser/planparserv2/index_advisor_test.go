@@ -0,0 +1,60 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+)
+
+func TestSuggestIndexes(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+	int64Field, err := helper.GetFieldFromName("Int64Field")
+	require.NoError(t, err)
+	indexInfos := []*indexpb.IndexInfo{
+		{
+			FieldID:     int64Field.GetFieldID(),
+			IndexName:   "idx_int",
+			IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: IndexTypeInverted}},
+		},
+	}
+
+	history := []string{
+		`Int64Field > 1 and VarCharField like "a%"`,
+		`VarCharField like "b%" or VarCharField == "c"`,
+		`VarCharField in {names}`,
+		`JSONField["a"] == 1 and BoolField == true`,
+		`Int32Field == Int64Field`,
+		`VarCharField > 1`,
+	}
+	suggestions, err := SuggestIndexes(helper, history, indexInfos, 0)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 3)
+
+	assert.Equal(t, "VarCharField", suggestions[0].FieldName)
+	assert.Equal(t, IndexTypeInverted, suggestions[0].IndexType)
+	assert.Equal(t, 4, suggestions[0].Hits)
+	assert.Equal(t, []string{predicateEqual, predicatePrefix}, suggestions[0].Kinds)
+	assert.Equal(t, `VarCharField like "a%"`, suggestions[0].Example)
+
+	assert.Equal(t, "JSONField", suggestions[1].FieldName)
+	assert.Equal(t, `JSONField["a"]`, suggestions[1].JSONPath)
+	assert.Equal(t, IndexTypeInverted, suggestions[1].IndexType)
+
+	assert.Equal(t, "BoolField", suggestions[2].FieldName)
+	assert.Empty(t, suggestions[2].JSONPath)
+	assert.Equal(t, IndexTypeBitmap, suggestions[2].IndexType)
+
+	suggestions, err = SuggestIndexes(helper, history, indexInfos, 1)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "VarCharField", suggestions[0].FieldName)
+
+	suggestions, err = SuggestIndexes(helper, nil, indexInfos, 0)
+	require.NoError(t, err)
+	assert.Empty(t, suggestions)
+}
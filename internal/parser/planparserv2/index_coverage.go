@@ -0,0 +1,316 @@
+package planparserv2
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// Index types of scalar fields, as reported in the index_type parameter of indexes.
+const (
+	IndexTypeInverted  = "INVERTED"
+	IndexTypeBitmap    = "BITMAP"
+	IndexTypeHybrid    = "HYBRID"
+	IndexTypeSTLSort   = "STL_SORT"
+	IndexTypeTrie      = "Trie"
+	IndexTypeAutoIndex = "AUTOINDEX"
+	// IndexTypeText is the text index built on the fields with enable_match.
+	IndexTypeText = "TEXT"
+	// IndexTypePrimaryKey stands for the lookup of primary keys, which needs no index.
+	IndexTypePrimaryKey = "PRIMARY_KEY"
+)
+
+// Kinds of predicates, which decide the index types able to serve them.
+const (
+	predicateEqual           = "equal"
+	predicateRange           = "range"
+	predicatePrefix          = "prefix"
+	predicateLeadingWildcard = "leading_wildcard"
+	predicateLike            = "like"
+	predicateTextMatch       = "text_match"
+	predicatePhraseMatch     = "phrase_match"
+	predicateNull            = "null"
+	predicateExists          = "exists"
+	predicateJSONContains    = "json_contains"
+	predicateArithmetic      = "arithmetic"
+	predicateArrayLength     = "array_length"
+	predicateCompare         = "column_compare"
+	predicateCall            = "call"
+)
+
+var indexTypesServing = map[string][]string{
+	predicateEqual:           {IndexTypeInverted, IndexTypeBitmap, IndexTypeHybrid, IndexTypeSTLSort, IndexTypeTrie, IndexTypeAutoIndex},
+	predicateRange:           {IndexTypeInverted, IndexTypeBitmap, IndexTypeHybrid, IndexTypeSTLSort, IndexTypeTrie, IndexTypeAutoIndex},
+	predicatePrefix:          {IndexTypeInverted, IndexTypeHybrid, IndexTypeSTLSort, IndexTypeTrie, IndexTypeAutoIndex},
+	predicateLeadingWildcard: {IndexTypeInverted, IndexTypeAutoIndex},
+	predicateLike:            {IndexTypeInverted, IndexTypeAutoIndex},
+	predicateNull:            {IndexTypeInverted, IndexTypeBitmap, IndexTypeHybrid, IndexTypeSTLSort, IndexTypeTrie, IndexTypeAutoIndex},
+	predicateExists:          {IndexTypeInverted, IndexTypeBitmap, IndexTypeHybrid, IndexTypeAutoIndex},
+	predicateJSONContains:    {IndexTypeInverted, IndexTypeBitmap, IndexTypeHybrid, IndexTypeAutoIndex},
+}
+
+// PredicateCoverage tells whether a sub-predicate of an expression can be served by an index.
+type PredicateCoverage struct {
+	// Predicate is the sub-predicate rendered as an expression.
+	Predicate string
+	// Kind is the kind of the predicate, such as "equal", "range" or "like".
+	Kind string
+	// Field is the field the predicate reads, nil if it reads several fields.
+	Field *FieldReference
+	// IndexName and IndexType are the index serving the predicate, empty if it requires a brute-force scan.
+	IndexName string
+	IndexType string
+	// Reason explains why the predicate requires a brute-force scan.
+	Reason string
+	// SuggestedIndexTypes are the index types which could serve the predicate once built on its field.
+	SuggestedIndexTypes []string
+}
+
+// Covered returns whether the predicate is served by an index.
+func (c *PredicateCoverage) Covered() bool {
+	return c.IndexType != ""
+}
+
+// AnalyzeIndexCoverage reports, for every sub-predicate of the parsed expression, whether it can be served
+// by one of the indexes of the collection or requires a brute-force scan, and which index types could serve it.
+// Predicates under a not are reported as they are, since the index serving them serves their negation as well.
+func AnalyzeIndexCoverage(schema *typeutil.SchemaHelper, expr *planpb.Expr, indexInfos []*indexpb.IndexInfo) ([]*PredicateCoverage, error) {
+	analyzer := newIndexCoverageAnalyzer(schema, indexInfos)
+	if err := analyzer.analyze(expr); err != nil {
+		return nil, err
+	}
+	return analyzer.coverages, nil
+}
+
+type indexCoverageAnalyzer struct {
+	schema    *typeutil.SchemaHelper
+	indexes   map[int64][]*indexpb.IndexInfo
+	coverages []*PredicateCoverage
+}
+
+func newIndexCoverageAnalyzer(schema *typeutil.SchemaHelper, indexInfos []*indexpb.IndexInfo) *indexCoverageAnalyzer {
+	analyzer := &indexCoverageAnalyzer{
+		schema:  schema,
+		indexes: make(map[int64][]*indexpb.IndexInfo),
+	}
+	for _, info := range indexInfos {
+		analyzer.indexes[info.GetFieldID()] = append(analyzer.indexes[info.GetFieldID()], info)
+	}
+	return analyzer
+}
+
+func (a *indexCoverageAnalyzer) analyze(expr *planpb.Expr) error {
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_AlwaysTrueExpr:
+		return nil
+	case *planpb.Expr_UnaryExpr:
+		return a.analyze(realExpr.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		if err := a.analyze(realExpr.BinaryExpr.GetLeft()); err != nil {
+			return err
+		}
+		return a.analyze(realExpr.BinaryExpr.GetRight())
+	case *planpb.Expr_RandomSampleExpr:
+		if predicate := realExpr.RandomSampleExpr.GetPredicate(); predicate != nil {
+			return a.analyze(predicate)
+		}
+		return nil
+	case *planpb.Expr_TermExpr:
+		return a.add(expr, predicateEqual, realExpr.TermExpr.GetColumnInfo())
+	case *planpb.Expr_UnaryRangeExpr:
+		e := realExpr.UnaryRangeExpr
+		switch e.GetOp() {
+		case planpb.OpType_Equal, planpb.OpType_NotEqual:
+			return a.add(expr, predicateEqual, e.GetColumnInfo())
+		case planpb.OpType_PrefixMatch:
+			return a.add(expr, predicatePrefix, e.GetColumnInfo())
+		case planpb.OpType_PostfixMatch, planpb.OpType_Match:
+			// patterns starting with a wildcard, like "%abc", can't be served by sorted indexes.
+			if e.GetOp() == planpb.OpType_PostfixMatch || strings.IndexAny(e.GetValue().GetStringVal(), "%_") == 0 {
+				return a.add(expr, predicateLeadingWildcard, e.GetColumnInfo())
+			}
+			return a.add(expr, predicateLike, e.GetColumnInfo())
+		case planpb.OpType_TextMatch:
+			return a.add(expr, predicateTextMatch, e.GetColumnInfo())
+		case planpb.OpType_PhraseMatch:
+			return a.add(expr, predicatePhraseMatch, e.GetColumnInfo())
+		default:
+			return a.add(expr, predicateRange, e.GetColumnInfo())
+		}
+	case *planpb.Expr_BinaryRangeExpr:
+		return a.add(expr, predicateRange, realExpr.BinaryRangeExpr.GetColumnInfo())
+	case *planpb.Expr_NullExpr:
+		return a.add(expr, predicateNull, realExpr.NullExpr.GetColumnInfo())
+	case *planpb.Expr_ExistsExpr:
+		return a.add(expr, predicateExists, realExpr.ExistsExpr.GetInfo())
+	case *planpb.Expr_JsonContainsExpr:
+		return a.add(expr, predicateJSONContains, realExpr.JsonContainsExpr.GetColumnInfo())
+	case *planpb.Expr_BinaryArithOpEvalRangeExpr:
+		e := realExpr.BinaryArithOpEvalRangeExpr
+		if e.GetArithOp() == planpb.ArithOpType_ArrayLength {
+			return a.add(expr, predicateArrayLength, e.GetColumnInfo())
+		}
+		return a.add(expr, predicateArithmetic, e.GetColumnInfo())
+	case *planpb.Expr_CompareExpr:
+		return a.add(expr, predicateCompare, nil)
+	case *planpb.Expr_CallExpr:
+		return a.add(expr, predicateCall, nil)
+	default:
+		return fmt.Errorf("unsupported expression type: %T", realExpr)
+	}
+}
+
+func (a *indexCoverageAnalyzer) add(expr *planpb.Expr, kind string, info *planpb.ColumnInfo) error {
+	coverage := &PredicateCoverage{Kind: kind}
+	a.coverages = append(a.coverages, coverage)
+	if info != nil {
+		field, err := fieldReference(a.schema, info)
+		if err != nil {
+			return err
+		}
+		coverage.Field = field
+	}
+	coverage.Predicate = renderPredicate(a.schema, expr, coverage)
+	if info == nil {
+		coverage.Reason = kind + " predicates read several fields and are evaluated row by row"
+		return nil
+	}
+	a.cover(coverage, info)
+	return nil
+}
+
+func (a *indexCoverageAnalyzer) cover(coverage *PredicateCoverage, info *planpb.ColumnInfo) {
+	switch coverage.Kind {
+	case predicateTextMatch, predicatePhraseMatch:
+		// text match is only accepted by the parser on the fields with enable_match, which always have a text index.
+		coverage.IndexType = IndexTypeText
+		return
+	case predicateEqual:
+		if info.GetIsPrimaryKey() && len(info.GetNestedPath()) == 0 {
+			coverage.IndexType = IndexTypePrimaryKey
+			return
+		}
+	}
+	if typeutil.IsArrayType(info.GetDataType()) && len(info.GetNestedPath()) != 0 {
+		coverage.Reason = "array elements accessed by position are not indexed"
+		return
+	}
+	serving, ok := indexTypesServing[coverage.Kind]
+	if !ok {
+		coverage.Reason = coverage.Kind + " predicates are evaluated row by row"
+		return
+	}
+	path := jsonPathOf(coverage.Field)
+	var unusable []string
+	for _, index := range a.indexes[info.GetFieldId()] {
+		params := funcutil.KeyValuePair2Map(index.GetIndexParams())
+		if len(info.GetNestedPath()) != 0 && params[common.JSONPathKey] != path {
+			continue
+		}
+		indexType := params[common.IndexTypeKey]
+		if index.GetState() == commonpb.IndexState_Failed || !slices.Contains(serving, indexType) {
+			unusable = append(unusable, fmt.Sprintf("%s (%s)", index.GetIndexName(), indexType))
+			continue
+		}
+		coverage.IndexName = index.GetIndexName()
+		coverage.IndexType = indexType
+		return
+	}
+	if len(unusable) != 0 {
+		coverage.Reason = fmt.Sprintf("indexes %v of %s cannot serve %s predicates", unusable, path, coverage.Kind)
+	} else {
+		coverage.Reason = fmt.Sprintf("%s has no index", path)
+	}
+	coverage.SuggestedIndexTypes = suggestIndexTypes(coverage.Kind, info)
+}
+
+// suggestIndexTypes returns the index types to build on the field read by an uncovered predicate, best first.
+func suggestIndexTypes(kind string, info *planpb.ColumnInfo) []string {
+	dataType := info.GetDataType()
+	switch {
+	case typeutil.IsJSONType(dataType):
+		if kind == predicateJSONContains {
+			return nil
+		}
+		return []string{IndexTypeInverted}
+	case typeutil.IsArrayType(dataType):
+		if kind == predicateJSONContains || kind == predicateNull {
+			return []string{IndexTypeInverted, IndexTypeBitmap}
+		}
+		return nil
+	}
+	switch kind {
+	case predicateEqual, predicateNull:
+		switch {
+		case dataType == schemapb.DataType_Bool:
+			return []string{IndexTypeBitmap}
+		case typeutil.IsIntegerType(dataType) || typeutil.IsStringType(dataType):
+			return []string{IndexTypeInverted, IndexTypeBitmap}
+		default:
+			return []string{IndexTypeInverted, IndexTypeSTLSort}
+		}
+	case predicateRange:
+		if typeutil.IsStringType(dataType) {
+			return []string{IndexTypeInverted, IndexTypeTrie}
+		}
+		return []string{IndexTypeSTLSort, IndexTypeInverted}
+	case predicatePrefix:
+		return []string{IndexTypeTrie, IndexTypeInverted}
+	case predicateLeadingWildcard, predicateLike:
+		return []string{IndexTypeInverted}
+	default:
+		return nil
+	}
+}
+
+// fieldReference resolves the name of the field of the column.
+func fieldReference(schema *typeutil.SchemaHelper, info *planpb.ColumnInfo) (*FieldReference, error) {
+	name, ok := systemFieldName(info.GetFieldId())
+	if !ok {
+		field, err := schema.GetFieldFromID(info.GetFieldId())
+		if err != nil {
+			return nil, err
+		}
+		name = field.GetName()
+	}
+	return &FieldReference{
+		FieldID:    info.GetFieldId(),
+		FieldName:  name,
+		DataType:   info.GetDataType(),
+		NestedPath: info.GetNestedPath(),
+	}, nil
+}
+
+// jsonPathOf renders the field reference the way the json_path parameter of indexes is written, like meta["a"]["b"].
+func jsonPathOf(field *FieldReference) string {
+	path, err := (&ASTField{Name: field.FieldName, Path: field.NestedPath}).render()
+	if err != nil {
+		return field.FieldName
+	}
+	return path
+}
+
+// renderPredicate renders the predicate as an expression, or describes it if it has no textual form,
+// such as the predicates with unfilled template variables.
+func renderPredicate(schema *typeutil.SchemaHelper, expr *planpb.Expr, coverage *PredicateCoverage) string {
+	if ast, err := ExportAST(schema, expr); err == nil {
+		if rendered, err := ASTToExprString(ast); err == nil {
+			return rendered
+		}
+	}
+	if call := expr.GetCallExpr(); call != nil {
+		return call.GetFunctionName() + "(...)"
+	}
+	if coverage.Field == nil {
+		return coverage.Kind + " predicate"
+	}
+	return fmt.Sprintf("%s predicate on %s", coverage.Kind, jsonPathOf(coverage.Field))
+}
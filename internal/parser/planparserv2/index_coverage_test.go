@@ -0,0 +1,84 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestAnalyzeIndexCoverage(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+	fieldID := func(name string) int64 {
+		field, err := helper.GetFieldFromName(name)
+		require.NoError(t, err)
+		return field.GetFieldID()
+	}
+	indexInfos := []*indexpb.IndexInfo{
+		{
+			FieldID:     fieldID("Int64Field"),
+			IndexName:   "idx_int",
+			IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: IndexTypeSTLSort}},
+		},
+		{
+			FieldID:     fieldID("VarCharField"),
+			IndexName:   "idx_str",
+			IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: IndexTypeTrie}},
+		},
+		{
+			FieldID:   fieldID("JSONField"),
+			IndexName: "idx_json",
+			IndexParams: []*commonpb.KeyValuePair{
+				{Key: common.IndexTypeKey, Value: IndexTypeInverted},
+				{Key: common.JSONPathKey, Value: `JSONField["a"]`},
+			},
+		},
+	}
+
+	expr, err := ParseExpr(helper, `Int64Field > 10 and not VarCharField like "%x" and JSONField["a"] == 1 and JSONField["b"] == 1 and Int32Field == Int64Field and FloatField in [1.0]`, nil)
+	require.NoError(t, err)
+	coverages, err := AnalyzeIndexCoverage(helper, expr, indexInfos)
+	require.NoError(t, err)
+	require.Len(t, coverages, 6)
+
+	assert.Equal(t, "Int64Field > 10", coverages[0].Predicate)
+	assert.True(t, coverages[0].Covered())
+	assert.Equal(t, "idx_int", coverages[0].IndexName)
+	assert.Equal(t, IndexTypeSTLSort, coverages[0].IndexType)
+
+	assert.Equal(t, predicateLeadingWildcard, coverages[1].Kind)
+	assert.False(t, coverages[1].Covered())
+	assert.Contains(t, coverages[1].Reason, "cannot serve leading_wildcard predicates")
+	assert.Equal(t, []string{IndexTypeInverted}, coverages[1].SuggestedIndexTypes)
+
+	assert.True(t, coverages[2].Covered())
+	assert.Equal(t, "idx_json", coverages[2].IndexName)
+
+	assert.False(t, coverages[3].Covered())
+	assert.Equal(t, `JSONField["b"] has no index`, coverages[3].Reason)
+	assert.Equal(t, []string{IndexTypeInverted}, coverages[3].SuggestedIndexTypes)
+
+	assert.Equal(t, predicateCompare, coverages[4].Kind)
+	assert.Nil(t, coverages[4].Field)
+	assert.False(t, coverages[4].Covered())
+	assert.Empty(t, coverages[4].SuggestedIndexTypes)
+
+	assert.Equal(t, "FloatField", coverages[5].Field.FieldName)
+	assert.Equal(t, []string{IndexTypeInverted, IndexTypeSTLSort}, coverages[5].SuggestedIndexTypes)
+
+	// failed indexes serve nothing.
+	indexInfos[0].State = commonpb.IndexState_Failed
+	coverages, err = AnalyzeIndexCoverage(helper, expr, indexInfos)
+	require.NoError(t, err)
+	assert.False(t, coverages[0].Covered())
+	assert.Equal(t, []string{IndexTypeSTLSort, IndexTypeInverted}, coverages[0].SuggestedIndexTypes)
+
+	coverages, err = AnalyzeIndexCoverage(helper, &planpb.Expr{Expr: &planpb.Expr_AlwaysTrueExpr{AlwaysTrueExpr: &planpb.AlwaysTrueExpr{}}}, indexInfos)
+	require.NoError(t, err)
+	assert.Empty(t, coverages)
+}
@@ -0,0 +1,85 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// maxExactJSONInteger is the largest magnitude of integers which JSON numbers stored as double hold exactly.
+const maxExactJSONInteger = 1 << 53
+
+// WithStrictJSONNumbers rejects comparing JSON values to integer literals beyond ±2^53. JSON numbers may be
+// stored as double, which can't tell such integers from their neighbors, so the comparisons are lossy.
+func WithStrictJSONNumbers(enabled bool) ParseOption {
+	return func(options *parseOptions) {
+		options.strictJSONNumbers = enabled
+	}
+}
+
+// checkJSONNumbers walks the predicates on JSON values, and rejects the integer literals which can't be
+// compared exactly.
+func checkJSONNumbers(schema *typeutil.SchemaHelper, expr *planpb.Expr) error {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryExpr:
+		return checkJSONNumbers(schema, e.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		if err := checkJSONNumbers(schema, e.BinaryExpr.GetLeft()); err != nil {
+			return err
+		}
+		return checkJSONNumbers(schema, e.BinaryExpr.GetRight())
+	case *planpb.Expr_RandomSampleExpr:
+		if e.RandomSampleExpr.GetPredicate() != nil {
+			return checkJSONNumbers(schema, e.RandomSampleExpr.GetPredicate())
+		}
+	case *planpb.Expr_UnaryRangeExpr:
+		return checkJSONValues(schema, e.UnaryRangeExpr.GetColumnInfo(), e.UnaryRangeExpr.GetValue())
+	case *planpb.Expr_BinaryRangeExpr:
+		info := e.BinaryRangeExpr.GetColumnInfo()
+		return checkJSONValues(schema, info, e.BinaryRangeExpr.GetLowerValue(), e.BinaryRangeExpr.GetUpperValue())
+	case *planpb.Expr_TermExpr:
+		return checkJSONValues(schema, e.TermExpr.GetColumnInfo(), e.TermExpr.GetValues()...)
+	case *planpb.Expr_JsonContainsExpr:
+		return checkJSONValues(schema, e.JsonContainsExpr.GetColumnInfo(), e.JsonContainsExpr.GetElements()...)
+	case *planpb.Expr_BinaryArithOpEvalRangeExpr:
+		arith := e.BinaryArithOpEvalRangeExpr
+		return checkJSONValues(schema, arith.GetColumnInfo(), arith.GetRightOperand(), arith.GetValue())
+	}
+	return nil
+}
+
+func checkJSONValues(schema *typeutil.SchemaHelper, info *planpb.ColumnInfo, values ...*planpb.GenericValue) error {
+	if info.GetDataType() != schemapb.DataType_JSON {
+		return nil
+	}
+	for _, value := range values {
+		if err := checkExactJSONInteger(schema, info, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkExactJSONInteger(schema *typeutil.SchemaHelper, info *planpb.ColumnInfo, value *planpb.GenericValue) error {
+	if array := value.GetArrayVal(); array != nil {
+		for _, element := range array.GetArray() {
+			if err := checkExactJSONInteger(schema, info, element); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !IsInteger(value) {
+		return nil
+	}
+	if v := value.GetInt64Val(); v > maxExactJSONInteger || v < -maxExactJSONInteger {
+		name := fmt.Sprint(info.GetFieldId())
+		if field, err := schema.GetFieldFromID(info.GetFieldId()); err == nil {
+			name = field.GetName()
+		}
+		return fmt.Errorf("integer %d compared with JSON field %s exceeds ±2^53, JSON numbers can't be compared exactly beyond it", v, name)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestStrictJSONNumbers(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	strict := WithStrictJSONNumbers(true)
+
+	for _, exprStr := range []string{
+		`JSONField["a"] == 9007199254740992`,
+		`JSONField["a"] > -9007199254740992`,
+		`$meta["a"] in [1, 2.5, "x"]`,
+		`JSONField["a"] > 1e300`,
+		`Int64Field == 9007199254740993`,
+		`ArrayField[0] == 9007199254740993`,
+	} {
+		_, err := ParseExpr(schema, exprStr, nil, strict)
+		assert.NoError(t, err, exprStr)
+	}
+
+	for _, exprStr := range []string{
+		`JSONField["a"] == 9007199254740993`,
+		`JSONField["a"] < -9007199254740993`,
+		`1 < JSONField["a"] < 9007199254740993`,
+		`$meta["a"] in [1, 9007199254740993]`,
+		`json_contains(JSONField["a"], 9007199254740993)`,
+		`json_contains_any(JSONField["a"], [1, 9007199254740993])`,
+		`JSONField["a"] + 9007199254740993 == 1`,
+		`Int64Field > 1 and not (JSONField["a"] != 9007199254740993)`,
+	} {
+		_, err := ParseExpr(schema, exprStr, nil)
+		assert.NoError(t, err, exprStr)
+		_, err = ParseExpr(schema, exprStr, nil, strict)
+		assert.ErrorContains(t, err, "exceeds ±2^53", exprStr)
+	}
+
+	// values of template variables are checked, too.
+	_, err := ParseExpr(schema, `JSONField["a"] == {v}`, map[string]*schemapb.TemplateValue{
+		"v": generateTemplateValue(schemapb.DataType_Int64, int64(1<<60)),
+	}, strict)
+	assert.Error(t, err)
+}
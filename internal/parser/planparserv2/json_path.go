@@ -0,0 +1,62 @@
+package planparserv2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// jsonPathKey is a key of a json path, like "a" in meta["a"][0].
+type jsonPathKey struct {
+	key    string
+	quoted bool
+}
+
+// splitJSONIdentifier splits an identifier like meta["a.b"][0] into the field name and the keys. Quoted keys follow
+// the rules of string literals, so that they can hold any character: quotes and backslashes are escaped with a
+// backslash, and characters can be written as \uXXXX or \UXXXXXXXX escapes. Dots and brackets need no escaping.
+func splitJSONIdentifier(identifier string) (string, []jsonPathKey, error) {
+	start := strings.IndexByte(identifier, '[')
+	if start <= 0 {
+		return "", nil, fmt.Errorf("invalid identifier: %s", identifier)
+	}
+	fieldName := decodeUnicode(identifier[:start])
+	var keys []jsonPathKey
+	for i := start; i < len(identifier); {
+		if identifier[i] != '[' || i+1 >= len(identifier) {
+			return "", nil, fmt.Errorf("invalid identifier: %s", identifier)
+		}
+		i++
+		var key jsonPathKey
+		if quote := identifier[i]; quote == '"' || quote == '\'' {
+			end := i + 1
+			for end < len(identifier) && identifier[end] != quote {
+				if identifier[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(identifier) {
+				return "", nil, fmt.Errorf("invalid identifier: %s", identifier)
+			}
+			unquoted, err := convertEscapeSingle(identifier[i : end+1])
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid json key %s: %w", identifier[i:end+1], err)
+			}
+			key = jsonPathKey{key: unquoted, quoted: true}
+			i = end + 1
+		} else {
+			end := strings.IndexByte(identifier[i:], ']')
+			if end < 0 {
+				return "", nil, fmt.Errorf("invalid identifier: %s", identifier)
+			}
+			key = jsonPathKey{key: identifier[i : i+end]}
+			i += end
+		}
+		if i >= len(identifier) || identifier[i] != ']' {
+			return "", nil, fmt.Errorf("invalid identifier: %s", identifier)
+		}
+		i++
+		keys = append(keys, key)
+	}
+	return fieldName, keys, nil
+}
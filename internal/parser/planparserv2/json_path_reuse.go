@@ -0,0 +1,70 @@
+package planparserv2
+
+import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// JSONPathUsage is a json path read by several predicates of an expression.
+type JSONPathUsage struct {
+	FieldID    int64
+	NestedPath []string
+	// Count is the number of predicates reading the path.
+	Count int
+}
+
+// RepeatedJSONPaths returns the json paths read more than once by the expression, in order of first appearance.
+// Segcore extracts a json path for each predicate reading it, so these are the paths worth extracting once per row.
+func RepeatedJSONPaths(expr *planpb.Expr) []*JSONPathUsage {
+	var usages []*JSONPathUsage
+	paths := make(map[string]*JSONPathUsage)
+	var walk func(message protoreflect.Message)
+	walk = func(message protoreflect.Message) {
+		if info, ok := message.Interface().(*planpb.ColumnInfo); ok {
+			if !typeutil.IsJSONType(info.GetDataType()) || len(info.GetNestedPath()) == 0 {
+				return
+			}
+			key := strconv.FormatInt(info.GetFieldId(), 10) + "\x00" + strings.Join(info.GetNestedPath(), "\x00")
+			usage, ok := paths[key]
+			if !ok {
+				usage = &JSONPathUsage{FieldID: info.GetFieldId(), NestedPath: info.GetNestedPath()}
+				paths[key] = usage
+				usages = append(usages, usage)
+			}
+			usage.Count++
+			return
+		}
+		// the fields are walked in order, since Range is unordered.
+		fields := message.Descriptor().Fields()
+		for i := 0; i < fields.Len(); i++ {
+			field := fields.Get(i)
+			if field.Message() == nil || field.IsMap() || !message.Has(field) {
+				continue
+			}
+			if field.IsList() {
+				list := message.Get(field).List()
+				for j := 0; j < list.Len(); j++ {
+					walk(list.Get(j).Message())
+				}
+				continue
+			}
+			walk(message.Get(field).Message())
+		}
+	}
+	if expr != nil {
+		walk(expr.ProtoReflect())
+	}
+
+	repeated := usages[:0]
+	for _, usage := range usages {
+		if usage.Count > 1 {
+			repeated = append(repeated, usage)
+		}
+	}
+	return repeated
+}
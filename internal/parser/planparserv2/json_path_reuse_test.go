@@ -0,0 +1,32 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepeatedJSONPaths(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+	jsonField, err := helper.GetFieldFromName("JSONField")
+	require.NoError(t, err)
+
+	expr, err := ParseExpr(helper, `(JSONField["a"] > 1 and JSONField["a"] < 10) or JSONField["a"] == 100 or `+
+		`JSONField["b"]["c"] == "x" or JSONField["d"] == 1 or json_contains(JSONField["b"]["c"], 1) or Int64Field > 1`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*JSONPathUsage{
+		{FieldID: jsonField.GetFieldID(), NestedPath: []string{"a"}, Count: 3},
+		{FieldID: jsonField.GetFieldID(), NestedPath: []string{"b", "c"}, Count: 2},
+	}, RepeatedJSONPaths(expr))
+
+	expr, err = ParseExpr(helper, `JSONField["a"] > 1 and JSONField["b"] > 1 and ArrayField[0] == 1 and ArrayField[0] == 2`, nil)
+	require.NoError(t, err)
+	assert.Empty(t, RepeatedJSONPaths(expr))
+	assert.Empty(t, RepeatedJSONPaths(nil))
+
+	report, err := ValidateExpr(helper, `JSONField["a"] > 1 or JSONField["a"] in {values}`)
+	require.NoError(t, err)
+	require.Len(t, report.RepeatedJSONPaths, 1)
+	assert.Equal(t, 2, report.RepeatedJSONPaths[0].Count)
+}
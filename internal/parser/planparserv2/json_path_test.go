@@ -0,0 +1,49 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPathSpecialKeys(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	cases := []struct {
+		expr string
+		path []string
+	}{
+		{`JSONField["we\"ird.key"] == 1`, []string{`we"ird.key`}},
+		{`JSONField['it\'s'] == 1`, []string{`it's`}},
+		{`JSONField["a][b"][0] == 1`, []string{"a][b", "0"}},
+		{`JSONField["['x']"] == 1`, []string{"['x']"}},
+		{`JSONField["back\\slash"] == 1`, []string{`back\slash`}},
+		{`JSONField["café"]["a.b"] == 1`, []string{"café", "a.b"}},
+		{`JSONField["caf\u00e9"] == 1`, []string{"café"}},
+	}
+	for _, c := range cases {
+		expr, err := ParseExpr(helper, c.expr, nil)
+		require.NoError(t, err, c.expr)
+		assert.Equal(t, c.path, expr.GetUnaryRangeExpr().GetColumnInfo().GetNestedPath(), c.expr)
+
+		// the rendered expression gives the same keys back.
+		ast, err := ExportAST(helper, expr)
+		require.NoError(t, err, c.expr)
+		rendered, err := ASTToExprString(ast)
+		require.NoError(t, err, c.expr)
+		expr, err = ParseExpr(helper, rendered, nil)
+		require.NoError(t, err, rendered)
+		assert.Equal(t, c.path, expr.GetUnaryRangeExpr().GetColumnInfo().GetNestedPath(), rendered)
+	}
+
+	invalid := []string{
+		`JSONField[""] == 1`,
+		`JSONField[a] == 1`,
+		`ArrayField["0"] == 1`,
+	}
+	for _, exprStr := range invalid {
+		_, err := ParseExpr(helper, exprStr, nil)
+		assert.Error(t, err, exprStr)
+	}
+}
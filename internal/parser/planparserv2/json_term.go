@@ -0,0 +1,155 @@
+package planparserv2
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// JSONTermCoercion is how `json_field["key"] in [...]` matches the values of the list. The json values are matched
+// by type, and segcore matches all the values of a term as the type of the first one, so the lists mixing types are
+// dispatched to a term per type, or rejected by JSONTermCoercionStrict.
+type JSONTermCoercion int
+
+const (
+	// JSONTermCoercionNumeric matches the integers and the floats of the list as numbers, and the other values as
+	// their own type.
+	JSONTermCoercionNumeric JSONTermCoercion = iota
+	// JSONTermCoercionStrict rejects the lists mixing types, integers and floats included.
+	JSONTermCoercionStrict
+	// JSONTermCoercionText also matches the numbers of the list to the json strings spelling them, and the strings
+	// spelling numbers to the json numbers, `[1, "2"]` matches `1`, `"1"`, `2` and `"2"` for example.
+	JSONTermCoercionText
+)
+
+// WithJSONTermCoercion sets how the values listed by `in` on json values are matched, JSONTermCoercionNumeric by
+// default.
+func WithJSONTermCoercion(coercion JSONTermCoercion) ParseOption {
+	return func(options *parseOptions) {
+		options.jsonTermCoercion = coercion
+	}
+}
+
+// dispatchJSONTerms rewrites the terms on json values to match their values by type, a term mixing types is
+// replaced by an OR of a term per type.
+func dispatchJSONTerms(expr *planpb.Expr, coercion JSONTermCoercion) error {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryExpr:
+		return dispatchJSONTerms(e.UnaryExpr.GetChild(), coercion)
+	case *planpb.Expr_BinaryExpr:
+		if err := dispatchJSONTerms(e.BinaryExpr.GetLeft(), coercion); err != nil {
+			return err
+		}
+		return dispatchJSONTerms(e.BinaryExpr.GetRight(), coercion)
+	case *planpb.Expr_RandomSampleExpr:
+		return dispatchJSONTerms(e.RandomSampleExpr.GetPredicate(), coercion)
+	case *planpb.Expr_TermExpr:
+		if !typeutil.IsJSONType(e.TermExpr.GetColumnInfo().GetDataType()) {
+			return nil
+		}
+		groups, err := groupJSONTermValues(e.TermExpr.GetValues(), coercion)
+		if err != nil {
+			return err
+		}
+		if len(groups) == 0 {
+			return nil
+		}
+		e.TermExpr.Values = groups[0]
+		dispatched := &planpb.Expr{Expr: e, IsTemplate: expr.GetIsTemplate()}
+		for _, group := range groups[1:] {
+			term := proto.Clone(e.TermExpr).(*planpb.TermExpr)
+			term.Values = group
+			dispatched = &planpb.Expr{
+				Expr: &planpb.Expr_BinaryExpr{
+					BinaryExpr: &planpb.BinaryExpr{
+						Left:  dispatched,
+						Right: &planpb.Expr{Expr: &planpb.Expr_TermExpr{TermExpr: term}, IsTemplate: expr.GetIsTemplate()},
+						Op:    planpb.BinaryExpr_LogicalOr,
+					},
+				},
+				IsTemplate: expr.GetIsTemplate(),
+			}
+		}
+		expr.Expr = dispatched.Expr
+	}
+	return nil
+}
+
+// groupJSONTermValues groups the values listed by `in` on json values by the type they are matched as, in the
+// order bools, integers, floats, strings and arrays. The empty groups are omitted.
+func groupJSONTermValues(values []*planpb.GenericValue, coercion JSONTermCoercion) ([][]*planpb.GenericValue, error) {
+	var bools, ints, floats, strs, arrays []*planpb.GenericValue
+	for _, value := range values {
+		switch value.GetVal().(type) {
+		case *planpb.GenericValue_BoolVal:
+			bools = append(bools, value)
+		case *planpb.GenericValue_Int64Val:
+			ints = append(ints, value)
+		case *planpb.GenericValue_FloatVal:
+			floats = append(floats, value)
+		case *planpb.GenericValue_StringVal:
+			strs = append(strs, value)
+		default:
+			arrays = append(arrays, value)
+		}
+	}
+
+	var kinds []string
+	for _, group := range []struct {
+		kind   string
+		values int
+	}{{"bool", len(bools)}, {"number", len(ints) + len(floats)}, {"string", len(strs)}, {"array", len(arrays)}} {
+		if group.values > 0 {
+			kinds = append(kinds, group.kind)
+		}
+	}
+	if len(kinds) > 1 && coercion == JSONTermCoercionStrict {
+		return nil, fmt.Errorf("'in' on json values can't mix %s values", strings.Join(kinds, " and "))
+	}
+
+	if coercion == JSONTermCoercionText {
+		listedInts, listedFloats, listedStrs := ints, floats, strs
+		for _, value := range listedStrs {
+			s := value.GetStringVal()
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				ints = append(ints, NewInt(i))
+			} else if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+				floats = append(floats, NewFloat(f))
+			}
+		}
+		for _, value := range listedInts {
+			strs = append(strs, NewString(strconv.FormatInt(value.GetInt64Val(), 10)))
+		}
+		for _, value := range listedFloats {
+			strs = append(strs, NewString(strconv.FormatFloat(value.GetFloatVal(), 'g', -1, 64)))
+		}
+	}
+
+	if len(ints) > 0 && len(floats) > 0 {
+		if coercion == JSONTermCoercionStrict {
+			return nil, fmt.Errorf("'in' on json values can't mix integer and float values")
+		}
+		for _, value := range ints {
+			i := value.GetInt64Val()
+			if i > maxExactJSONInteger || i < -maxExactJSONInteger {
+				return nil, fmt.Errorf("'in' on json values can't mix float values and the integer %d, which can't be matched exactly as a float", i)
+			}
+			floats = append(floats, NewFloat(float64(i)))
+		}
+		ints = nil
+	}
+
+	var groups [][]*planpb.GenericValue
+	for _, group := range [][]*planpb.GenericValue{bools, ints, floats, strs, arrays} {
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
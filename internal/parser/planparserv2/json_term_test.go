@@ -0,0 +1,80 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestDispatchJSONTerms(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	terms := func(expr *planpb.Expr) [][]interface{} {
+		var ret [][]interface{}
+		var walk func(expr *planpb.Expr)
+		walk = func(expr *planpb.Expr) {
+			if binary := expr.GetBinaryExpr(); binary != nil {
+				require.Equal(t, planpb.BinaryExpr_LogicalOr, binary.GetOp())
+				walk(binary.GetLeft())
+				walk(binary.GetRight())
+				return
+			}
+			values := make([]interface{}, 0)
+			for _, value := range expr.GetTermExpr().GetValues() {
+				values = append(values, extractGenericValue(value))
+			}
+			ret = append(ret, values)
+		}
+		walk(expr)
+		return ret
+	}
+
+	for _, tc := range []struct {
+		expr     string
+		coercion JSONTermCoercion
+		expected [][]interface{}
+	}{
+		{`A["ids"] in [3, 1, 2]`, JSONTermCoercionNumeric, [][]interface{}{{int64(1), int64(2), int64(3)}}},
+		{`A["ids"] in [1, 2.5]`, JSONTermCoercionNumeric, [][]interface{}{{1.0, 2.5}}},
+		{`A["ids"] in ["b", 1, true, "a"]`, JSONTermCoercionNumeric, [][]interface{}{{true}, {int64(1)}, {"a", "b"}}},
+		{`A["ids"] in [1, "2"]`, JSONTermCoercionText, [][]interface{}{{int64(1), int64(2)}, {"1", "2"}}},
+		{`A["ids"] in [1.5, "x"]`, JSONTermCoercionText, [][]interface{}{{1.5}, {"1.5", "x"}}},
+		{`A["ids"] in ["a", "b"]`, JSONTermCoercionStrict, [][]interface{}{{"a", "b"}}},
+		{`A["ids"] in []`, JSONTermCoercionStrict, [][]interface{}{{}}},
+	} {
+		expr, err := ParseExpr(schema, tc.expr, nil, WithJSONTermCoercion(tc.coercion))
+		require.NoError(t, err, tc.expr)
+		assert.Equal(t, tc.expected, terms(expr), tc.expr)
+	}
+
+	expr, err := ParseExpr(schema, `A["ids"] not in [1, "a"]`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{int64(1)}, {"a"}}, terms(expr.GetUnaryExpr().GetChild()))
+
+	expr, err = ParseExpr(schema, `A in {list}`, map[string]*schemapb.TemplateValue{
+		"list": generateTemplateValue(schemapb.DataType_Array, generateTemplateArrayValue(schemapb.DataType_JSON, [][]byte{
+			generateJSONData(int64(1)),
+			generateJSONData("abc"),
+		})),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{int64(1)}, {"abc"}}, terms(expr))
+
+	for _, exprStr := range []string{
+		`A["ids"] in [1, "a"]`,
+		`A["ids"] in [true, 1]`,
+		`A["ids"] in [1, 2.5]`,
+		`A["ids"] in [[1], 2]`,
+	} {
+		_, err := ParseExpr(schema, exprStr, nil, WithJSONTermCoercion(JSONTermCoercionStrict))
+		assert.Error(t, err, exprStr)
+	}
+	_, err = ParseExpr(schema, `A["ids"] in [9007199254740993, 2.5]`, nil)
+	assert.Error(t, err)
+	expr, err = ParseExpr(schema, `A["ids"] in [[1], 2]`, nil)
+	require.NoError(t, err)
+	assert.Len(t, terms(expr), 2)
+}
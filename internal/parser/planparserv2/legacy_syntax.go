@@ -0,0 +1,162 @@
+package planparserv2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// WithLegacySyntax accepts the v1 spellings of the expressions, which are translated to the v2 syntax before parsing,
+// with a WarnCodeDeprecatedSyntax warning per spelling, so that the stored filters keep working across the upgrade.
+func WithLegacySyntax(enabled bool) ParseOption {
+	return func(options *parseOptions) {
+		options.legacySyntax = enabled
+	}
+}
+
+// legacyKeywords are the keywords the v1 parser matched regardless of case, while v2 only accepts the lower and
+// upper case spellings.
+var legacyKeywords = map[string]struct{}{
+	"and":    {},
+	"or":     {},
+	"not":    {},
+	"in":     {},
+	"like":   {},
+	"exists": {},
+}
+
+// TranslateLegacyExpr rewrites the v1 spellings of the expression to the v2 syntax, and returns the warnings on the
+// rewritten spellings:
+//   - `=` is rewritten to `==`, and `<>` to `!=`.
+//   - the raw strings quoted by backquotes are rewritten to double quoted strings.
+//   - the keywords in mixed case, like `And` or `Not In`, are lowercased, unless they are the names of fields.
+//
+// The string literals are copied as they are, and the v2 expressions are returned unchanged.
+func TranslateLegacyExpr(schema *typeutil.SchemaHelper, exprStr string) (string, []*ExprWarning) {
+	t := &legacyTranslator{warned: make(map[string]struct{})}
+	for i := 0; i < len(exprStr); {
+		c := exprStr[i]
+		switch {
+		case c == '"' || c == '\'':
+			end := quotedStringEnd(exprStr, i)
+			t.buf.WriteString(exprStr[i:end])
+			i = end
+		case c == '`':
+			end := strings.IndexByte(exprStr[i+1:], '`')
+			if end < 0 {
+				// unterminated, left for the parser to report.
+				t.buf.WriteString(exprStr[i:])
+				return t.buf.String(), t.warnings
+			}
+			t.buf.WriteString(doubleQuote(exprStr[i+1 : i+1+end]))
+			t.warn("`", "strings quoted by backquotes are deprecated, use double quotes")
+			i += end + 2
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			i = t.translateOperator(exprStr, i)
+		case isIdentifierByte(c):
+			end := i
+			for end < len(exprStr) && isIdentifierByte(exprStr[end]) {
+				end++
+			}
+			t.buf.WriteString(t.translateWord(schema, exprStr[i:end]))
+			i = end
+		default:
+			t.buf.WriteByte(c)
+			i++
+		}
+	}
+	return t.buf.String(), t.warnings
+}
+
+type legacyTranslator struct {
+	buf      strings.Builder
+	warnings []*ExprWarning
+	warned   map[string]struct{}
+}
+
+// warn adds a warning per deprecated spelling, however many times it's used.
+func (t *legacyTranslator) warn(spelling string, format string, args ...interface{}) {
+	if _, ok := t.warned[spelling]; ok {
+		return
+	}
+	t.warned[spelling] = struct{}{}
+	t.warnings = append(t.warnings, &ExprWarning{Code: WarnCodeDeprecatedSyntax, Message: fmt.Sprintf(format, args...)})
+}
+
+// translateOperator writes the comparison operator at i, and returns the index following it.
+func (t *legacyTranslator) translateOperator(exprStr string, i int) int {
+	var next byte
+	if i+1 < len(exprStr) {
+		next = exprStr[i+1]
+	}
+	switch {
+	case exprStr[i] == '<' && next == '>':
+		t.buf.WriteString("!=")
+		t.warn("<>", "`<>` is deprecated, use `!=`")
+		return i + 2
+	case next == '=':
+		t.buf.WriteString(exprStr[i : i+2])
+		return i + 2
+	case exprStr[i] == '=':
+		t.buf.WriteString("==")
+		t.warn("=", "`=` is deprecated, use `==`")
+		return i + 1
+	}
+	t.buf.WriteByte(exprStr[i])
+	return i + 1
+}
+
+func (t *legacyTranslator) translateWord(schema *typeutil.SchemaHelper, word string) string {
+	lower := strings.ToLower(word)
+	if _, ok := legacyKeywords[lower]; !ok || word == lower || word == strings.ToUpper(word) {
+		return word
+	}
+	if schema != nil {
+		if _, err := schema.GetFieldFromName(word); err == nil {
+			return word
+		}
+	}
+	t.warn(lower, "`%s` is deprecated, use `%s` or `%s`", word, lower, strings.ToUpper(word))
+	return lower
+}
+
+// quotedStringEnd returns the index following the string literal starting at start, or the end of the expression
+// if the literal is unterminated.
+func quotedStringEnd(exprStr string, start int) int {
+	quote := exprStr[start]
+	for i := start + 1; i < len(exprStr); i++ {
+		switch exprStr[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(exprStr)
+}
+
+// doubleQuote quotes the raw string as a v2 string literal of the same value.
+func doubleQuote(raw string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; c {
+		case '\\', '"':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
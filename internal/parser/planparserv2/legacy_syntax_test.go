@@ -0,0 +1,85 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestTranslateLegacyExpr(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	translate := func(exprStr string) (string, int) {
+		translated, warnings := TranslateLegacyExpr(schema, exprStr)
+		for _, warning := range warnings {
+			assert.Equal(t, WarnCodeDeprecatedSyntax, warning.Code)
+		}
+		return translated, len(warnings)
+	}
+
+	for _, exprStr := range []string{
+		`Int64Field == 1 and VarCharField != "a=b" or not (Int8Field <= 2)`,
+		`Int64Field >= 1 AND Int64Field in [1, 2] && !(Int64Field < 0)`,
+		`VarCharField like 'a<>b%' and exists $meta["In"]`,
+		`Int64Field << 1 > 2 or Int64Field >> 1 < 2`,
+		`VarCharField == "it\"s = 'a'"`,
+	} {
+		translated, warnings := translate(exprStr)
+		assert.Equal(t, exprStr, translated)
+		assert.Zero(t, warnings, exprStr)
+	}
+
+	translated, warnings := translate(`Int64Field = 1 And Int8Field <> 2 or Int16Field = 3`)
+	assert.Equal(t, `Int64Field == 1 and Int8Field != 2 or Int16Field == 3`, translated)
+	assert.Equal(t, 3, warnings)
+
+	translated, _ = translate("VarCharField Like `a\\_%` Or VarCharField Not In [`say \"hi\"`]")
+	assert.Equal(t, `VarCharField like "a\\_%" or VarCharField not in ["say \"hi\""]`, translated)
+
+	translated, _ = translate("VarCharField == `unterminated")
+	assert.Equal(t, "VarCharField == `unterminated", translated)
+}
+
+func TestTranslateLegacyExprFieldNames(t *testing.T) {
+	schema, err := typeutil.CreateSchemaHelper(&schemapb.CollectionSchema{
+		Name: "legacy",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "id", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "In", DataType: schemapb.DataType_Int64},
+		},
+	})
+	require.NoError(t, err)
+
+	translated, warnings := TranslateLegacyExpr(schema, `In > 1 And In < 5`)
+	assert.Equal(t, `In > 1 and In < 5`, translated)
+	assert.Len(t, warnings, 1)
+}
+
+func TestWithLegacySyntax(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	_, err := ParseExpr(schema, `Int64Field = 1`, nil)
+	assert.Error(t, err)
+
+	var warnings []*ExprWarning
+	legacy, err := ParseExpr(schema, "Int64Field = 1 And VarCharField <> `a`", nil, WithLegacySyntax(true), WithWarnings(&warnings))
+	require.NoError(t, err)
+	expected, err := ParseExpr(schema, `Int64Field == 1 and VarCharField != "a"`, nil)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(expected, legacy))
+
+	codes := make([]WarningCode, 0, len(warnings))
+	for _, warning := range warnings {
+		codes = append(codes, warning.Code)
+	}
+	assert.Equal(t, []WarningCode{WarnCodeDeprecatedSyntax, WarnCodeDeprecatedSyntax, WarnCodeDeprecatedSyntax, WarnCodeDeprecatedSyntax}, codes)
+	assert.Equal(t, "deprecated_syntax: `=` is deprecated, use `==`", warnings[0].String())
+
+	_, err = ParseExpr(schema, `Int64Field == 1 and VarCharField != "a"`, nil, WithLegacySyntax(true), WithWarnings(&warnings))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
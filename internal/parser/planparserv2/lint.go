@@ -0,0 +1,156 @@
+package planparserv2
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// WarningCode is the stable code of a warning on a valid expression.
+type WarningCode string
+
+const (
+	WarnCodeLeadingWildcard WarningCode = "leading_wildcard"
+	WarnCodeFloatEquality   WarningCode = "float_equality"
+	WarnCodeOutOfRange      WarningCode = "out_of_range_literal"
+	WarnCodeAlwaysTrue      WarningCode = "always_true_clause"
+	WarnCodeEmptyTextQuery  WarningCode = "empty_text_query"
+
+	// WarnCodeDeprecatedSyntax is a v1 spelling translated by WithLegacySyntax.
+	WarnCodeDeprecatedSyntax WarningCode = "deprecated_syntax"
+)
+
+// ExprWarning is a suspicious pattern in an expression, which is valid but likely slow or not doing what is meant.
+type ExprWarning struct {
+	Code    WarningCode
+	Message string
+}
+
+func (w *ExprWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Code, w.Message)
+}
+
+// WithWarnings stores the warnings on the parsed expression into warnings, so that they can be returned to the
+// client along with the results.
+func WithWarnings(warnings *[]*ExprWarning) ParseOption {
+	return func(options *parseOptions) {
+		options.warnings = warnings
+	}
+}
+
+type exprLinter struct {
+	schema   *typeutil.SchemaHelper
+	warnings []*ExprWarning
+}
+
+// lintExpr returns the warnings on the parsed expression.
+func lintExpr(schema *typeutil.SchemaHelper, expr *planpb.Expr) []*ExprWarning {
+	linter := &exprLinter{schema: schema}
+	linter.lint(expr)
+	return linter.warnings
+}
+
+func (l *exprLinter) warn(code WarningCode, format string, args ...interface{}) {
+	l.warnings = append(l.warnings, &ExprWarning{Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *exprLinter) fieldName(info *planpb.ColumnInfo) string {
+	field, err := fieldReference(l.schema, info)
+	if err != nil {
+		return fmt.Sprintf("field %d", info.GetFieldId())
+	}
+	return jsonPathOf(field)
+}
+
+func (l *exprLinter) lint(expr *planpb.Expr) {
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryExpr:
+		l.lint(realExpr.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		for _, child := range []*planpb.Expr{realExpr.BinaryExpr.GetLeft(), realExpr.BinaryExpr.GetRight()} {
+			if IsTriviallyTrue(child) {
+				l.warn(WarnCodeAlwaysTrue, "a clause of the %s is always true", logicalOpName(realExpr.BinaryExpr.GetOp()))
+			}
+			l.lint(child)
+		}
+	case *planpb.Expr_UnaryRangeExpr:
+		info, op, value := realExpr.UnaryRangeExpr.GetColumnInfo(), realExpr.UnaryRangeExpr.GetOp(), realExpr.UnaryRangeExpr.GetValue()
+		if op == planpb.OpType_Match && strings.HasPrefix(value.GetStringVal(), "%") {
+			l.warn(WarnCodeLeadingWildcard, "like %s on %s starts with a wildcard, which can't use an index",
+				QuoteStringLiteral(value.GetStringVal()), l.fieldName(info))
+		}
+		if op == planpb.OpType_Equal || op == planpb.OpType_NotEqual {
+			l.lintFloatEquality(info, value)
+		}
+		l.lintRange(info, value)
+	case *planpb.Expr_BinaryRangeExpr:
+		l.lintRange(realExpr.BinaryRangeExpr.GetColumnInfo(), realExpr.BinaryRangeExpr.GetLowerValue())
+		l.lintRange(realExpr.BinaryRangeExpr.GetColumnInfo(), realExpr.BinaryRangeExpr.GetUpperValue())
+	case *planpb.Expr_TermExpr:
+		for _, value := range realExpr.TermExpr.GetValues() {
+			if l.lintFloatEquality(realExpr.TermExpr.GetColumnInfo(), value) {
+				break
+			}
+		}
+		for _, value := range realExpr.TermExpr.GetValues() {
+			l.lintRange(realExpr.TermExpr.GetColumnInfo(), value)
+		}
+	case *planpb.Expr_CompareExpr:
+		op := realExpr.CompareExpr.GetOp()
+		left, right := realExpr.CompareExpr.GetLeftColumnInfo(), realExpr.CompareExpr.GetRightColumnInfo()
+		if (op == planpb.OpType_Equal || op == planpb.OpType_NotEqual) &&
+			(typeutil.IsFloatingType(columnType(left)) || typeutil.IsFloatingType(columnType(right))) {
+			l.warn(WarnCodeFloatEquality, "%s and %s are compared for equality, which is unreliable for floating point numbers",
+				l.fieldName(left), l.fieldName(right))
+		}
+	}
+}
+
+// lintFloatEquality warns about a floating point number compared for equality, returns whether it warned.
+func (l *exprLinter) lintFloatEquality(info *planpb.ColumnInfo, value *planpb.GenericValue) bool {
+	if !typeutil.IsFloatingType(columnType(info)) && !(typeutil.IsJSONType(info.GetDataType()) && IsFloating(value)) {
+		return false
+	}
+	l.warn(WarnCodeFloatEquality, "%s is compared for equality, which is unreliable for floating point numbers", l.fieldName(info))
+	return true
+}
+
+// lintRange warns about an integer literal which the integer field can't hold.
+func (l *exprLinter) lintRange(info *planpb.ColumnInfo, value *planpb.GenericValue) {
+	if !IsInteger(value) {
+		return
+	}
+	var lower, upper int64
+	switch dataType := columnType(info); dataType {
+	case schemapb.DataType_Int8:
+		lower, upper = math.MinInt8, math.MaxInt8
+	case schemapb.DataType_Int16:
+		lower, upper = math.MinInt16, math.MaxInt16
+	case schemapb.DataType_Int32:
+		lower, upper = math.MinInt32, math.MaxInt32
+	default:
+		return
+	}
+	if v := value.GetInt64Val(); v < lower || v > upper {
+		l.warn(WarnCodeOutOfRange, "%d is out of the range of %s, which is a %s", v, l.fieldName(info), columnType(info).String())
+	}
+}
+
+// columnType returns the data type of the column, or of the elements if it reads an element of an array.
+func columnType(info *planpb.ColumnInfo) schemapb.DataType {
+	if info.GetDataType() == schemapb.DataType_Array && len(info.GetNestedPath()) > 0 {
+		return info.GetElementType()
+	}
+	return info.GetDataType()
+}
+
+func logicalOpName(op planpb.BinaryExpr_BinaryOp) string {
+	if op == planpb.BinaryExpr_LogicalAnd {
+		return "conjunction"
+	}
+	return "disjunction"
+}
@@ -0,0 +1,45 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithWarnings(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	lint := func(exprStr string) []WarningCode {
+		var warnings []*ExprWarning
+		_, err := ParseExpr(schema, exprStr, nil, WithWarnings(&warnings))
+		require.NoError(t, err, exprStr)
+		codes := make([]WarningCode, 0, len(warnings))
+		for _, warning := range warnings {
+			codes = append(codes, warning.Code)
+		}
+		return codes
+	}
+
+	assert.Empty(t, lint(`Int64Field > 1 and VarCharField like "abc%"`))
+	assert.Equal(t, []WarningCode{WarnCodeLeadingWildcard}, lint(`VarCharField like "%abc%"`))
+	assert.Equal(t, []WarningCode{WarnCodeLeadingWildcard}, lint(`$meta["a"] like "%abc"`))
+
+	assert.Equal(t, []WarningCode{WarnCodeFloatEquality}, lint(`FloatField == 1.5`))
+	assert.Equal(t, []WarningCode{WarnCodeFloatEquality}, lint(`DoubleField in [1.5, 2.5]`))
+	assert.Equal(t, []WarningCode{WarnCodeFloatEquality}, lint(`$meta["a"] != 1.5`))
+	assert.Equal(t, []WarningCode{WarnCodeFloatEquality}, lint(`FloatField == DoubleField`))
+	assert.Empty(t, lint(`FloatField > 1.5 and $meta["a"] == 1`))
+
+	assert.Equal(t, []WarningCode{WarnCodeOutOfRange}, lint(`Int8Field < 1000`))
+	assert.Equal(t, []WarningCode{WarnCodeOutOfRange, WarnCodeOutOfRange}, lint(`-40000 < Int16Field < 40000`))
+	assert.Equal(t, []WarningCode{WarnCodeOutOfRange}, lint(`Int32Field in [1, 3000000000]`))
+	assert.Empty(t, lint(`Int8Field < 100 and Int64Field > 3000000000`))
+
+	assert.Equal(t, []WarningCode{WarnCodeAlwaysTrue}, lint(`Int64Field > 1 or not (Int64Field in [])`))
+
+	var warnings []*ExprWarning
+	_, err := ParseExpr(schema, `Int8Field == 1000`, nil, WithWarnings(&warnings))
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "out_of_range_literal: 1000 is out of the range of Int8Field, which is a Int8", warnings[0].String())
+}
@@ -0,0 +1,128 @@
+package planparserv2
+
+import (
+	"maps"
+	"sync/atomic"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// ErrorCode is the stable code of a parse error, which doesn't change with the wording or the language of
+// its message, so that clients can handle errors programmatically.
+type ErrorCode string
+
+const (
+	ErrCodeInvalidExpr      ErrorCode = "invalid_expression"
+	ErrCodeSyntax           ErrorCode = "syntax_error"
+	ErrCodeFieldNotFound    ErrorCode = "field_not_found"
+	ErrCodeNotBoolean       ErrorCode = "not_boolean"
+	ErrCodeTemplateValue    ErrorCode = "template_value"
+	ErrCodeOperatorDisabled ErrorCode = "operator_disabled"
+	ErrCodePlanTooLarge     ErrorCode = "plan_too_large"
+)
+
+// errorCatalog holds the english summary of each error code.
+var errorCatalog = map[ErrorCode]string{
+	ErrCodeInvalidExpr:      "the expression is invalid",
+	ErrCodeSyntax:           "the expression has a syntax error",
+	ErrCodeFieldNotFound:    "the expression reads a field which doesn't exist",
+	ErrCodeNotBoolean:       "the expression doesn't evaluate to a boolean",
+	ErrCodeTemplateValue:    "a template value of the expression is missing or invalid",
+	ErrCodeOperatorDisabled: "the expression uses a disabled operator",
+	ErrCodePlanTooLarge:     "the plan built from the expression is too large",
+}
+
+// ErrorCatalog returns the english summary of each error code, to be translated by the deployments.
+func ErrorCatalog() map[ErrorCode]string {
+	return maps.Clone(errorCatalog)
+}
+
+// codedError attaches an error code to an error, without changing its message.
+type codedError struct {
+	code ErrorCode
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+func withErrorCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// ErrorCodeOf returns the code of an error returned by the parser.
+func ErrorCodeOf(err error) ErrorCode {
+	var coded *codedError
+	switch {
+	case errors.As(err, &coded):
+		return coded.code
+	case errors.Is(err, ErrOperatorDisabled):
+		return ErrCodeOperatorDisabled
+	case errors.Is(err, merr.ErrParameterTooLarge):
+		return ErrCodePlanTooLarge
+	default:
+		return ErrCodeInvalidExpr
+	}
+}
+
+// ErrorTranslator renders the message of a parse error in the language lang, like "fr" or "zh-CN", given its code,
+// the summary of the code from ErrorCatalog and the english message. It returns false if it has no translation.
+type ErrorTranslator func(lang string, code ErrorCode, summary string, message string) (string, bool)
+
+var errorTranslator atomic.Pointer[ErrorTranslator]
+
+// SetErrorTranslator sets the translator used by LocalizeError, nil removes it.
+func SetErrorTranslator(translator ErrorTranslator) {
+	if translator == nil {
+		errorTranslator.Store(nil)
+		return
+	}
+	errorTranslator.Store(&translator)
+}
+
+// localizedError is an error whose message is translated, which keeps the code and the causes of the original one.
+type localizedError struct {
+	msg string
+	err error
+}
+
+func (e *localizedError) Error() string {
+	return e.msg
+}
+
+func (e *localizedError) Unwrap() error {
+	return e.err
+}
+
+// WithLanguage renders the parse errors in the language lang, like "fr" or "zh-CN", see LocalizeError.
+func WithLanguage(lang string) ParseOption {
+	return func(options *parseOptions) {
+		options.language = lang
+	}
+}
+
+// LocalizeError renders the message of a parse error in the language lang with the translator set by
+// SetErrorTranslator. The error is returned as is if there is no translation or if it's already translated.
+func LocalizeError(err error, lang string) error {
+	translator := errorTranslator.Load()
+	var localized *localizedError
+	if err == nil || lang == "" || translator == nil || errors.As(err, &localized) {
+		return err
+	}
+	code := ErrorCodeOf(err)
+	msg, ok := (*translator)(lang, code, errorCatalog[code], err.Error())
+	if !ok {
+		return err
+	}
+	return &localizedError{msg: msg, err: err}
+}
@@ -0,0 +1,71 @@
+package planparserv2
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestErrorCodes(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	cases := []struct {
+		expr string
+		opts []ParseOption
+		code ErrorCode
+	}{
+		{`Int64Field >`, nil, ErrCodeSyntax},
+		{`Int64Field > 1 1`, nil, ErrCodeSyntax},
+		{`unknown > 1`, []ParseOption{WithImplicitDynamicField(false)}, ErrCodeFieldNotFound},
+		{`Int64Field + 1`, nil, ErrCodeNotBoolean},
+		{`Int64Field in {v}`, nil, ErrCodeTemplateValue},
+		{`VarCharField like "%a"`, []ParseOption{WithDisabledOperators("like")}, ErrCodeOperatorDisabled},
+		{`Int64Field > "a"`, nil, ErrCodeInvalidExpr},
+	}
+	for _, c := range cases {
+		_, err := ParseExpr(helper, c.expr, nil, c.opts...)
+		require.Error(t, err, c.expr)
+		assert.Equal(t, c.code, ErrorCodeOf(err), c.expr)
+		assert.Contains(t, ErrorCatalog(), c.code)
+	}
+
+	_, err := CreateRetrievePlan(helper, `Int64Field in [1, 2, 3]`, nil, WithMaxPlanSize(1))
+	require.Error(t, err)
+	assert.Equal(t, ErrCodePlanTooLarge, ErrorCodeOf(err))
+}
+
+func TestLocalizeError(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+	_, err := ParseExpr(helper, `Int64Field >`, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, err, LocalizeError(err, "fr"))
+
+	SetErrorTranslator(func(lang string, code ErrorCode, summary string, message string) (string, bool) {
+		if lang != "fr" || code != ErrCodeSyntax {
+			return "", false
+		}
+		return fmt.Sprintf("erreur de syntaxe (%s)", code), true
+	})
+	defer SetErrorTranslator(nil)
+
+	localized := LocalizeError(err, "fr")
+	assert.Equal(t, "erreur de syntaxe (syntax_error)", localized.Error())
+	assert.Equal(t, ErrCodeSyntax, ErrorCodeOf(localized))
+	assert.Equal(t, err, LocalizeError(err, "de"))
+	assert.Equal(t, err, LocalizeError(err, ""))
+	assert.Equal(t, localized, LocalizeError(localized, "fr"))
+
+	_, err = ParseExpr(helper, `Int64Field >`, nil, WithLanguage("fr"))
+	assert.EqualError(t, err, "erreur de syntaxe (syntax_error)")
+	_, err = CreateRetrievePlan(helper, `Int64Field >`, nil, WithLanguage("fr"))
+	assert.EqualError(t, err, "erreur de syntaxe (syntax_error)")
+
+	_, err = ParseExpr(helper, `Int64Field in {v}`, map[string]*schemapb.TemplateValue{})
+	require.Error(t, err)
+	assert.Equal(t, err, LocalizeError(err, "fr"))
+}
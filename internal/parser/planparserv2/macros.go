@@ -0,0 +1,163 @@
+package planparserv2
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// MacroKeyPrefix is the prefix of the collection properties defining macros. The property
+// `expr.macro.active_users` defines the macro called by `@active_users()` in expressions,
+// which is expanded to its sub-expression before parsing.
+const MacroKeyPrefix = "expr.macro."
+
+// maxMacroDepth limits the nesting of macros calling other macros.
+const maxMacroDepth = 8
+
+var macroCallPattern = regexp.MustCompile(`^@([a-zA-Z_][a-zA-Z0-9_]*)\s*\(\s*\)`)
+
+func withMacros(macros map[string]string) ParseOption {
+	return func(options *parseOptions) {
+		options.macros = macros
+	}
+}
+
+func getMacros(schema *typeutil.SchemaHelper, options *parseOptions) map[string]string {
+	if options.macros != nil {
+		return options.macros
+	}
+	return macrosOf(schema.GetSchema().GetProperties())
+}
+
+func macrosOf(properties []*commonpb.KeyValuePair) map[string]string {
+	macros := make(map[string]string)
+	for _, kv := range properties {
+		if name, ok := strings.CutPrefix(kv.GetKey(), MacroKeyPrefix); ok {
+			macros[name] = kv.GetValue()
+		}
+	}
+	return macros
+}
+
+// DefinesMacros returns whether the properties define any macro.
+func DefinesMacros(properties []*commonpb.KeyValuePair) bool {
+	return len(macrosOf(properties)) > 0
+}
+
+// expandMacros replaces the macro calls and the saved filter references of the expression by their
+// sub-expressions in parentheses. String literals are copied as is.
+func expandMacros(schema *typeutil.SchemaHelper, exprStr string, options *parseOptions) (string, error) {
+	if !strings.Contains(exprStr, "@") && !strings.Contains(exprStr, savedFilterPrefix) {
+		return exprStr, nil
+	}
+	expander := &macroExpander{
+		ctx:          options.ctx,
+		collectionID: schema.GetCollectionID(),
+		macros:       getMacros(schema, options),
+	}
+	return expander.expand(exprStr, nil)
+}
+
+type macroExpander struct {
+	ctx          context.Context
+	collectionID int64
+	macros       map[string]string
+}
+
+func (e *macroExpander) expand(exprStr string, stack []string) (string, error) {
+	var builder strings.Builder
+	builder.Grow(len(exprStr))
+	var quote byte
+	for i := 0; i < len(exprStr); i++ {
+		ch := exprStr[i]
+		var reference, body string
+		var n int
+		var err error
+		switch {
+		case quote != 0:
+			builder.WriteByte(ch)
+			if ch == '\\' && i+1 < len(exprStr) {
+				i++
+				builder.WriteByte(exprStr[i])
+			} else if ch == quote {
+				quote = 0
+			}
+			continue
+		case ch == '"' || ch == '\'':
+			quote = ch
+			builder.WriteByte(ch)
+			continue
+		case ch == '@':
+			reference, body, n, err = e.resolveMacro(exprStr[i:], i)
+		case strings.HasPrefix(exprStr[i:], savedFilterPrefix):
+			reference, body, n, err = e.resolveSavedFilter(exprStr[i:], i)
+		default:
+			builder.WriteByte(ch)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		for _, entered := range stack {
+			if entered == reference {
+				return "", fmt.Errorf("%s references itself: %s", reference, strings.Join(append(stack, reference), " -> "))
+			}
+		}
+		if len(stack) >= maxMacroDepth {
+			return "", fmt.Errorf("macros are nested deeper than %d: %s", maxMacroDepth, strings.Join(append(stack, reference), " -> "))
+		}
+		expanded, err := e.expand(body, append(stack, reference))
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString("(")
+		builder.WriteString(expanded)
+		builder.WriteString(")")
+		i += n - 1
+	}
+	return builder.String(), nil
+}
+
+// resolveMacro resolves the macro called at the start of s, and returns the reference to it, its body and
+// the length of the call.
+func (e *macroExpander) resolveMacro(s string, pos int) (string, string, int, error) {
+	match := macroCallPattern.FindStringSubmatch(s)
+	if match == nil {
+		return "", "", 0, fmt.Errorf("invalid macro call at position %d, expected @name()", pos)
+	}
+	body, ok := e.macros[match[1]]
+	if !ok {
+		return "", "", 0, fmt.Errorf("macro %s is not defined", match[1])
+	}
+	return "@" + match[1] + "()", body, len(match[0]), nil
+}
+
+// ValidateMacros checks the macros defined by the properties, which are to be set on the collection,
+// together with the macros already defined by the collection. Every macro must be expanded to a valid predicate.
+func ValidateMacros(schema *typeutil.SchemaHelper, properties []*commonpb.KeyValuePair) error {
+	defined := macrosOf(properties)
+	if len(defined) == 0 {
+		return nil
+	}
+	macros := macrosOf(schema.GetSchema().GetProperties())
+	for name, body := range defined {
+		macros[name] = body
+	}
+	for name := range defined {
+		if !hintNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid macro name %s", name)
+		}
+		report, err := ValidateExpr(schema, "@"+name+"()", withMacros(macros))
+		if err != nil {
+			return fmt.Errorf("invalid macro %s: %w", name, err)
+		}
+		if !report.Executable {
+			return fmt.Errorf("invalid macro %s: not a predicate", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func newMacroSchemaHelper(t *testing.T, macros map[string]string) *typeutil.SchemaHelper {
+	collection := newTestSchema(true)
+	for name, body := range macros {
+		collection.Properties = append(collection.Properties, &commonpb.KeyValuePair{Key: MacroKeyPrefix + name, Value: body})
+	}
+	helper, err := typeutil.CreateSchemaHelper(collection)
+	require.NoError(t, err)
+	return helper
+}
+
+func TestExpandMacros(t *testing.T) {
+	macros := map[string]string{
+		"active":       `Int64Field > 0`,
+		"active_users": `@active() and VarCharField like "user_%"`,
+		"loop_a":       `@loop_b()`,
+		"loop_b":       `@loop_a()`,
+	}
+	schema := newMacroSchemaHelper(t, macros)
+	options := newParseOptions()
+
+	for exprStr, expected := range map[string]string{
+		`Int64Field > 1`:                            `Int64Field > 1`,
+		`@active()`:                                 `(Int64Field > 0)`,
+		`@active_users( ) or Int8Field == 1`:        `((Int64Field > 0) and VarCharField like "user_%") or Int8Field == 1`,
+		`VarCharField == "@active()" and @active()`: `VarCharField == "@active()" and (Int64Field > 0)`,
+		`$meta['a\'@'] == 1`:                        `$meta['a\'@'] == 1`,
+	} {
+		expanded, err := expandMacros(schema, exprStr, options)
+		require.NoError(t, err, exprStr)
+		assert.Equal(t, expected, expanded, exprStr)
+	}
+
+	for exprStr, msg := range map[string]string{
+		`@unknown()`:     "macro unknown is not defined",
+		`@active`:        "invalid macro call",
+		`@(active)`:      "invalid macro call",
+		`@loop_a()`:      "@loop_a() references itself: @loop_a() -> @loop_b() -> @loop_a()",
+		`Int64Field > @`: "invalid macro call at position 13",
+	} {
+		_, err := expandMacros(schema, exprStr, options)
+		assert.ErrorContains(t, err, msg, exprStr)
+	}
+}
+
+func TestParseExpr_Macros(t *testing.T) {
+	schema := newMacroSchemaHelper(t, map[string]string{"active": `Int64Field > {min}`})
+
+	expr, err := ParseExpr(schema, `@active() and Int8Field == 1`, map[string]*schemapb.TemplateValue{
+		"min": generateTemplateValue(schemapb.DataType_Int64, int64(10)),
+	})
+	require.NoError(t, err)
+	left := expr.GetBinaryExpr().GetLeft().GetUnaryRangeExpr()
+	assert.Equal(t, planpb.OpType_GreaterThan, left.GetOp())
+	assert.Equal(t, int64(10), left.GetValue().GetInt64Val())
+
+	_, err = ParseExpr(newTestSchemaHelper(t), `@active()`, nil)
+	assert.ErrorContains(t, err, "macro active is not defined")
+}
+
+func TestValidateMacros(t *testing.T) {
+	schema := newMacroSchemaHelper(t, map[string]string{"active": `Int64Field > 0`})
+	property := func(name, body string) []*commonpb.KeyValuePair {
+		return []*commonpb.KeyValuePair{
+			{Key: "collection.ttl.seconds", Value: "10"},
+			{Key: MacroKeyPrefix + name, Value: body},
+		}
+	}
+
+	assert.False(t, DefinesMacros([]*commonpb.KeyValuePair{{Key: "collection.ttl.seconds", Value: "10"}}))
+	assert.True(t, DefinesMacros(property("adult", `Int8Field >= 18`)))
+	assert.NoError(t, ValidateMacros(schema, nil))
+	assert.NoError(t, ValidateMacros(schema, property("adult", `Int8Field >= 18`)))
+	assert.NoError(t, ValidateMacros(schema, property("active_adult", `@active() and Int8Field >= 18`)))
+	// the macro is not defined by the collection yet.
+	assert.Error(t, ValidateMacros(schema, property("x", `@adult()`)))
+	assert.ErrorContains(t, ValidateMacros(schema, property("bad-name", `Int8Field >= 18`)), "invalid macro name")
+	assert.ErrorContains(t, ValidateMacros(schema, property("adult", `Int8Field >=`)), "invalid macro adult")
+	assert.ErrorContains(t, ValidateMacros(schema, property("value", `Int8Field + 1`)), "not a predicate")
+	assert.ErrorContains(t, ValidateMacros(schema, property("active", `@active()`)), "references itself")
+}
@@ -0,0 +1,79 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/parameterutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// MaxLengthCheck is the action taken when a string literal compared for equality exceeds the max_length
+// of the field, such predicates can never match.
+type MaxLengthCheck int
+
+const (
+	MaxLengthCheckNone MaxLengthCheck = iota
+	MaxLengthCheckWarn
+	MaxLengthCheckError
+)
+
+// checkMaxLength walks the equality predicates on varchar fields, and warns or errors on literals
+// longer than the max_length of the field.
+func checkMaxLength(schema *typeutil.SchemaHelper, expr *planpb.Expr, check MaxLengthCheck) error {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryExpr:
+		return checkMaxLength(schema, e.UnaryExpr.GetChild(), check)
+	case *planpb.Expr_BinaryExpr:
+		if err := checkMaxLength(schema, e.BinaryExpr.GetLeft(), check); err != nil {
+			return err
+		}
+		return checkMaxLength(schema, e.BinaryExpr.GetRight(), check)
+	case *planpb.Expr_RandomSampleExpr:
+		if e.RandomSampleExpr.GetPredicate() != nil {
+			return checkMaxLength(schema, e.RandomSampleExpr.GetPredicate(), check)
+		}
+	case *planpb.Expr_UnaryRangeExpr:
+		if e.UnaryRangeExpr.GetOp() == planpb.OpType_Equal {
+			return checkStringLength(schema, e.UnaryRangeExpr.GetColumnInfo(), e.UnaryRangeExpr.GetValue(), check)
+		}
+	case *planpb.Expr_TermExpr:
+		for _, value := range e.TermExpr.GetValues() {
+			if err := checkStringLength(schema, e.TermExpr.GetColumnInfo(), value, check); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkStringLength(schema *typeutil.SchemaHelper, info *planpb.ColumnInfo, value *planpb.GenericValue, check MaxLengthCheck) error {
+	if !IsString(value) {
+		return nil
+	}
+	isVarChar := info.GetDataType() == schemapb.DataType_VarChar && len(info.GetNestedPath()) == 0
+	isVarCharElement := info.GetDataType() == schemapb.DataType_Array && info.GetElementType() == schemapb.DataType_VarChar &&
+		len(info.GetNestedPath()) != 0
+	if !isVarChar && !isVarCharElement {
+		return nil
+	}
+	field, err := schema.GetFieldFromID(info.GetFieldId())
+	if err != nil {
+		return nil
+	}
+	maxLength, err := parameterutil.GetMaxLength(field)
+	if err != nil || int64(len(value.GetStringVal())) <= maxLength {
+		return nil
+	}
+	if check == MaxLengthCheckError {
+		return fmt.Errorf("string literal of length %d exceeds the max_length %d of field %s, the predicate can never match",
+			len(value.GetStringVal()), maxLength, field.GetName())
+	}
+	log.Warn("string literal exceeds the max_length of field, the predicate can never match",
+		zap.String("field", field.GetName()), zap.Int("length", len(value.GetStringVal())), zap.Int64("maxLength", maxLength))
+	return nil
+}
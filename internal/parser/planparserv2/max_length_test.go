@@ -0,0 +1,57 @@
+package planparserv2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestParseExpr_MaxLengthCheck(t *testing.T) {
+	schema := newTestSchema(true)
+	for _, field := range schema.Fields {
+		if field.GetDataType() == schemapb.DataType_VarChar {
+			field.TypeParams = append(field.TypeParams, &commonpb.KeyValuePair{Key: common.MaxLengthKey, Value: "8"})
+		}
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+	long := strings.Repeat("a", 9)
+
+	errorCheck := WithMaxLengthCheck(MaxLengthCheckError)
+	for _, exprStr := range []string{
+		`VarCharField == "` + long + `"`,
+		`VarCharField in ["a", "` + long + `"]`,
+		`Int64Field > 1 and not (VarCharField == "` + long + `")`,
+	} {
+		_, err = ParseExpr(helper, exprStr, nil, errorCheck)
+		assert.ErrorContains(t, err, "exceeds the max_length 8 of field VarCharField", exprStr)
+	}
+
+	for _, exprStr := range []string{
+		`VarCharField == "aaaaaaaa"`,
+		`VarCharField != "` + long + `"`,
+		`VarCharField > "` + long + `"`,
+		`$meta["VarCharField"] == "` + long + `"`,
+	} {
+		_, err = ParseExpr(helper, exprStr, nil, errorCheck)
+		assert.NoError(t, err, exprStr)
+	}
+
+	exprStr := `VarCharField == {v}`
+	templateValues := map[string]*schemapb.TemplateValue{
+		"v": {Val: &schemapb.TemplateValue_StringVal{StringVal: long}},
+	}
+	_, err = ParseExpr(helper, exprStr, templateValues, errorCheck)
+	assert.Error(t, err)
+	_, err = ParseExpr(helper, exprStr, templateValues, WithMaxLengthCheck(MaxLengthCheckWarn))
+	assert.NoError(t, err)
+	_, err = ParseExpr(helper, exprStr, templateValues)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,319 @@
+package planparserv2
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+var identifierPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ConvertMongoFilter translates a MongoDB-style query document, such as
+// {"age": {"$gt": 18}, "$or": [{"city": "NYC"}, {"tags": {"$in": ["a", "b"]}}]},
+// into a plan expression. The document is lowered into the native expression
+// syntax first, so the result goes through exactly the same semantic checks as ParseExpr.
+func ConvertMongoFilter(schema *typeutil.SchemaHelper, filter []byte) (*planpb.Expr, error) {
+	exprStr, err := MongoFilterToExprString(filter)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExpr(schema, exprStr, nil)
+}
+
+// MongoFilterToExprString translates a MongoDB-style query document into the native expression string.
+func MongoFilterToExprString(filter []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewBuffer(filter))
+	decoder.UseNumber()
+	var doc map[string]interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return "", fmt.Errorf("invalid mongo filter document: %s", err)
+	}
+	if len(doc) == 0 {
+		// empty document matches everything.
+		return "", nil
+	}
+	return convertMongoDocument(doc)
+}
+
+func sortedKeys(doc map[string]interface{}) []string {
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinExprs combines the sub expressions with the logical operator, an empty list is treated as always true.
+func joinExprs(exprs []string, op string) string {
+	switch len(exprs) {
+	case 0:
+		return "true"
+	case 1:
+		return exprs[0]
+	default:
+		return "(" + strings.Join(exprs, " "+op+" ") + ")"
+	}
+}
+
+func negateExpr(expr string) string {
+	return "not (" + expr + ")"
+}
+
+func convertMongoDocument(doc map[string]interface{}) (string, error) {
+	exprs := make([]string, 0, len(doc))
+	for _, key := range sortedKeys(doc) {
+		value := doc[key]
+		var expr string
+		var err error
+		switch key {
+		case "$and", "$or", "$nor":
+			expr, err = convertMongoLogical(key, value)
+		default:
+			if strings.HasPrefix(key, "$") {
+				return "", fmt.Errorf("unsupported mongo top-level operator: %s", key)
+			}
+			expr, err = convertMongoField(key, value)
+		}
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	return joinExprs(exprs, "and"), nil
+}
+
+func convertMongoLogical(op string, value interface{}) (string, error) {
+	clauses, ok := value.([]interface{})
+	if !ok || len(clauses) == 0 {
+		return "", fmt.Errorf("mongo operator %s requires a non-empty array", op)
+	}
+	exprs := make([]string, 0, len(clauses))
+	for _, clause := range clauses {
+		doc, ok := clause.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("mongo operator %s requires an array of documents", op)
+		}
+		expr, err := convertMongoDocument(doc)
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	switch op {
+	case "$and":
+		return joinExprs(exprs, "and"), nil
+	case "$or":
+		return joinExprs(exprs, "or"), nil
+	default:
+		return negateExpr(joinExprs(exprs, "or")), nil
+	}
+}
+
+// dottedPathToIdentifier converts dotted document path `a.b.0` to `a["b"][0]`.
+func dottedPathToIdentifier(path string) (string, error) {
+	segments := strings.Split(path, ".")
+	if !identifierPattern.MatchString(segments[0]) {
+		return "", fmt.Errorf("invalid field name: %s", path)
+	}
+	var b strings.Builder
+	b.WriteString(segments[0])
+	for _, segment := range segments[1:] {
+		if segment == "" {
+			return "", fmt.Errorf("invalid field path: %s", path)
+		}
+		// only canonical integers are array indexes, `007` is kept as a key.
+		if index, err := strconv.ParseUint(segment, 10, 64); err == nil && strconv.FormatUint(index, 10) == segment {
+			b.WriteString("[" + segment + "]")
+		} else {
+			b.WriteString("[" + strconv.Quote(segment) + "]")
+		}
+	}
+	return b.String(), nil
+}
+
+func convertMongoField(path string, value interface{}) (string, error) {
+	identifier, err := dottedPathToIdentifier(path)
+	if err != nil {
+		return "", err
+	}
+	operators, ok := value.(map[string]interface{})
+	if !ok || !isMongoOperatorDocument(operators) {
+		// {field: value} is the shorthand of {field: {$eq: value}}
+		return convertMongoOperator(identifier, "$eq", value)
+	}
+	exprs := make([]string, 0, len(operators))
+	for _, op := range sortedKeys(operators) {
+		expr, err := convertMongoOperator(identifier, op, operators[op])
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	return joinExprs(exprs, "and"), nil
+}
+
+func isMongoOperatorDocument(doc map[string]interface{}) bool {
+	if len(doc) == 0 {
+		return false
+	}
+	for key := range doc {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+var mongoCompareOps = map[string]string{
+	"$eq":  "==",
+	"$ne":  "!=",
+	"$gt":  ">",
+	"$gte": ">=",
+	"$lt":  "<",
+	"$lte": "<=",
+}
+
+func convertMongoOperator(identifier, op string, value interface{}) (string, error) {
+	if cmpOp, ok := mongoCompareOps[op]; ok {
+		if value == nil {
+			switch op {
+			case "$eq":
+				return mongoNullExpr(identifier, true), nil
+			case "$ne":
+				return mongoNullExpr(identifier, false), nil
+			default:
+				return "", fmt.Errorf("mongo operator %s cannot be applied to null", op)
+			}
+		}
+		literal, err := formatJSONLiteral(value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s %s", identifier, cmpOp, literal), nil
+	}
+
+	switch op {
+	case "$in", "$nin":
+		values, ok := value.([]interface{})
+		if !ok {
+			return "", fmt.Errorf("mongo operator %s requires an array", op)
+		}
+		// null in the list matches the missing values, which is not a term of the in expression.
+		hasNull := false
+		nonNulls := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			if v == nil {
+				hasNull = true
+				continue
+			}
+			nonNulls = append(nonNulls, v)
+		}
+		var exprs []string
+		if hasNull {
+			exprs = append(exprs, mongoNullExpr(identifier, op == "$in"))
+		}
+		if len(nonNulls) > 0 || !hasNull {
+			literal, err := formatJSONLiteral(nonNulls)
+			if err != nil {
+				return "", err
+			}
+			if op == "$in" {
+				exprs = append(exprs, fmt.Sprintf("%s in %s", identifier, literal))
+			} else {
+				exprs = append(exprs, fmt.Sprintf("%s not in %s", identifier, literal))
+			}
+		}
+		if op == "$in" {
+			return joinExprs(exprs, "or"), nil
+		}
+		return joinExprs(exprs, "and"), nil
+	case "$all":
+		if _, ok := value.([]interface{}); !ok {
+			return "", fmt.Errorf("mongo operator %s requires an array", op)
+		}
+		literal, err := formatJSONLiteral(value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("json_contains_all(%s, %s)", identifier, literal), nil
+	case "$size":
+		literal, err := formatJSONLiteral(value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("array_length(%s) == %s", identifier, literal), nil
+	case "$exists":
+		exists, ok := value.(bool)
+		if !ok {
+			return "", fmt.Errorf("mongo operator $exists requires a boolean")
+		}
+		if exists {
+			return "exists " + identifier, nil
+		}
+		return negateExpr("exists " + identifier), nil
+	case "$not":
+		operators, ok := value.(map[string]interface{})
+		if !ok || !isMongoOperatorDocument(operators) {
+			return "", fmt.Errorf("mongo operator $not requires an operator document")
+		}
+		exprs := make([]string, 0, len(operators))
+		for _, subOp := range sortedKeys(operators) {
+			expr, err := convertMongoOperator(identifier, subOp, operators[subOp])
+			if err != nil {
+				return "", err
+			}
+			exprs = append(exprs, expr)
+		}
+		return negateExpr(joinExprs(exprs, "and")), nil
+	default:
+		return "", fmt.Errorf("unsupported mongo operator: %s", op)
+	}
+}
+
+// mongoNullExpr matches the null or missing values, json paths have no `is null` so `exists` is used instead.
+func mongoNullExpr(identifier string, isNull bool) string {
+	if strings.Contains(identifier, "[") {
+		if isNull {
+			return negateExpr("exists " + identifier)
+		}
+		return "exists " + identifier
+	}
+	if isNull {
+		return identifier + " is null"
+	}
+	return identifier + " is not null"
+}
+
+// formatJSONLiteral renders a decoded json value as an expression literal.
+func formatJSONLiteral(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String(), nil
+	case string:
+		return strconv.Quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case []interface{}:
+		elements := make([]string, 0, len(v))
+		for _, e := range v {
+			literal, err := formatJSONLiteral(e)
+			if err != nil {
+				return "", err
+			}
+			elements = append(elements, literal)
+		}
+		return "[" + strings.Join(elements, ", ") + "]", nil
+	default:
+		return "", fmt.Errorf("unsupported literal value: %v", value)
+	}
+}
@@ -0,0 +1,77 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMongoFilterToExprString(t *testing.T) {
+	cases := []struct {
+		filter string
+		expr   string
+	}{
+		{`{}`, ``},
+		{`{"Int64Field": 1}`, `Int64Field == 1`},
+		{`{"Int64Field": {"$gt": 1, "$lte": 10}}`, `(Int64Field > 1 and Int64Field <= 10)`},
+		{`{"VarCharField": {"$in": ["a", "b"]}}`, `VarCharField in ["a", "b"]`},
+		{`{"VarCharField": {"$nin": ["a"]}}`, `VarCharField not in ["a"]`},
+		{`{"VarCharField": {"$in": ["a", null]}}`, `(VarCharField is null or VarCharField in ["a"])`},
+		{`{"VarCharField": {"$in": [null]}}`, `VarCharField is null`},
+		{`{"VarCharField": {"$nin": [null, "a"]}}`, `(VarCharField is not null and VarCharField not in ["a"])`},
+		{`{"VarCharField": {"$in": []}}`, `VarCharField in []`},
+		{`{"JSONField.a.0": {"$ne": "x"}}`, `JSONField["a"][0] != "x"`},
+		{`{"JSONField.a.007": 1}`, `JSONField["a"]["007"] == 1`},
+		{`{"JSONField.a": {"$nin": [null]}}`, `exists JSONField["a"]`},
+		{`{"JSONField.a": null}`, `not (exists JSONField["a"])`},
+		{`{"JSONField.a": {"$exists": false}}`, `not (exists JSONField["a"])`},
+		{`{"Int64Field": null}`, `Int64Field is null`},
+		{`{"$or": [{"Int64Field": 1}, {"DoubleField": {"$lt": 2.5}}]}`, `(Int64Field == 1 or DoubleField < 2.5)`},
+		{`{"$nor": [{"Int64Field": 1}]}`, `not (Int64Field == 1)`},
+		{`{"Int64Field": {"$not": {"$gt": 5}}}`, `not (Int64Field > 5)`},
+		{`{"ArrayField": {"$all": [1, 2]}}`, `json_contains_all(ArrayField, [1, 2])`},
+		{`{"ArrayField": {"$size": 3}}`, `array_length(ArrayField) == 3`},
+	}
+	for _, c := range cases {
+		expr, err := MongoFilterToExprString([]byte(c.filter))
+		assert.NoError(t, err, c.filter)
+		assert.Equal(t, c.expr, expr, c.filter)
+	}
+
+	invalidFilters := []string{
+		`[]`,
+		`{"$where": "x"}`,
+		`{"Int64Field": {"$regex": "a.*"}}`,
+		`{"Int64Field": {"$in": 1}}`,
+		`{"$or": []}`,
+		`{"$and": [1]}`,
+		`{"a b": 1}`,
+		`{"a..b": 1}`,
+		`{"Int64Field": {"$gt": null}}`,
+		`{"JSONField.a": {"$exists": 1}}`,
+		`{"Int64Field": {"$eq": {"a": 1}}}`,
+	}
+	for _, filter := range invalidFilters {
+		_, err := MongoFilterToExprString([]byte(filter))
+		assert.Error(t, err, filter)
+	}
+}
+
+func TestConvertMongoFilter(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	expr, err := ConvertMongoFilter(helper, []byte(`{"Int64Field": {"$gte": 1}, "VarCharField": "abc"}`))
+	assert.NoError(t, err)
+	assert.NotNil(t, expr.GetBinaryExpr())
+
+	expr, err = ConvertMongoFilter(helper, []byte(`{}`))
+	assert.NoError(t, err)
+	assert.NotNil(t, expr.GetAlwaysTrueExpr())
+
+	expr, err = ConvertMongoFilter(helper, []byte(`{"JSONField.007": {"$in": [1, null]}}`))
+	assert.NoError(t, err)
+	assert.NotNil(t, expr.GetBinaryExpr())
+
+	_, err = ConvertMongoFilter(helper, []byte(`{"BoolField": {"$gt": "abc"}}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package planparserv2
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// guardNegatedJSONContains gives `not json_contains(json["path"], ...)` the three-valued semantics of a missing key:
+// like json_contains itself, its negation is false on the rows missing the path, so it's rewritten to
+// `exists json["path"] and not json_contains(json["path"], ...)`. `not exists json["path"]` needs no rewrite, a
+// missing key is known not to exist.
+//
+// Only the negations under no other negation are rewritten, since the unknown result only collapses to false at the
+// top of the expression, and `not not x` is reduced to `x` first. An index on the path serves both the exists and the
+// json_contains, so the rewrite doesn't force a scan of the rows.
+func guardNegatedJSONContains(expr *planpb.Expr) *planpb.Expr {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_BinaryExpr:
+		e.BinaryExpr.Left = guardNegatedJSONContains(e.BinaryExpr.GetLeft())
+		e.BinaryExpr.Right = guardNegatedJSONContains(e.BinaryExpr.GetRight())
+	case *planpb.Expr_RandomSampleExpr:
+		if predicate := e.RandomSampleExpr.GetPredicate(); predicate != nil {
+			e.RandomSampleExpr.Predicate = guardNegatedJSONContains(predicate)
+		}
+	case *planpb.Expr_UnaryExpr:
+		if e.UnaryExpr.GetOp() != planpb.UnaryExpr_Not {
+			return expr
+		}
+		child := e.UnaryExpr.GetChild()
+		if negated := child.GetUnaryExpr(); negated != nil && negated.GetOp() == planpb.UnaryExpr_Not {
+			return guardNegatedJSONContains(negated.GetChild())
+		}
+		info := child.GetJsonContainsExpr().GetColumnInfo()
+		if info == nil || !typeutil.IsJSONType(info.GetDataType()) || len(info.GetNestedPath()) == 0 {
+			return expr
+		}
+		return &planpb.Expr{
+			Expr: &planpb.Expr_BinaryExpr{
+				BinaryExpr: &planpb.BinaryExpr{
+					Left: &planpb.Expr{
+						Expr: &planpb.Expr_ExistsExpr{
+							ExistsExpr: &planpb.ExistsExpr{
+								Info: proto.Clone(info).(*planpb.ColumnInfo),
+							},
+						},
+					},
+					Right: expr,
+					Op:    planpb.BinaryExpr_LogicalAnd,
+				},
+			},
+			IsTemplate: expr.GetIsTemplate(),
+		}
+	}
+	return expr
+}
@@ -0,0 +1,89 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+)
+
+func TestGuardNegatedJSONContains(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	expr, err := ParseExpr(helper, `not json_contains(JSONField["tags"], "x")`, nil)
+	require.NoError(t, err)
+	binary := expr.GetBinaryExpr()
+	require.NotNil(t, binary)
+	assert.Equal(t, []string{"tags"}, binary.GetLeft().GetExistsExpr().GetInfo().GetNestedPath())
+	assert.NotNil(t, binary.GetRight().GetUnaryExpr().GetChild().GetJsonContainsExpr())
+
+	expr, err = ParseExpr(helper, `not not json_contains(JSONField["tags"], "x")`, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetJsonContainsExpr())
+
+	for _, exprStr := range []string{
+		`not json_contains(JSONField, "x")`,
+		`not json_contains(ArrayField, 1)`,
+		`not (Int64Field > 1 and not json_contains(JSONField["tags"], "x"))`,
+		`not exists JSONField["a"]["b"]`,
+	} {
+		expr, err := ParseExpr(helper, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		assert.NotNil(t, expr.GetUnaryExpr(), exprStr)
+	}
+}
+
+func TestNegatedJSONSemantics(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+	jsonField, err := helper.GetFieldFromName("JSONField")
+	require.NoError(t, err)
+	eval := func(exprStr string, json interface{}) bool {
+		expr, err := ParseExpr(helper, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		result, err := EvalExpr(expr, map[int64]interface{}{jsonField.GetFieldID(): json})
+		require.NoError(t, err, exprStr)
+		return result
+	}
+
+	row := []byte(`{"tags": ["red"], "a": {"b": 1}, "n": 1}`)
+	assert.True(t, eval(`not json_contains(JSONField["tags"], "x")`, row))
+	assert.False(t, eval(`not json_contains(JSONField["tags"], "red")`, row))
+	// a missing key is neither contained nor not contained.
+	assert.False(t, eval(`not json_contains(JSONField["missing"], "x")`, row))
+	assert.False(t, eval(`json_contains(JSONField["missing"], "x")`, row))
+	assert.False(t, eval(`not json_contains(JSONField["tags"], "x")`, nil))
+
+	assert.False(t, eval(`not exists JSONField["a"]["b"]`, row))
+	assert.True(t, eval(`not exists JSONField["a"]["c"]`, row))
+	assert.True(t, eval(`not exists JSONField["missing"]["c"]`, row))
+	assert.True(t, eval(`not exists JSONField["n"]["c"]`, row))
+	assert.False(t, eval(`not exists JSONField["a"]["b"]`, nil))
+}
+
+func TestNegatedJSONContainsIndexCoverage(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+	jsonField, err := helper.GetFieldFromName("JSONField")
+	require.NoError(t, err)
+	indexInfos := []*indexpb.IndexInfo{{
+		FieldID:   jsonField.GetFieldID(),
+		IndexName: "idx_tags",
+		IndexParams: []*commonpb.KeyValuePair{
+			{Key: common.IndexTypeKey, Value: IndexTypeInverted},
+			{Key: common.JSONPathKey, Value: `JSONField["tags"]`},
+		},
+	}}
+
+	expr, err := ParseExpr(helper, `not json_contains(JSONField["tags"], "x")`, nil)
+	require.NoError(t, err)
+	coverages, err := AnalyzeIndexCoverage(helper, expr, indexInfos)
+	require.NoError(t, err)
+	require.Len(t, coverages, 2)
+	for _, coverage := range coverages {
+		assert.True(t, coverage.Covered(), coverage.Predicate)
+		assert.Equal(t, "idx_tags", coverage.IndexName)
+	}
+}
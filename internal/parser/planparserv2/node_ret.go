@@ -12,6 +12,8 @@ type ExprWithType struct {
 	// For example, a column expression or a value expression itself cannot be an expression node independently.
 	// Unless our execution backend can support them.
 	nodeDependent bool
+	// timeValue is set on the values in milliseconds built from now() and interval(), see translateTimeFunction.
+	timeValue bool
 }
 
 func getError(obj interface{}) error {
@@ -0,0 +1,33 @@
+package planparserv2
+
+import (
+	"strings"
+
+	parser "github.com/milvus-io/milvus/internal/parser/planparserv2/generated"
+)
+
+// isNullLiteral returns whether the element of a list is null. The grammar has no null constant, so null is only
+// recognized in the lists of 'in', which can't hold fields.
+func isNullLiteral(ctx parser.IExprContext) bool {
+	identifier, ok := ctx.(*parser.IdentifierContext)
+	return ok && strings.EqualFold(identifier.GetText(), "null")
+}
+
+// visitTermList translates the list of an 'in' without its null elements, which never equal a value like in SQL,
+// so that `a in [1, null]` is `a in [1]` and `a in [null]` matches nothing. It also returns whether there were any.
+func (v *ParserVisitor) visitTermList(ctx parser.IExprContext) (interface{}, bool) {
+	if isValueSetRef(ctx) {
+		return v.translateValueSetRef(ctx.(*parser.CallContext)), false
+	}
+	array, ok := ctx.(*parser.ArrayContext)
+	if !ok {
+		return ctx.Accept(v), false
+	}
+	elements := make([]parser.IExprContext, 0, len(array.AllExpr()))
+	for _, element := range array.AllExpr() {
+		if !isNullLiteral(element) {
+			elements = append(elements, element)
+		}
+	}
+	return v.translateArray(elements), len(elements) != len(array.AllExpr())
+}
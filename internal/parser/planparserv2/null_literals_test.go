@@ -0,0 +1,66 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestEmptyAndNullLists(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	expr, err := ParseExpr(helper, `Int64Field in []`, nil)
+	require.NoError(t, err)
+	assert.True(t, IsTriviallyFalse(expr))
+	expr, err = ParseExpr(helper, `Int64Field not in []`, nil)
+	require.NoError(t, err)
+	assert.True(t, IsTriviallyTrue(expr))
+
+	// a template filled with an empty list is as false as a literal one.
+	expr, err = ParseExpr(helper, `Int8Field > 1 and Int64Field in {v}`, map[string]*schemapb.TemplateValue{
+		"v": generateTemplateValue(schemapb.DataType_Array, generateTemplateArrayValue(schemapb.DataType_Int64, []int64{})),
+	})
+	require.NoError(t, err)
+	assert.True(t, IsTriviallyFalse(expr))
+	// but an unfilled one is not known yet.
+	report, err := ValidateExpr(helper, `Int64Field in {v}`)
+	require.NoError(t, err)
+	assert.Len(t, report.TemplateSlots, 1)
+
+	// null never equals a value.
+	expr, err = ParseExpr(helper, `Int64Field in [1, null]`, nil)
+	require.NoError(t, err)
+	require.Len(t, expr.GetTermExpr().GetValues(), 1)
+	assert.Equal(t, int64(1), expr.GetTermExpr().GetValues()[0].GetInt64Val())
+	expr, err = ParseExpr(helper, `Int64Field in [NULL]`, nil)
+	require.NoError(t, err)
+	assert.True(t, IsTriviallyFalse(expr))
+	expr, err = ParseExpr(helper, `JSONField["a"] in [null, "x"]`, nil)
+	require.NoError(t, err)
+	require.Len(t, expr.GetTermExpr().GetValues(), 1)
+	assert.Equal(t, "x", expr.GetTermExpr().GetValues()[0].GetStringVal())
+	_, err = ParseExpr(helper, `Int64Field not in [1, null]`, nil)
+	assert.ErrorContains(t, err, "is not null")
+
+	// the empty string is a value, which missing json keys don't hold.
+	expr, err = ParseExpr(helper, `JSONField["a"] == ""`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "", expr.GetUnaryRangeExpr().GetValue().GetStringVal())
+	assert.NotNil(t, expr.GetUnaryRangeExpr().GetValue().GetVal())
+	expr, err = ParseExpr(helper, `JSONField["a"] in [""]`, nil)
+	require.NoError(t, err)
+	assert.Len(t, expr.GetTermExpr().GetValues(), 1)
+
+	// parsing a cached syntax tree gives the same plans.
+	for _, exprStr := range []string{`Int64Field in []`, `Int64Field in [1, null]`, `JSONField["a"] in [""]`} {
+		uncached, err := ParseExpr(helper, exprStr, nil, WithCacheRefresh())
+		require.NoError(t, err)
+		cached, err := ParseExpr(helper, exprStr, nil)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(uncached, cached), exprStr)
+	}
+}
@@ -0,0 +1,225 @@
+package planparserv2
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// The type params declaring the range of the values of a numeric field, like {"min": "0", "max": "150"}.
+const (
+	RangeMinKey = "min"
+	RangeMaxKey = "max"
+)
+
+// RangeCheck is the action taken when a literal compared with a numeric field is outside of the range the field
+// declares in its type params.
+type RangeCheck int
+
+const (
+	RangeCheckNone RangeCheck = iota
+	// RangeCheckClamp rewrites the comparisons with the literals outside of the range into equivalent ones within
+	// the range, such as `age > 500` into always false if age is at most 150.
+	RangeCheckClamp
+	RangeCheckError
+)
+
+// WithRangeCheck sets the action taken on the literals outside of the declared range of numeric fields. Unless
+// it's RangeCheckNone, the ranges are also trusted to detect the predicates which can't match.
+func WithRangeCheck(check RangeCheck) ParseOption {
+	return func(options *parseOptions) {
+		options.rangeCheck = check
+	}
+}
+
+// fieldRange is the declared range of a numeric field, the bounds are inclusive and nil if not declared.
+type fieldRange struct {
+	min, max *planpb.GenericValue
+}
+
+func (r *fieldRange) String() string {
+	bound := func(value *planpb.GenericValue) string {
+		if value == nil {
+			return "unbounded"
+		}
+		return fmt.Sprint(extractGenericValue(value))
+	}
+	return fmt.Sprintf("[%s, %s]", bound(r.min), bound(r.max))
+}
+
+func (r *fieldRange) belowMin(value *planpb.GenericValue) bool {
+	return r.min != nil && valueIs(Less(value, r.min))
+}
+
+func (r *fieldRange) aboveMax(value *planpb.GenericValue) bool {
+	return r.max != nil && valueIs(Greater(value, r.max))
+}
+
+func parseRangeBound(field *schemapb.FieldSchema, key string) (*planpb.GenericValue, error) {
+	for _, param := range field.GetTypeParams() {
+		if param.GetKey() != key {
+			continue
+		}
+		if typeutil.IsIntegerType(field.GetDataType()) {
+			v, err := strconv.ParseInt(param.GetValue(), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %s of field %s, it must be an integer", key, param.GetValue(), field.GetName())
+			}
+			return NewInt(v), nil
+		}
+		v, err := strconv.ParseFloat(param.GetValue(), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %s of field %s, it must be a number", key, param.GetValue(), field.GetName())
+		}
+		return NewFloat(v), nil
+	}
+	return nil, nil
+}
+
+// fieldRanges returns the declared ranges of the numeric fields by field id.
+func fieldRanges(schema *typeutil.SchemaHelper) (map[int64]*fieldRange, error) {
+	ranges := make(map[int64]*fieldRange)
+	for _, field := range schema.GetSchema().GetFields() {
+		if !typeutil.IsIntegerType(field.GetDataType()) && !typeutil.IsFloatingType(field.GetDataType()) {
+			continue
+		}
+		lower, err := parseRangeBound(field, RangeMinKey)
+		if err != nil {
+			return nil, err
+		}
+		upper, err := parseRangeBound(field, RangeMaxKey)
+		if err != nil {
+			return nil, err
+		}
+		if lower == nil && upper == nil {
+			continue
+		}
+		if lower != nil && upper != nil && valueIs(Greater(lower, upper)) {
+			return nil, fmt.Errorf("invalid range of field %s, min is greater than max", field.GetName())
+		}
+		ranges[field.GetFieldID()] = &fieldRange{min: lower, max: upper}
+	}
+	return ranges, nil
+}
+
+// applyFieldRanges clamps or rejects the literals outside of the declared ranges of the fields they're
+// compared with.
+func applyFieldRanges(schema *typeutil.SchemaHelper, expr *planpb.Expr, ranges map[int64]*fieldRange, check RangeCheck) (*planpb.Expr, error) {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryExpr:
+		child, err := applyFieldRanges(schema, e.UnaryExpr.GetChild(), ranges, check)
+		if err != nil {
+			return nil, err
+		}
+		e.UnaryExpr.Child = child
+	case *planpb.Expr_BinaryExpr:
+		left, err := applyFieldRanges(schema, e.BinaryExpr.GetLeft(), ranges, check)
+		if err != nil {
+			return nil, err
+		}
+		right, err := applyFieldRanges(schema, e.BinaryExpr.GetRight(), ranges, check)
+		if err != nil {
+			return nil, err
+		}
+		e.BinaryExpr.Left, e.BinaryExpr.Right = left, right
+	case *planpb.Expr_RandomSampleExpr:
+		if e.RandomSampleExpr.GetPredicate() != nil {
+			predicate, err := applyFieldRanges(schema, e.RandomSampleExpr.GetPredicate(), ranges, check)
+			if err != nil {
+				return nil, err
+			}
+			e.RandomSampleExpr.Predicate = predicate
+		}
+	case *planpb.Expr_UnaryRangeExpr:
+		r, ok := rangeOf(ranges, e.UnaryRangeExpr.GetColumnInfo())
+		if !ok {
+			return expr, nil
+		}
+		value := e.UnaryRangeExpr.GetValue()
+		if !r.belowMin(value) && !r.aboveMax(value) {
+			return expr, nil
+		}
+		if check == RangeCheckError {
+			return nil, errOutOfRange(schema, e.UnaryRangeExpr.GetColumnInfo(), value, r)
+		}
+		return clampUnaryRange(expr, e.UnaryRangeExpr, r), nil
+	case *planpb.Expr_BinaryRangeExpr:
+		r, ok := rangeOf(ranges, e.BinaryRangeExpr.GetColumnInfo())
+		if !ok {
+			return expr, nil
+		}
+		lower, upper := e.BinaryRangeExpr.GetLowerValue(), e.BinaryRangeExpr.GetUpperValue()
+		for _, value := range []*planpb.GenericValue{lower, upper} {
+			if check == RangeCheckError && (r.belowMin(value) || r.aboveMax(value)) {
+				return nil, errOutOfRange(schema, e.BinaryRangeExpr.GetColumnInfo(), value, r)
+			}
+		}
+		if r.aboveMax(lower) || r.belowMin(upper) {
+			return alwaysFalseExpr(), nil
+		}
+		if r.belowMin(lower) {
+			e.BinaryRangeExpr.LowerValue, e.BinaryRangeExpr.LowerInclusive = r.min, true
+		}
+		if r.aboveMax(upper) {
+			e.BinaryRangeExpr.UpperValue, e.BinaryRangeExpr.UpperInclusive = r.max, true
+		}
+	case *planpb.Expr_TermExpr:
+		r, ok := rangeOf(ranges, e.TermExpr.GetColumnInfo())
+		if !ok {
+			return expr, nil
+		}
+		values := make([]*planpb.GenericValue, 0, len(e.TermExpr.GetValues()))
+		for _, value := range e.TermExpr.GetValues() {
+			if !r.belowMin(value) && !r.aboveMax(value) {
+				values = append(values, value)
+				continue
+			}
+			if check == RangeCheckError {
+				return nil, errOutOfRange(schema, e.TermExpr.GetColumnInfo(), value, r)
+			}
+		}
+		e.TermExpr.Values = values
+	}
+	return expr, nil
+}
+
+// rangeOf returns the declared range of the column, if it's a numeric field itself rather than a JSON path.
+func rangeOf(ranges map[int64]*fieldRange, info *planpb.ColumnInfo) (*fieldRange, bool) {
+	if len(info.GetNestedPath()) != 0 {
+		return nil, false
+	}
+	r, ok := ranges[info.GetFieldId()]
+	return r, ok
+}
+
+// clampUnaryRange rewrites the comparison with a literal outside of the range.
+func clampUnaryRange(expr *planpb.Expr, e *planpb.UnaryRangeExpr, r *fieldRange) *planpb.Expr {
+	below := r.belowMin(e.GetValue())
+	switch e.GetOp() {
+	case planpb.OpType_Equal:
+		return alwaysFalseExpr()
+	case planpb.OpType_GreaterThan, planpb.OpType_GreaterEqual:
+		if !below {
+			return alwaysFalseExpr()
+		}
+		// matches every valid value, null values still don't match.
+		e.Op, e.Value = planpb.OpType_GreaterEqual, r.min
+	case planpb.OpType_LessThan, planpb.OpType_LessEqual:
+		if below {
+			return alwaysFalseExpr()
+		}
+		e.Op, e.Value = planpb.OpType_LessEqual, r.max
+	}
+	return expr
+}
+
+func errOutOfRange(schema *typeutil.SchemaHelper, info *planpb.ColumnInfo, value *planpb.GenericValue, r *fieldRange) error {
+	name := fmt.Sprint(info.GetFieldId())
+	if field, err := schema.GetFieldFromID(info.GetFieldId()); err == nil {
+		name = field.GetName()
+	}
+	return fmt.Errorf("literal %v is out of the range %s of field %s", extractGenericValue(value), r, name)
+}
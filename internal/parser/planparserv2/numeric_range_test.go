@@ -0,0 +1,90 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func newRangeSchemaHelper(t *testing.T, ageParams ...*commonpb.KeyValuePair) *typeutil.SchemaHelper {
+	schema, err := typeutil.CreateSchemaHelper(&schemapb.CollectionSchema{
+		Name: "test",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "id", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "age", DataType: schemapb.DataType_Int64, TypeParams: ageParams},
+			{FieldID: 102, Name: "score", DataType: schemapb.DataType_Double, TypeParams: []*commonpb.KeyValuePair{
+				{Key: RangeMinKey, Value: "0"}, {Key: RangeMaxKey, Value: "1"},
+			}},
+		},
+	})
+	require.NoError(t, err)
+	return schema
+}
+
+func TestWithRangeCheck(t *testing.T) {
+	schema := newRangeSchemaHelper(t, &commonpb.KeyValuePair{Key: RangeMinKey, Value: "0"}, &commonpb.KeyValuePair{Key: RangeMaxKey, Value: "150"})
+	parse := func(exprStr string, check RangeCheck) (*planpb.Expr, error) {
+		return ParseExpr(schema, exprStr, nil, WithRangeCheck(check))
+	}
+
+	for _, exprStr := range []string{`age > 500`, `age == 200`, `age < -1`, `age > 150`, `age in [-1, 200]`, `300 < age < 400`,
+		`score > 1.5`, `age > 100 and age > 500`} {
+		expr, err := parse(exprStr, RangeCheckClamp)
+		require.NoError(t, err, exprStr)
+		assert.True(t, IsTriviallyFalse(expr), exprStr)
+	}
+
+	expr, err := parse(`age < 500`, RangeCheckClamp)
+	require.NoError(t, err)
+	assert.Equal(t, planpb.OpType_LessEqual, expr.GetUnaryRangeExpr().GetOp())
+	assert.Equal(t, int64(150), expr.GetUnaryRangeExpr().GetValue().GetInt64Val())
+
+	expr, err = parse(`age >= -5`, RangeCheckClamp)
+	require.NoError(t, err)
+	assert.Equal(t, planpb.OpType_GreaterEqual, expr.GetUnaryRangeExpr().GetOp())
+	assert.Equal(t, int64(0), expr.GetUnaryRangeExpr().GetValue().GetInt64Val())
+
+	expr, err = parse(`-10 < age < 500`, RangeCheckClamp)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), expr.GetBinaryRangeExpr().GetLowerValue().GetInt64Val())
+	assert.True(t, expr.GetBinaryRangeExpr().GetLowerInclusive())
+	assert.Equal(t, int64(150), expr.GetBinaryRangeExpr().GetUpperValue().GetInt64Val())
+
+	expr, err = parse(`age in [1, 200]`, RangeCheckClamp)
+	require.NoError(t, err)
+	assert.Len(t, expr.GetTermExpr().GetValues(), 1)
+
+	expr, err = parse(`age != 500 and age > 10`, RangeCheckClamp)
+	require.NoError(t, err)
+	assert.False(t, IsTriviallyFalse(expr))
+
+	for _, exprStr := range []string{`age > 500`, `age in [1, 200]`, `-10 < age < 100`, `score == 2.0`} {
+		_, err := parse(exprStr, RangeCheckError)
+		assert.ErrorContains(t, err, "out of the range", exprStr)
+	}
+	_, err = parse(`age > 10 and score < 0.5`, RangeCheckError)
+	assert.NoError(t, err)
+
+	// without the option, the ranges are not trusted.
+	expr, err = ParseExpr(schema, `age > 500`, nil)
+	require.NoError(t, err)
+	assert.False(t, IsTriviallyFalse(expr))
+}
+
+func TestWithRangeCheck_InvalidRange(t *testing.T) {
+	for _, params := range [][]*commonpb.KeyValuePair{
+		{{Key: RangeMinKey, Value: "a"}},
+		{{Key: RangeMaxKey, Value: "1.5"}},
+		{{Key: RangeMinKey, Value: "10"}, {Key: RangeMaxKey, Value: "1"}},
+	} {
+		schema := newRangeSchemaHelper(t, params...)
+		_, err := ParseExpr(schema, `age > 1`, nil, WithRangeCheck(RangeCheckClamp))
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,111 @@
+package planparserv2
+
+import (
+	"context"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+// ParseOption customizes how an expression is parsed.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	ctx context.Context
+	// implicitDynamicField is nil if not set, which keeps the legacy fallback
+	// of unresolved identifiers into the dynamic field.
+	implicitDynamicField *bool
+	defaultValueForNull  bool
+	maxLengthCheck       MaxLengthCheck
+	role                 string
+	policyValues         map[string]*schemapb.TemplateValue
+	disabledOperators    []string
+	refreshCache         bool
+	scoreFilter          string
+	topKLimit            int64
+	systemFields         bool
+	requestContext       *RequestContext
+	strictJSONNumbers    bool
+	keepTermValues       bool
+	maxPlanSize          int64
+	partitionTargets     *[]string
+	prefixRangeFields    map[int64]struct{}
+	language             string
+	valueSetRefs         bool
+	executionBudget      *ExecutionBudget
+	warnings             *[]*ExprWarning
+	rangeCheck           RangeCheck
+	parseStats           *ParseStats
+	jsonTermCoercion     JSONTermCoercion
+	legacySyntax         bool
+	// macros overrides the macros defined by the collection if not nil.
+	macros map[string]string
+	// skipFieldAuthorization is set when parsing the row level policy.
+	skipFieldAuthorization bool
+	// skipAudit is set by the dry runs, which don't read data.
+	skipAudit bool
+	// systemFilter is set when parsing the filters defined by the system, like the constraints, which the row level
+	// policy and the expression interceptors of the requests don't apply to.
+	systemFilter bool
+}
+
+func newParseOptions(opts ...ParseOption) *parseOptions {
+	options := &parseOptions{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithContext sets the request context passed to the hooks, such as the FieldAuthorizer.
+func WithContext(ctx context.Context) ParseOption {
+	return func(options *parseOptions) {
+		options.ctx = ctx
+	}
+}
+
+// WithImplicitDynamicField controls whether unresolved bare identifiers like `color` are lowered to `$meta["color"]`.
+// If enabled, an identifier which differs from a schema field only in case is rejected as ambiguous.
+// If disabled, keys of the dynamic field can only be accessed with the bracket syntax.
+func WithImplicitDynamicField(enabled bool) ParseOption {
+	return func(options *parseOptions) {
+		options.implicitDynamicField = &enabled
+	}
+}
+
+// WithDefaultValueForNull makes comparisons on nullable fields treat null as the default value of the field.
+func WithDefaultValueForNull(enabled bool) ParseOption {
+	return func(options *parseOptions) {
+		options.defaultValueForNull = enabled
+	}
+}
+
+// WithMaxLengthCheck sets the action taken when a string literal compared for equality exceeds the max_length of the field.
+func WithMaxLengthCheck(check MaxLengthCheck) ParseOption {
+	return func(options *parseOptions) {
+		options.maxLengthCheck = check
+	}
+}
+
+func withoutFieldAuthorization() ParseOption {
+	return func(options *parseOptions) {
+		options.skipFieldAuthorization = true
+	}
+}
+
+// asSystemFilter parses the filters defined by the system instead of sent by a request, which aren't authorized,
+// audited, restricted by the row level policy nor rewritten by the expression interceptors.
+func asSystemFilter() ParseOption {
+	return func(options *parseOptions) {
+		options.skipFieldAuthorization = true
+		options.skipAudit = true
+		options.systemFilter = true
+	}
+}
+
+// WithCacheRefresh forces re-parsing the expression and overwrites its cache entry, which is useful to debug
+// stale cache entries. The `/*+ refresh_cache */` hint does the same.
+func WithCacheRefresh() ParseOption {
+	return func(options *parseOptions) {
+		options.refreshCache = true
+	}
+}
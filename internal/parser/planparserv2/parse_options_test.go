@@ -0,0 +1,43 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpr_ImplicitDynamicField(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	dynamicField, err := schema.GetDynamicField()
+	require.NoError(t, err)
+
+	// legacy behavior, unresolved identifiers fall into the dynamic field.
+	for _, exprStr := range []string{`color == "red"`, `int64field > 1`} {
+		expr, err := ParseExpr(schema, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		assert.Equal(t, dynamicField.GetFieldID(), expr.GetUnaryRangeExpr().GetColumnInfo().GetFieldId())
+	}
+
+	enabled := WithImplicitDynamicField(true)
+	expr, err := ParseExpr(schema, `color == "red"`, nil, enabled)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"color"}, expr.GetUnaryRangeExpr().GetColumnInfo().GetNestedPath())
+	expr, err = ParseExpr(schema, `color["shade"] == "dark"`, nil, enabled)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"color", "shade"}, expr.GetUnaryRangeExpr().GetColumnInfo().GetNestedPath())
+	_, err = ParseExpr(schema, `Int64Field > 1 and $meta["int64field"] > 1`, nil, enabled)
+	assert.NoError(t, err)
+	_, err = ParseExpr(schema, `int64field > 1`, nil, enabled)
+	assert.ErrorContains(t, err, "ambiguous with field Int64Field")
+	_, err = ParseExpr(schema, `jsonfield["a"] > 1`, nil, enabled)
+	assert.ErrorContains(t, err, "ambiguous with field JSONField")
+
+	disabled := WithImplicitDynamicField(false)
+	_, err = ParseExpr(schema, `color == "red"`, nil, disabled)
+	assert.ErrorContains(t, err, `use $meta["color"]`)
+	_, err = ParseExpr(schema, `color["shade"] == "dark"`, nil, disabled)
+	assert.Error(t, err)
+	_, err = ParseExpr(schema, `Int64Field > 1 and $meta["color"] == "red"`, nil, disabled)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,53 @@
+package planparserv2
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// ParseStats is the cost of parsing an expression, which the proxy reports to the quota center so that the tenants
+// sending expensive expressions can be throttled.
+type ParseStats struct {
+	// CPUTime is the time spent parsing the expression, including the failed attempts.
+	CPUTime time.Duration
+	// Nodes is the number of nodes of the parsed expression, zero if the parsing failed.
+	Nodes int
+}
+
+// WithParseStats stores the cost of parsing the expression into stats.
+func WithParseStats(stats *ParseStats) ParseOption {
+	return func(options *parseOptions) {
+		options.parseStats = stats
+	}
+}
+
+// countExprNodes returns the number of planpb.Expr nodes of the expression.
+func countExprNodes(expr *planpb.Expr) int {
+	count := 0
+	var walk func(message protoreflect.Message)
+	walk = func(message protoreflect.Message) {
+		if _, ok := message.Interface().(*planpb.Expr); ok {
+			count++
+		}
+		message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+			switch {
+			case field.Message() == nil || field.IsMap():
+			case field.IsList():
+				list := value.List()
+				for i := 0; i < list.Len(); i++ {
+					walk(list.Get(i).Message())
+				}
+			default:
+				walk(value.Message())
+			}
+			return true
+		})
+	}
+	if expr != nil {
+		walk(expr.ProtoReflect())
+	}
+	return count
+}
@@ -0,0 +1,28 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStats(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	stats := &ParseStats{}
+	_, err := ParseExpr(schema, "Int64Field > 1 and VarCharField == 'a'", nil, WithParseStats(stats))
+	require.NoError(t, err)
+	assert.Positive(t, stats.CPUTime)
+	// the conjunction and its two predicates.
+	assert.Equal(t, 3, stats.Nodes)
+
+	_, err = ParseExpr(schema, "Int64Field > 1 and Int64Field in [1, 2] or not (FloatField < 1.5)", nil, WithParseStats(stats))
+	require.NoError(t, err)
+	assert.Equal(t, 6, stats.Nodes)
+
+	_, err = ParseExpr(schema, "Int64Field >", nil, WithParseStats(stats))
+	require.Error(t, err)
+	assert.Positive(t, stats.CPUTime)
+	assert.Zero(t, stats.Nodes)
+}
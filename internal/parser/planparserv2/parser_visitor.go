@@ -16,11 +16,47 @@ import (
 
 type ParserVisitor struct {
 	parser.BasePlanVisitor
-	schema *typeutil.SchemaHelper
+	schema  *typeutil.SchemaHelper
+	options *parseOptions
 }
 
-func NewParserVisitor(schema *typeutil.SchemaHelper) *ParserVisitor {
-	return &ParserVisitor{schema: schema}
+func NewParserVisitor(schema *typeutil.SchemaHelper, opts ...ParseOption) *ParserVisitor {
+	return &ParserVisitor{schema: schema, options: newParseOptions(opts...)}
+}
+
+// getField resolves a field by name, unresolved names fall into the dynamic field if allowed.
+// The resolved field must pass the FieldAuthorizer if one is set.
+func (v *ParserVisitor) getField(name string) (*schemapb.FieldSchema, error) {
+	field, err := v.resolveField(name)
+	if err != nil {
+		return nil, err
+	}
+	if v.options.skipFieldAuthorization {
+		return field, nil
+	}
+	if err := authorizeField(v.options.ctx, v.schema, field); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+func (v *ParserVisitor) resolveField(name string) (*schemapb.FieldSchema, error) {
+	field, err := v.schema.GetFieldFromNameDefaultJSON(name)
+	if err != nil {
+		return nil, withErrorCode(ErrCodeFieldNotFound, err)
+	}
+	if !field.GetIsDynamic() || name == field.GetName() || v.options.implicitDynamicField == nil {
+		return field, nil
+	}
+	if !*v.options.implicitDynamicField {
+		return nil, withErrorCode(ErrCodeFieldNotFound, fmt.Errorf("field %s not exist, use %s[\"%s\"] to access the dynamic field", name, field.GetName(), name))
+	}
+	for _, f := range v.schema.GetSchema().GetFields() {
+		if strings.EqualFold(f.GetName(), name) {
+			return nil, fmt.Errorf("identifier %s is ambiguous with field %s, use %s[\"%s\"] to access the dynamic field", name, f.GetName(), field.GetName(), name)
+		}
+	}
+	return field, nil
 }
 
 // VisitParens unpack the parentheses.
@@ -30,18 +66,22 @@ func (v *ParserVisitor) VisitParens(ctx *parser.ParensContext) interface{} {
 
 func (v *ParserVisitor) translateIdentifier(identifier string) (*ExprWithType, error) {
 	identifier = decodeUnicode(identifier)
-	field, err := v.schema.GetFieldFromNameDefaultJSON(identifier)
+	field, err := v.getField(identifier)
 	if err != nil {
 		return nil, err
 	}
 	var nestedPath []string
-	if identifier != field.Name {
+	// unresolved names fall into the dynamic field, while aliases resolve to the field itself.
+	if field.GetIsDynamic() && identifier != field.Name {
 		nestedPath = append(nestedPath, identifier)
 	}
 
 	if field.DataType == schemapb.DataType_Text {
 		return nil, fmt.Errorf("filter on text field (%s) is not supported yet", field.Name)
 	}
+	if err := checkFunctionOutput(v.schema, field); err != nil {
+		return nil, err
+	}
 
 	return &ExprWithType{
 		expr: &planpb.Expr{
@@ -98,17 +138,20 @@ func (v *ParserVisitor) VisitBoolean(ctx *parser.BooleanContext) interface{} {
 
 // VisitInteger translates expr to GenericValue.
 func (v *ParserVisitor) VisitInteger(ctx *parser.IntegerContext) interface{} {
-	literal := ctx.IntegerConstant().GetText()
-	i, err := strconv.ParseInt(literal, 0, 64)
+	return v.translateInteger(ctx.IntegerConstant().GetText())
+}
+
+func (v *ParserVisitor) translateInteger(literal string) interface{} {
+	value, dataType, err := parseIntegerLiteral(literal)
 	if err != nil {
 		return err
 	}
 	return &ExprWithType{
-		dataType: schemapb.DataType_Int64,
+		dataType: dataType,
 		expr: &planpb.Expr{
 			Expr: &planpb.Expr_ValueExpr{
 				ValueExpr: &planpb.ValueExpr{
-					Value: NewInt(i),
+					Value: value,
 				},
 			},
 		},
@@ -181,14 +224,22 @@ func (v *ParserVisitor) VisitAddSub(ctx *parser.AddSubContext) interface{} {
 			return fmt.Errorf("placeholder was not supported between two constants with operator: %s", ctx.GetOp().GetText())
 		}
 		leftValue, rightValue := leftValueExpr.GetValue(), rightValueExpr.GetValue()
+		if err := checkIntegerOverflow(ctx.GetOp().GetTokenType(), leftValue, rightValue); err != nil {
+			return err
+		}
+		var ret *ExprWithType
 		switch ctx.GetOp().GetTokenType() {
 		case parser.PlanParserADD:
-			return Add(leftValue, rightValue)
+			ret = Add(leftValue, rightValue)
 		case parser.PlanParserSUB:
-			return Subtract(leftValue, rightValue)
+			ret = Subtract(leftValue, rightValue)
 		default:
 			return fmt.Errorf("unexpected op: %s", ctx.GetOp().GetText())
 		}
+		if ret != nil {
+			ret.timeValue = getExpr(left).timeValue || getExpr(right).timeValue
+		}
+		return ret
 	}
 
 	leftExpr, rightExpr := getExpr(left), getExpr(right)
@@ -260,6 +311,9 @@ func (v *ParserVisitor) VisitMulDivMod(ctx *parser.MulDivModContext) interface{}
 			return fmt.Errorf("placeholder was not supported between two constants with operator: %s", ctx.GetOp().GetText())
 		}
 		leftValue, rightValue := getGenericValue(left), getGenericValue(right)
+		if err := checkIntegerOverflow(ctx.GetOp().GetTokenType(), leftValue, rightValue); err != nil {
+			return err
+		}
 		switch ctx.GetOp().GetTokenType() {
 		case parser.PlanParserMUL:
 			return Multiply(leftValue, rightValue)
@@ -348,6 +402,11 @@ func (v *ParserVisitor) VisitEquality(ctx *parser.EqualityContext) interface{} {
 		return err
 	}
 
+	left, right, err := v.convertTimeOperands(left, right, cmpOpMap[ctx.GetOp().GetTokenType()])
+	if err != nil {
+		return err
+	}
+
 	leftValueExpr, rightValueExpr := getValueExpr(left), getValueExpr(right)
 	if leftValueExpr != nil && rightValueExpr != nil {
 		if isTemplateExpr(leftValueExpr) || isTemplateExpr(rightValueExpr) {
@@ -393,6 +452,11 @@ func (v *ParserVisitor) VisitRelational(ctx *parser.RelationalContext) interface
 	if err := getError(right); err != nil {
 		return err
 	}
+	left, right, err := v.convertTimeOperands(left, right, cmpOpMap[ctx.GetOp().GetTokenType()])
+	if err != nil {
+		return err
+	}
+
 	leftValueExpr, rightValueExpr := getValueExpr(left), getValueExpr(right)
 	if leftValueExpr != nil && rightValueExpr != nil {
 		if isTemplateExpr(leftValueExpr) || isTemplateExpr(rightValueExpr) {
@@ -487,24 +551,39 @@ func (v *ParserVisitor) VisitLike(ctx *parser.LikeContext) interface{} {
 }
 
 func (v *ParserVisitor) VisitTextMatch(ctx *parser.TextMatchContext) interface{} {
-	column, err := v.translateIdentifier(ctx.Identifier().GetText())
+	queryText, err := convertEscapeSingle(ctx.StringLiteral().GetText())
 	if err != nil {
 		return err
 	}
+	expr, err := v.translateTextMatch(ctx.Identifier().GetText(), queryText)
+	if err != nil {
+		return err
+	}
+	return expr
+}
+
+func (v *ParserVisitor) translateTextMatch(identifier string, queryText string) (*ExprWithType, error) {
+	column, err := v.translateIdentifier(identifier)
+	if err != nil {
+		return nil, err
+	}
 	columnInfo := toColumnInfo(column)
 	if !v.schema.IsFieldTextMatchEnabled(columnInfo.FieldId) {
-		return fmt.Errorf("field %v does not enable text match", columnInfo.FieldId)
+		if field, err := v.schema.GetFieldFromID(columnInfo.FieldId); err == nil {
+			if description := describeFunctionInput(v.schema, field); description != "" {
+				return nil, fmt.Errorf("field %s does not enable text match, %s", field.GetName(), description)
+			}
+		}
+		return nil, fmt.Errorf("field %v does not enable text match", columnInfo.FieldId)
 	}
 	if !typeutil.IsStringType(column.dataType) {
-		return fmt.Errorf("text match operation on non-string is unsupported")
+		return nil, fmt.Errorf("text match operation on non-string is unsupported")
 	}
 	if column.dataType == schemapb.DataType_Text {
-		return fmt.Errorf("text match operation on text field is not supported yet")
+		return nil, fmt.Errorf("text match operation on text field is not supported yet")
 	}
-
-	queryText, err := convertEscapeSingle(ctx.StringLiteral().GetText())
-	if err != nil {
-		return err
+	if err := v.checkTextQuery(planpb.OpType_TextMatch, columnInfo, queryText); err != nil {
+		return nil, err
 	}
 
 	return &ExprWithType{
@@ -518,17 +597,10 @@ func (v *ParserVisitor) VisitTextMatch(ctx *parser.TextMatchContext) interface{}
 			},
 		},
 		dataType: schemapb.DataType_Bool,
-	}
+	}, nil
 }
 
 func (v *ParserVisitor) VisitPhraseMatch(ctx *parser.PhraseMatchContext) interface{} {
-	column, err := v.translateIdentifier(ctx.Identifier().GetText())
-	if err != nil {
-		return err
-	}
-	if !typeutil.IsStringType(column.dataType) {
-		return fmt.Errorf("phrase match operation on non-string is unsupported")
-	}
 	queryText, err := convertEscapeSingle(ctx.StringLiteral().GetText())
 	if err != nil {
 		return err
@@ -550,6 +622,25 @@ func (v *ParserVisitor) VisitPhraseMatch(ctx *parser.PhraseMatchContext) interfa
 		}
 	}
 
+	expr, err := v.translatePhraseMatch(ctx.Identifier().GetText(), queryText, slop)
+	if err != nil {
+		return err
+	}
+	return expr
+}
+
+func (v *ParserVisitor) translatePhraseMatch(identifier string, queryText string, slop int64) (*ExprWithType, error) {
+	column, err := v.translateIdentifier(identifier)
+	if err != nil {
+		return nil, err
+	}
+	if !typeutil.IsStringType(column.dataType) {
+		return nil, fmt.Errorf("phrase match operation on non-string is unsupported")
+	}
+	if err := v.checkTextQuery(planpb.OpType_PhraseMatch, toColumnInfo(column), queryText); err != nil {
+		return nil, err
+	}
+
 	return &ExprWithType{
 		expr: &planpb.Expr{
 			Expr: &planpb.Expr_UnaryRangeExpr{
@@ -562,7 +653,7 @@ func (v *ParserVisitor) VisitPhraseMatch(ctx *parser.PhraseMatchContext) interfa
 			},
 		},
 		dataType: schemapb.DataType_Bool,
-	}
+	}, nil
 }
 
 func isRandomSampleExpr(expr *ExprWithType) bool {
@@ -604,6 +695,9 @@ func (v *ParserVisitor) VisitRandomSample(ctx *parser.RandomSampleContext) inter
 
 // VisitTerm translates expr to term plan.
 func (v *ParserVisitor) VisitTerm(ctx *parser.TermContext) interface{} {
+	if isPartitionCall(ctx.Expr(0)) {
+		return v.translatePartitionTerm(ctx)
+	}
 	child := ctx.Expr(0).Accept(v)
 	if err := getError(child); err != nil {
 		return err
@@ -624,10 +718,13 @@ func (v *ParserVisitor) VisitTerm(ctx *parser.TermContext) interface{} {
 		dataType = columnInfo.GetElementType()
 	}
 
-	term := ctx.Expr(1).Accept(v)
+	term, hasNull := v.visitTermList(ctx.Expr(1))
 	if getError(term) != nil {
 		return term
 	}
+	if hasNull && ctx.GetOp() != nil {
+		return fmt.Errorf("'not in' can't list null, which would make it match no entity, use `is not null` to exclude null values")
+	}
 
 	valueExpr := getValueExpr(term)
 	var placeholder string
@@ -651,6 +748,9 @@ func (v *ParserVisitor) VisitTerm(ctx *parser.TermContext) interface{} {
 		for i, e := range array {
 			castedValue, err := castValue(dataType, e)
 			if err != nil {
+				if isBeyondInt64(e) {
+					return err
+				}
 				return fmt.Errorf("value '%s' in list cannot be casted to %s", e.String(), dataType.String())
 			}
 			values[i] = castedValue
@@ -699,7 +799,52 @@ func (v *ParserVisitor) getChildColumnInfo(identifier, child antlr.TerminalNode)
 // VisitCall parses the expr to call plan.
 func (v *ParserVisitor) VisitCall(ctx *parser.CallContext) interface{} {
 	functionName := strings.ToLower(ctx.Identifier().GetText())
+	if functionName == queryStringFunctionName {
+		return v.visitQueryString(ctx)
+	}
+	if functionName == partitionFunctionName {
+		return errPartitionOutsideTerm()
+	}
+	if functionName == valueSetRefFunctionName {
+		return fmt.Errorf("%s() can only be used as the list of 'in'", valueSetRefFunctionName)
+	}
 	numParams := len(ctx.AllExpr())
+	if functionName == primaryKeyFunctionName {
+		expr, err := v.translatePrimaryKey(numParams)
+		if err != nil {
+			return err
+		}
+		return expr
+	}
+	if fieldID, ok := systemFieldFunctions[functionName]; ok && v.options.systemFields {
+		expr, err := v.translateSystemField(functionName, fieldID, numParams)
+		if err != nil {
+			return err
+		}
+		return expr
+	}
+	if functionName == nowFunctionName || functionName == intervalFunctionName {
+		params := make([]*ExprWithType, 0, numParams)
+		for _, param := range ctx.AllExpr() {
+			paramExpr := param.Accept(v)
+			if err := getError(paramExpr); err != nil {
+				return err
+			}
+			params = append(params, getExpr(paramExpr))
+		}
+		expr, err := v.translateTimeFunction(functionName, params)
+		if err != nil {
+			return err
+		}
+		return expr
+	}
+	if _, ok := contextFunctions[functionName]; ok {
+		expr, err := v.translateContextFunction(functionName, numParams)
+		if err != nil {
+			return err
+		}
+		return expr
+	}
 	funcParameters := make([]*planpb.Expr, 0, numParams)
 	for _, param := range ctx.AllExpr() {
 		paramExpr := getExpr(param.Accept(v))
@@ -739,6 +884,12 @@ func (v *ParserVisitor) VisitRange(ctx *parser.RangeContext) interface{} {
 	if err := getError(upper); err != nil {
 		return err
 	}
+	if lower, err = v.convertTimeValue(columnInfo, lower, ctx.GetOp1().GetTokenType() == parser.PlanParserLE); err != nil {
+		return err
+	}
+	if upper, err = v.convertTimeValue(columnInfo, upper, ctx.GetOp2().GetTokenType() == parser.PlanParserLT); err != nil {
+		return err
+	}
 
 	lowerValueExpr, upperValueExpr := getValueExpr(lower), getValueExpr(upper)
 	if lowerValueExpr == nil {
@@ -821,6 +972,12 @@ func (v *ParserVisitor) VisitReverseRange(ctx *parser.ReverseRangeContext) inter
 	if err := getError(upper); err != nil {
 		return err
 	}
+	if lower, err = v.convertTimeValue(columnInfo, lower, ctx.GetOp2().GetTokenType() == parser.PlanParserGE); err != nil {
+		return err
+	}
+	if upper, err = v.convertTimeValue(columnInfo, upper, ctx.GetOp1().GetTokenType() == parser.PlanParserGT); err != nil {
+		return err
+	}
 
 	lowerValueExpr, upperValueExpr := getValueExpr(lower), getValueExpr(upper)
 	if lowerValueExpr == nil {
@@ -883,6 +1040,10 @@ func (v *ParserVisitor) VisitReverseRange(ctx *parser.ReverseRangeContext) inter
 
 // VisitUnary unpack the +expr to expr.
 func (v *ParserVisitor) VisitUnary(ctx *parser.UnaryContext) interface{} {
+	// -9223372036854775808 is only in the int64 range with its sign.
+	if integer, ok := ctx.Expr().(*parser.IntegerContext); ok && ctx.GetOp().GetTokenType() == parser.PlanParserSUB {
+		return v.translateInteger("-" + integer.IntegerConstant().GetText())
+	}
 	child := ctx.Expr().Accept(v)
 	if err := getError(child); err != nil {
 		return err
@@ -894,6 +1055,9 @@ func (v *ParserVisitor) VisitUnary(ctx *parser.UnaryContext) interface{} {
 		case parser.PlanParserADD:
 			return child
 		case parser.PlanParserSUB:
+			if IsInteger(childValue) && childValue.GetInt64Val() == math.MinInt64 {
+				return fmt.Errorf("integer overflow: -(%d) is out of the int64 range", childValue.GetInt64Val())
+			}
 			return Negative(childValue)
 		case parser.PlanParserNOT:
 			return Not(childValue)
@@ -917,6 +1081,9 @@ func (v *ParserVisitor) VisitUnary(ctx *parser.UnaryContext) interface{} {
 	case parser.PlanParserADD:
 		return childExpr
 	case parser.PlanParserNOT:
+		if err := checkBareBoolColumn(v.schema, childExpr); err != nil {
+			return err
+		}
 		if !canBeExecuted(childExpr) {
 			return fmt.Errorf("%s op can only be applied on boolean expression", unaryLogicalNameMap[parser.PlanParserNOT])
 		}
@@ -964,6 +1131,12 @@ func (v *ParserVisitor) VisitLogicalOr(ctx *parser.LogicalOrContext) interface{}
 	var rightExpr *ExprWithType
 	leftExpr = getExpr(left)
 	rightExpr = getExpr(right)
+	if err := checkBareBoolColumn(v.schema, leftExpr); err != nil {
+		return err
+	}
+	if err := checkBareBoolColumn(v.schema, rightExpr); err != nil {
+		return err
+	}
 	if isRandomSampleExpr(leftExpr) || isRandomSampleExpr(rightExpr) {
 		return fmt.Errorf("random sample expression cannot be used in logical and expression")
 	}
@@ -1016,6 +1189,12 @@ func (v *ParserVisitor) VisitLogicalAnd(ctx *parser.LogicalAndContext) interface
 	var rightExpr *ExprWithType
 	leftExpr = getExpr(left)
 	rightExpr = getExpr(right)
+	if err := checkBareBoolColumn(v.schema, leftExpr); err != nil {
+		return err
+	}
+	if err := checkBareBoolColumn(v.schema, rightExpr); err != nil {
+		return err
+	}
 	if isRandomSampleExpr(leftExpr) {
 		return fmt.Errorf("random sample expression can only be the last expression in the logical and expression")
 	}
@@ -1023,6 +1202,13 @@ func (v *ParserVisitor) VisitLogicalAnd(ctx *parser.LogicalAndContext) interface
 	if !canBeExecuted(leftExpr) || !canBeExecuted(rightExpr) {
 		return fmt.Errorf("'and' can only be used between boolean expressions")
 	}
+	// the conjuncts targeting partitions are always true.
+	if isAlwaysTrueExpr(leftExpr.expr) {
+		return rightExpr
+	}
+	if isAlwaysTrueExpr(rightExpr.expr) {
+		return leftExpr
+	}
 
 	var expr *planpb.Expr
 	if isRandomSampleExpr(rightExpr) {
@@ -1122,10 +1308,12 @@ func (v *ParserVisitor) VisitBitOr(ctx *parser.BitOrContext) interface{} {
 */
 // More tests refer to plan_parser_v2_test.go::Test_JSONExpr
 func (v *ParserVisitor) getColumnInfoFromJSONIdentifier(identifier string) (*planpb.ColumnInfo, error) {
-	identifier = decodeUnicode(identifier)
-	fieldName := strings.Split(identifier, "[")[0]
+	fieldName, keys, err := splitJSONIdentifier(identifier)
+	if err != nil {
+		return nil, err
+	}
 	nestedPath := make([]string, 0)
-	field, err := v.schema.GetFieldFromNameDefaultJSON(fieldName)
+	field, err := v.getField(fieldName)
 	if err != nil {
 		return nil, err
 	}
@@ -1134,29 +1322,21 @@ func (v *ParserVisitor) getColumnInfoFromJSONIdentifier(identifier string) (*pla
 		errMsg := fmt.Sprintf("%s data type not supported accessed with []", field.GetDataType())
 		return nil, fmt.Errorf(errMsg)
 	}
-	if fieldName != field.Name {
+	if field.GetIsDynamic() && fieldName != field.Name {
 		nestedPath = append(nestedPath, fieldName)
 	}
-	jsonKeyStr := identifier[len(fieldName):]
-	ss := strings.Split(jsonKeyStr, "][")
-	for i := 0; i < len(ss); i++ {
-		path := strings.Trim(ss[i], "[]")
-		if path == "" {
+	for _, key := range keys {
+		if key.key == "" {
 			return nil, fmt.Errorf("invalid identifier: %s", identifier)
 		}
-		if (strings.HasPrefix(path, "\"") && strings.HasSuffix(path, "\"")) ||
-			(strings.HasPrefix(path, "'") && strings.HasSuffix(path, "'")) {
-			path = path[1 : len(path)-1]
-			if path == "" {
-				return nil, fmt.Errorf("invalid identifier: %s", identifier)
-			}
+		if key.quoted {
 			if typeutil.IsArrayType(field.DataType) {
 				return nil, fmt.Errorf("can only access array field with integer index")
 			}
-		} else if _, err := strconv.ParseInt(path, 10, 64); err != nil {
-			return nil, fmt.Errorf("json key must be enclosed in double quotes or single quotes: \"%s\"", path)
+		} else if _, err := strconv.ParseInt(key.key, 10, 64); err != nil {
+			return nil, fmt.Errorf("json key must be enclosed in double quotes or single quotes: \"%s\"", key.key)
 		}
-		nestedPath = append(nestedPath, path)
+		nestedPath = append(nestedPath, key.key)
 	}
 
 	return &planpb.ColumnInfo{
@@ -1228,7 +1408,10 @@ func (v *ParserVisitor) VisitExists(ctx *parser.ExistsContext) interface{} {
 }
 
 func (v *ParserVisitor) VisitArray(ctx *parser.ArrayContext) interface{} {
-	allExpr := ctx.AllExpr()
+	return v.translateArray(ctx.AllExpr())
+}
+
+func (v *ParserVisitor) translateArray(allExpr []parser.IExprContext) interface{} {
 	array := make([]*planpb.GenericValue, len(allExpr))
 	dType := schemapb.DataType_None
 	sameType := true
@@ -0,0 +1,83 @@
+package planparserv2
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	parser "github.com/milvus-io/milvus/internal/parser/planparserv2/generated"
+)
+
+// partitionFunctionName is the pseudo field of the partition of entities, used as `partition() in ["p1", "p2"]`.
+const partitionFunctionName = "partition"
+
+// WithPartitionTargets accepts the `partition() in [...]` conjuncts of the filter, which target the listed partitions
+// instead of filtering rows. The names of the targeted partitions are stored into targets, and left nil if the filter
+// targets no partition. Several conjuncts target the partitions they have in common.
+// Without this option, partition() is rejected.
+func WithPartitionTargets(targets *[]string) ParseOption {
+	return func(options *parseOptions) {
+		options.partitionTargets = targets
+	}
+}
+
+func isPartitionCall(ctx parser.IExprContext) bool {
+	call, ok := ctx.(*parser.CallContext)
+	return ok && strings.ToLower(call.Identifier().GetText()) == partitionFunctionName
+}
+
+func errPartitionOutsideTerm() error {
+	return fmt.Errorf("%s() can only be used as `%s() in [...]` in the conjunction of the filter", partitionFunctionName, partitionFunctionName)
+}
+
+// translatePartitionTerm records the partitions targeted by `partition() in [...]`, which is always true for
+// the entities of the searched partitions.
+func (v *ParserVisitor) translatePartitionTerm(ctx *parser.TermContext) interface{} {
+	if v.options.partitionTargets == nil {
+		return fmt.Errorf("%s() is not supported in this request", partitionFunctionName)
+	}
+	if len(ctx.Expr(0).(*parser.CallContext).AllExpr()) != 0 {
+		return fmt.Errorf("%s() takes no argument", partitionFunctionName)
+	}
+	// not in is rejected, like any use outside of the top level conjunction.
+	if ctx.GetOp() != nil {
+		return errPartitionOutsideTerm()
+	}
+	for parent := ctx.GetParent(); parent != nil; parent = parent.GetParent() {
+		switch parent.(type) {
+		case *parser.LogicalAndContext, *parser.ParensContext:
+		default:
+			return errPartitionOutsideTerm()
+		}
+	}
+
+	term := ctx.Expr(1).Accept(v)
+	if err := getError(term); err != nil {
+		return err
+	}
+	value := getValueExpr(term).GetValue()
+	if !IsArray(value) {
+		return fmt.Errorf("%s() must be compared with a list of partition names, but got: %s", partitionFunctionName, ctx.Expr(1).GetText())
+	}
+	names := make([]string, 0, len(value.GetArrayVal().GetArray()))
+	for _, element := range value.GetArrayVal().GetArray() {
+		if !IsString(element) {
+			return fmt.Errorf("partition names must be strings, but got: %s", ctx.Expr(1).GetText())
+		}
+		if !slices.Contains(names, element.GetStringVal()) {
+			names = append(names, element.GetStringVal())
+		}
+	}
+
+	targets := v.options.partitionTargets
+	if *targets != nil {
+		names = slices.DeleteFunc(names, func(name string) bool {
+			return !slices.Contains(*targets, name)
+		})
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%s() in [...] targets no partition", partitionFunctionName)
+	}
+	*targets = names
+	return trueLiteral
+}
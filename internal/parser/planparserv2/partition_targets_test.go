@@ -0,0 +1,56 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionTargets(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	var targets []string
+	expr, err := ParseExpr(helper, `partition() in ["p_2024_01", "p_2024_02", "p_2024_01"] and Int64Field > 1`, nil, WithPartitionTargets(&targets))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p_2024_01", "p_2024_02"}, targets)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+
+	targets = nil
+	expr, err = ParseExpr(helper, `Int64Field > 1 and (PARTITION() in ["a", "b"] and partition() in ["b", "c"])`, nil, WithPartitionTargets(&targets))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, targets)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+
+	targets = nil
+	expr, err = ParseExpr(helper, `partition() in ["a"]`, nil, WithPartitionTargets(&targets))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, targets)
+	assert.True(t, isAlwaysTrueExpr(expr))
+
+	targets = nil
+	_, err = ParseExpr(helper, `Int64Field > 1`, nil, WithPartitionTargets(&targets))
+	require.NoError(t, err)
+	assert.Nil(t, targets)
+
+	invalid := []string{
+		`partition() in ["a"] or Int64Field > 1`,
+		`not (partition() in ["a"])`,
+		`partition() not in ["a"]`,
+		`partition() == "a"`,
+		`partition("a") in ["a"]`,
+		`partition() in [1]`,
+		`partition() in []`,
+		`partition() in {names}`,
+		`partition() in ["a"] and partition() in ["b"]`,
+	}
+	for _, exprStr := range invalid {
+		targets = nil
+		_, err = ParseExpr(helper, exprStr, nil, WithPartitionTargets(&targets))
+		assert.Error(t, err, exprStr)
+	}
+
+	// partition() is rejected by the requests which can't target partitions.
+	_, err = ParseExpr(helper, `partition() in ["a"]`, nil)
+	assert.ErrorContains(t, err, "not supported")
+}
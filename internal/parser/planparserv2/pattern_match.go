@@ -1,6 +1,11 @@
 package planparserv2
 
 import (
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+
 	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
 )
 
@@ -67,3 +72,56 @@ func translatePatternMatch(pattern string) (op planpb.OpType, operand string, er
 
 	return planpb.OpType_Match, pattern, nil
 }
+
+// likePatterns caches the compiled like patterns by pattern, so that the hot patterns are compiled once instead of
+// for every row or segment.
+var likePatterns = expirable.NewLRU[string, *regexp.Regexp](1024, nil, time.Minute*10)
+
+// CompileLikePattern returns the regular expression matching the like pattern, compiled once per pattern. The
+// regular expression is safe for concurrent use.
+func CompileLikePattern(pattern string) (*regexp.Regexp, error) {
+	if matcher, ok := likePatterns.Get(pattern); ok {
+		return matcher, nil
+	}
+	matcher, err := likeRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	likePatterns.Add(pattern, matcher)
+	return matcher, nil
+}
+
+// compileLikePatterns compiles the like patterns matched by the expression, keyed by pattern.
+func compileLikePatterns(expr *planpb.Expr) (map[string]*regexp.Regexp, error) {
+	patterns := make(map[string]*regexp.Regexp)
+	var walk func(expr *planpb.Expr) error
+	walk = func(expr *planpb.Expr) error {
+		switch e := expr.GetExpr().(type) {
+		case *planpb.Expr_UnaryExpr:
+			return walk(e.UnaryExpr.GetChild())
+		case *planpb.Expr_BinaryExpr:
+			if err := walk(e.BinaryExpr.GetLeft()); err != nil {
+				return err
+			}
+			return walk(e.BinaryExpr.GetRight())
+		case *planpb.Expr_UnaryRangeExpr:
+			if e.UnaryRangeExpr.GetOp() != planpb.OpType_Match {
+				return nil
+			}
+			pattern := e.UnaryRangeExpr.GetValue().GetStringVal()
+			if _, ok := patterns[pattern]; ok {
+				return nil
+			}
+			matcher, err := CompileLikePattern(pattern)
+			if err != nil {
+				return err
+			}
+			patterns[pattern] = matcher
+		}
+		return nil
+	}
+	if err := walk(expr); err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
@@ -3,6 +3,9 @@ package planparserv2
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
 )
 
@@ -144,3 +147,30 @@ func Test_translatePatternMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestCompileLikePattern(t *testing.T) {
+	matcher, err := CompileLikePattern("a%b_c")
+	require.NoError(t, err)
+	assert.True(t, matcher.MatchString("axxbyc"))
+	assert.False(t, matcher.MatchString("abc"))
+	cached, err := CompileLikePattern("a%b_c")
+	require.NoError(t, err)
+	assert.Same(t, matcher, cached)
+
+	schema := newTestSchemaHelper(t)
+	expr, err := ParseExpr(schema, `VarCharField like "%a%" or (VarCharField like "b%c" and not (VarCharField like "%a%"))`, nil)
+	require.NoError(t, err)
+	patterns, err := compileLikePatterns(expr)
+	require.NoError(t, err)
+	assert.Len(t, patterns, 2)
+	matcher, err = CompileLikePattern("%a%")
+	require.NoError(t, err)
+	assert.Same(t, matcher, patterns["%a%"])
+	assert.Contains(t, patterns, "b%c")
+
+	expr, err = ParseExpr(schema, `VarCharField like "a%"`, nil)
+	require.NoError(t, err)
+	patterns, err = compileLikePatterns(expr)
+	require.NoError(t, err)
+	assert.Empty(t, patterns, "prefix matches need no regular expression")
+}
@@ -25,9 +25,10 @@ var (
 	}
 )
 
-func handleInternal(exprStr string) (ast planparserv2.IExprContext, err error) {
+// handleInternal parses the expression into a syntax tree, refresh forces re-parsing and overwrites the cache entry.
+func handleInternal(exprStr string, refresh bool) (ast planparserv2.IExprContext, err error) {
 	val, ok := exprCache.Get(exprStr)
-	if ok {
+	if ok && !refresh {
 		switch v := val.(type) {
 		case planparserv2.IExprContext:
 			return v, nil
@@ -44,7 +45,7 @@ func handleInternal(exprStr string) (ast planparserv2.IExprContext, err error) {
 			exprCache.Add(exprStr, err)
 		}
 	}()
-	exprNormal := convertHanToASCII(exprStr)
+	exprNormal := rewriteIsBoolean(convertUnicode(exprStr))
 	listener := &errorListenerImpl{}
 
 	inputStream := antlr.NewInputStream(exprNormal)
@@ -64,8 +65,8 @@ func handleInternal(exprStr string) (ast planparserv2.IExprContext, err error) {
 	}
 
 	if parser.GetCurrentToken().GetTokenType() != antlr.TokenEOF {
-		log.Info("invalid expression", zap.String("expr", exprStr))
-		err = fmt.Errorf("invalid expression: %s", exprStr)
+		log.Info("invalid expression", zap.String("expr", displayExpr(exprStr)))
+		err = withErrorCode(ErrCodeSyntax, fmt.Errorf("invalid expression: %s", displayExpr(exprStr)))
 		return
 	}
 
@@ -77,51 +78,180 @@ func handleInternal(exprStr string) (ast planparserv2.IExprContext, err error) {
 	return
 }
 
-func handleExpr(schema *typeutil.SchemaHelper, exprStr string) interface{} {
+// handleExpr parses the expression and translates it with the visitor. Panics, such as the ones raised by
+// malformed input or unexpected schemas, are recovered into errors, so that a bad filter can't crash the caller.
+func handleExpr(schema *typeutil.SchemaHelper, exprStr string, opts ...ParseOption) (ret interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Warn("panic while parsing expression", zap.String("expr", redactLiterals(exprStr)),
+				zap.Any("panic", r), zap.Stack("stack"))
+			ret = merr.WrapErrServiceInternal(fmt.Sprintf("panic while parsing expression: %v", r))
+		}
+	}()
+	hints, exprStr, err := ParseHints(exprStr)
+	if err != nil {
+		return err
+	}
+	visitor := NewParserVisitor(schema, opts...)
+	exprStr, err = expandMacros(schema, exprStr, visitor.options)
+	if err != nil {
+		return err
+	}
 	if isEmptyExpression(exprStr) {
 		return trueLiteral
 	}
-	ast, err := handleInternal(exprStr)
+	ast, err := handleInternal(exprStr, visitor.options.refreshCache || hasHint(hints, refreshCacheHint))
 	if err != nil {
 		return err
 	}
 
-	visitor := NewParserVisitor(schema)
-	return ast.Accept(visitor)
+	ret = ast.Accept(visitor)
+	if err := getError(ret); err != nil {
+		return checkSchemaOutdated(visitor.options.ctx, schema, ast, err)
+	}
+	return ret
 }
 
-func ParseExpr(schema *typeutil.SchemaHelper, exprStr string, exprTemplateValues map[string]*schemapb.TemplateValue) (*planpb.Expr, error) {
-	ret := handleExpr(schema, exprStr)
+func ParseExpr(schema *typeutil.SchemaHelper, exprStr string, exprTemplateValues map[string]*schemapb.TemplateValue, opts ...ParseOption) (ret *planpb.Expr, err error) {
+	options := newParseOptions(opts...)
+	defer func() {
+		err = LocalizeError(err, options.language)
+	}()
+	if options.parseStats != nil {
+		start := time.Now()
+		defer func(stats *ParseStats) {
+			stats.CPUTime = time.Since(start)
+			stats.Nodes = 0
+			if err == nil {
+				stats.Nodes = countExprNodes(ret)
+			}
+		}(options.parseStats)
+	}
+
+	if options.warnings != nil {
+		*options.warnings = nil
+	}
+	// the audit records the expression as sent, the translation is only parsed.
+	parsedStr := exprStr
+	if options.legacySyntax {
+		var deprecated []*ExprWarning
+		parsedStr, deprecated = TranslateLegacyExpr(schema, exprStr)
+		if options.warnings != nil {
+			*options.warnings = append(*options.warnings, deprecated...)
+		}
+	}
+	expr, err := parseExpr(schema, parsedStr, exprTemplateValues, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if options.warnings != nil {
+		*options.warnings = append(*options.warnings, lintExpr(schema, expr)...)
+	}
+
+	if err := checkDisabledOperators(schema, expr, options); err != nil {
+		return nil, err
+	}
+	hook := auditHook.Load()
+	if options.skipAudit {
+		hook = nil
+	}
+	var fields []*FieldReference
+	if hook != nil {
+		fields = collectFieldReferences(schema, expr)
+	}
+	if options.maxLengthCheck != MaxLengthCheckNone {
+		if err := checkMaxLength(schema, expr, options.maxLengthCheck); err != nil {
+			return nil, err
+		}
+	}
+	if options.strictJSONNumbers {
+		if err := checkJSONNumbers(schema, expr); err != nil {
+			return nil, err
+		}
+	}
+	if err := dispatchJSONTerms(expr, options.jsonTermCoercion); err != nil {
+		return nil, err
+	}
+	expr = guardNegatedJSONContains(expr)
+	if !options.keepTermValues {
+		normalizeTermValues(expr)
+	}
+	if options.prefixRangeFields != nil {
+		expr = rewritePrefixRanges(expr, options.prefixRangeFields)
+	}
+	if options.rangeCheck != RangeCheckNone {
+		ranges, err := fieldRanges(schema)
+		if err != nil {
+			return nil, err
+		}
+		if expr, err = applyFieldRanges(schema, expr, ranges, options.rangeCheck); err != nil {
+			return nil, err
+		}
+		expr = collapseContradictionsWithin(expr, ranges)
+	} else {
+		expr = collapseContradictions(expr)
+	}
+	expr = reorderConjunctions(schema, expr)
+	if options.defaultValueForNull {
+		expr = applyDefaultValues(schema, expr)
+	}
+	if !options.systemFilter {
+		expr, err = applyExprInterceptors(options.ctx, schema, expr)
+		if err != nil {
+			return nil, err
+		}
+		expr, err = applyRowLevelPolicy(schema, expr, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if hook != nil {
+		(*hook)(options.ctx, &AuditEvent{
+			Expr:           exprStr,
+			Database:       schema.GetDatabaseName(),
+			CollectionID:   schema.GetCollectionID(),
+			CollectionName: schema.GetCollectionName(),
+			Role:           options.role,
+			Fields:         fields,
+		})
+	}
+	return expr, nil
+}
+
+func parseExpr(schema *typeutil.SchemaHelper, exprStr string, exprTemplateValues map[string]*schemapb.TemplateValue, opts ...ParseOption) (*planpb.Expr, error) {
+	ret := handleExpr(schema, exprStr, opts...)
 
 	if err := getError(ret); err != nil {
-		return nil, fmt.Errorf("cannot parse expression: %s, error: %s", exprStr, err)
+		return nil, fmt.Errorf("cannot parse expression: %s, error: %w", displayExpr(exprStr), redactError(err))
 	}
 
 	predicate := getExpr(ret)
 	if predicate == nil {
-		return nil, fmt.Errorf("cannot parse expression: %s", exprStr)
+		return nil, fmt.Errorf("cannot parse expression: %s", displayExpr(exprStr))
+	}
+	if err := checkBareBoolColumn(schema, predicate); err != nil {
+		return nil, fmt.Errorf("cannot parse expression: %s, error: %w", displayExpr(exprStr), err)
 	}
 	if !canBeExecuted(predicate) {
-		return nil, fmt.Errorf("predicate is not a boolean expression: %s, data type: %s", exprStr, predicate.dataType)
+		return nil, withErrorCode(ErrCodeNotBoolean, fmt.Errorf("predicate is not a boolean expression: %s, data type: %s", displayExpr(exprStr), predicate.dataType))
 	}
 
 	valueMap, err := UnmarshalExpressionValues(exprTemplateValues)
 	if err != nil {
-		return nil, err
+		return nil, withErrorCode(ErrCodeTemplateValue, err)
 	}
 
 	if err := FillExpressionValue(predicate.expr, valueMap); err != nil {
-		return nil, err
+		return nil, withErrorCode(ErrCodeTemplateValue, err)
 	}
-
 	return predicate.expr, nil
 }
 
-func ParseIdentifier(schema *typeutil.SchemaHelper, identifier string, checkFunc func(*planpb.Expr) error) error {
-	ret := handleExpr(schema, identifier)
+func ParseIdentifier(schema *typeutil.SchemaHelper, identifier string, checkFunc func(*planpb.Expr) error, opts ...ParseOption) error {
+	ret := handleExpr(schema, identifier, opts...)
 
 	if err := getError(ret); err != nil {
-		return fmt.Errorf("cannot parse identifier: %s, error: %s", identifier, err)
+		return fmt.Errorf("cannot parse identifier: %s, error: %w", identifier, err)
 	}
 
 	predicate := getExpr(ret)
@@ -135,11 +265,66 @@ func ParseIdentifier(schema *typeutil.SchemaHelper, identifier string, checkFunc
 	return checkFunc(predicate.expr)
 }
 
-func CreateRetrievePlan(schema *typeutil.SchemaHelper, exprStr string, exprTemplateValues map[string]*schemapb.TemplateValue) (*planpb.PlanNode, error) {
-	expr, err := ParseExpr(schema, exprStr, exprTemplateValues)
+// IdentifierPath is the structured form of an identifier like `meta["group"]` or `tags[0]`.
+type IdentifierPath struct {
+	FieldID     int64
+	FieldName   string
+	DataType    schemapb.DataType
+	ElementType schemapb.DataType
+	// NestedPath is the json keys or the array index accessed under the field.
+	NestedPath []string
+	// IsDynamic is true if the identifier reads a key of the dynamic field.
+	IsDynamic bool
+	// IsArrayElement is true if the identifier reads an element of an array field.
+	IsArrayElement bool
+}
+
+// ParseIdentifierPath validates an identifier, which can be a field, a json path or an array element,
+// and returns the path it accesses.
+func ParseIdentifierPath(schema *typeutil.SchemaHelper, identifier string, opts ...ParseOption) (*IdentifierPath, error) {
+	var info *planpb.ColumnInfo
+	err := ParseIdentifier(schema, identifier, func(expr *planpb.Expr) error {
+		info = expr.GetColumnExpr().GetInfo()
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	field, err := schema.GetFieldFromID(info.GetFieldId())
 	if err != nil {
 		return nil, err
 	}
+	return &IdentifierPath{
+		FieldID:        field.GetFieldID(),
+		FieldName:      field.GetName(),
+		DataType:       field.GetDataType(),
+		ElementType:    field.GetElementType(),
+		NestedPath:     info.GetNestedPath(),
+		IsDynamic:      field.GetIsDynamic(),
+		IsArrayElement: typeutil.IsArrayType(field.GetDataType()) && len(info.GetNestedPath()) != 0,
+	}, nil
+}
+
+func CreateRetrievePlan(schema *typeutil.SchemaHelper, exprStr string, exprTemplateValues map[string]*schemapb.TemplateValue, opts ...ParseOption) (*planpb.PlanNode, error) {
+	expr, err := ParseExpr(schema, exprStr, exprTemplateValues, opts...)
+	if err != nil {
+		return nil, err
+	}
+	hints, _, err := ParseHints(exprStr)
+	if err != nil {
+		return nil, err
+	}
+	for _, hint := range hints {
+		_, parserHint := parserHints[hint.Name]
+		_, budgetHint := budgetHints[hint.Name]
+		if !parserHint && !budgetHint {
+			return nil, fmt.Errorf("hint %s is not supported by query", hint.Name)
+		}
+	}
+	// query plans can't carry the execution budget, which is only validated here.
+	if _, err := ParseExecutionBudget(exprStr, opts...); err != nil {
+		return nil, err
+	}
 
 	planNode := &planpb.PlanNode{
 		Node: &planpb.PlanNode_Query{
@@ -148,15 +333,30 @@ func CreateRetrievePlan(schema *typeutil.SchemaHelper, exprStr string, exprTempl
 			},
 		},
 	}
+	options := newParseOptions(opts...)
+	if err := checkPlanSize(planNode, options.maxPlanSize); err != nil {
+		return nil, LocalizeError(err, options.language)
+	}
 	return planNode, nil
 }
 
-func CreateSearchPlan(schema *typeutil.SchemaHelper, exprStr string, vectorFieldName string, queryInfo *planpb.QueryInfo, exprTemplateValues map[string]*schemapb.TemplateValue) (*planpb.PlanNode, error) {
+// vectorTypes maps the data types of vector fields to the vector types of search plans,
+// a new vector type only needs an entry here.
+var vectorTypes = map[schemapb.DataType]planpb.VectorType{
+	schemapb.DataType_BinaryVector:      planpb.VectorType_BinaryVector,
+	schemapb.DataType_FloatVector:       planpb.VectorType_FloatVector,
+	schemapb.DataType_Float16Vector:     planpb.VectorType_Float16Vector,
+	schemapb.DataType_BFloat16Vector:    planpb.VectorType_BFloat16Vector,
+	schemapb.DataType_SparseFloatVector: planpb.VectorType_SparseFloatVector,
+	schemapb.DataType_Int8Vector:        planpb.VectorType_Int8Vector,
+}
+
+func CreateSearchPlan(schema *typeutil.SchemaHelper, exprStr string, vectorFieldName string, queryInfo *planpb.QueryInfo, exprTemplateValues map[string]*schemapb.TemplateValue, opts ...ParseOption) (*planpb.PlanNode, error) {
 	parse := func() (*planpb.Expr, error) {
-		if len(exprStr) <= 0 {
+		if len(exprStr) <= 0 && !HasRowLevelPolicy(schema, opts...) {
 			return nil, nil
 		}
-		return ParseExpr(schema, exprStr, exprTemplateValues)
+		return ParseExpr(schema, exprStr, exprTemplateValues, opts...)
 	}
 
 	expr, err := parse()
@@ -176,25 +376,53 @@ func CreateSearchPlan(schema *typeutil.SchemaHelper, exprStr string, vectorField
 	fieldID := vectorField.FieldID
 	dataType := vectorField.DataType
 
-	var vectorType planpb.VectorType
 	if !typeutil.IsVectorType(dataType) {
 		return nil, fmt.Errorf("field (%s) to search is not of vector data type", vectorFieldName)
 	}
-	switch dataType {
-	case schemapb.DataType_BinaryVector:
-		vectorType = planpb.VectorType_BinaryVector
-	case schemapb.DataType_FloatVector:
-		vectorType = planpb.VectorType_FloatVector
-	case schemapb.DataType_Float16Vector:
-		vectorType = planpb.VectorType_Float16Vector
-	case schemapb.DataType_BFloat16Vector:
-		vectorType = planpb.VectorType_BFloat16Vector
-	case schemapb.DataType_SparseFloatVector:
-		vectorType = planpb.VectorType_SparseFloatVector
-	case schemapb.DataType_Int8Vector:
-		vectorType = planpb.VectorType_Int8Vector
-	default:
+	vectorType, ok := vectorTypes[dataType]
+	if !ok {
 		log.Error("Invalid dataType", zap.Any("dataType", dataType))
+		return nil, fmt.Errorf("vector type %s of field (%s) is not supported by search plan", dataType, vectorFieldName)
+	}
+	options := newParseOptions(opts...)
+	hints, _, err := ParseHints(exprStr)
+	if err != nil {
+		return nil, err
+	}
+	if len(hints) != 0 {
+		if queryInfo == nil {
+			queryInfo = &planpb.QueryInfo{}
+		}
+		if err := applySearchHints(hints, queryInfo); err != nil {
+			return nil, err
+		}
+	}
+	budget, err := ParseExecutionBudget(exprStr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if budget != nil {
+		if queryInfo == nil {
+			queryInfo = &planpb.QueryInfo{}
+		}
+		if err := applyExecutionBudget(budget, queryInfo); err != nil {
+			return nil, err
+		}
+	}
+	if options.scoreFilter != "" {
+		filter, err := ParseScoreFilter(schema, options.scoreFilter)
+		if err != nil {
+			return nil, err
+		}
+		if queryInfo == nil {
+			queryInfo = &planpb.QueryInfo{}
+		}
+		if err := applyScoreFilter(filter, queryInfo); err != nil {
+			return nil, err
+		}
+	}
+	applyFeedbackHints(schema.GetCollectionID(), expr, queryInfo)
+	if err := validateQueryInfo(schema, vectorField, queryInfo, options); err != nil {
 		return nil, err
 	}
 	planNode := &planpb.PlanNode{
@@ -208,10 +436,58 @@ func CreateSearchPlan(schema *typeutil.SchemaHelper, exprStr string, vectorField
 			},
 		},
 	}
+	if err := checkPlanSize(planNode, options.maxPlanSize); err != nil {
+		return nil, LocalizeError(err, options.language)
+	}
 	return planNode, nil
 }
 
 func CreateRequeryPlan(pkField *schemapb.FieldSchema, ids *schemapb.IDs) *planpb.PlanNode {
+	predicate := newPrimaryKeyTermExpr(pkField, ids)
+	return &planpb.PlanNode{
+		Node: &planpb.PlanNode_Query{
+			Query: &planpb.QueryPlanNode{
+				Predicates: predicate,
+				IsCount:    false,
+				Limit:      int64(len(predicate.GetTermExpr().GetValues())),
+			},
+		},
+	}
+}
+
+// CreateLimitedRequeryPlan creates the requery plan of the first maxIDs ids at most, and returns the remaining ids,
+// nil if there are none, so that large result sets can be requeried by several plans. maxIDs <= 0 means no limit.
+func CreateLimitedRequeryPlan(pkField *schemapb.FieldSchema, ids *schemapb.IDs, maxIDs int64) (*planpb.PlanNode, *schemapb.IDs) {
+	batch, remainder := splitIDs(ids, maxIDs)
+	return CreateRequeryPlan(pkField, batch), remainder
+}
+
+// splitIDs splits the ids after the first n ones, the remainder is nil if there are no more than n ids.
+func splitIDs(ids *schemapb.IDs, n int64) (*schemapb.IDs, *schemapb.IDs) {
+	if n <= 0 {
+		return ids, nil
+	}
+	switch ids.GetIdField().(type) {
+	case *schemapb.IDs_IntId:
+		data := ids.GetIntId().GetData()
+		if int64(len(data)) <= n {
+			return ids, nil
+		}
+		return &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: data[:n]}}},
+			&schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: data[n:]}}}
+	case *schemapb.IDs_StrId:
+		data := ids.GetStrId().GetData()
+		if int64(len(data)) <= n {
+			return ids, nil
+		}
+		return &schemapb.IDs{IdField: &schemapb.IDs_StrId{StrId: &schemapb.StringArray{Data: data[:n]}}},
+			&schemapb.IDs{IdField: &schemapb.IDs_StrId{StrId: &schemapb.StringArray{Data: data[n:]}}}
+	default:
+		return ids, nil
+	}
+}
+
+func newPrimaryKeyTermExpr(pkField *schemapb.FieldSchema, ids *schemapb.IDs) *planpb.Expr {
 	var values []*planpb.GenericValue
 	switch ids.GetIdField().(type) {
 	case *schemapb.IDs_IntId:
@@ -232,25 +508,17 @@ func CreateRequeryPlan(pkField *schemapb.FieldSchema, ids *schemapb.IDs) *planpb
 		})
 	}
 
-	return &planpb.PlanNode{
-		Node: &planpb.PlanNode_Query{
-			Query: &planpb.QueryPlanNode{
-				Predicates: &planpb.Expr{
-					Expr: &planpb.Expr_TermExpr{
-						TermExpr: &planpb.TermExpr{
-							ColumnInfo: &planpb.ColumnInfo{
-								FieldId:        pkField.GetFieldID(),
-								DataType:       pkField.GetDataType(),
-								IsPrimaryKey:   true,
-								IsAutoID:       pkField.GetAutoID(),
-								IsPartitionKey: pkField.GetIsPartitionKey(),
-							},
-							Values: values,
-						},
-					},
+	return &planpb.Expr{
+		Expr: &planpb.Expr_TermExpr{
+			TermExpr: &planpb.TermExpr{
+				ColumnInfo: &planpb.ColumnInfo{
+					FieldId:        pkField.GetFieldID(),
+					DataType:       pkField.GetDataType(),
+					IsPrimaryKey:   true,
+					IsAutoID:       pkField.GetAutoID(),
+					IsPartitionKey: pkField.GetIsPartitionKey(),
 				},
-				IsCount: false,
-				Limit:   int64(len(values)),
+				Values: values,
 			},
 		},
 	}
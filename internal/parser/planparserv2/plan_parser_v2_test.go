@@ -1,6 +1,7 @@
 package planparserv2
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -10,11 +11,13 @@ import (
 	"github.com/antlr4-go/antlr/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
 )
 
@@ -643,6 +646,22 @@ func TestCreateSparseFloatVectorSearchPlan(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCreateInt8VectorSearchPlan(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	plan, err := CreateSearchPlan(schema, `$meta["A"] != 10`, "Int8VectorField", &planpb.QueryInfo{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, planpb.VectorType_Int8Vector, plan.GetVectorAnns().GetVectorType())
+}
+
+func TestCreateSearchPlan_VectorTypes(t *testing.T) {
+	for _, value := range schemapb.DataType_value {
+		dataType := schemapb.DataType(value)
+		if typeutil.IsVectorType(dataType) {
+			assert.Contains(t, vectorTypes, dataType, dataType.String())
+		}
+	}
+}
+
 func TestExpr_Invalid(t *testing.T) {
 	schema := newTestSchema(true)
 	helper, err := typeutil.CreateSchemaHelper(schema)
@@ -1616,8 +1635,10 @@ func Test_convertHanToASCII(t *testing.T) {
 	}
 	testcases := []testcase{
 		{`A in ["中国"]`, `A in ["\u4e2d\u56fd"]`},
-		{`A in ["\中国"]`, `A in ["\中国"]`},
+		{`A in ["\中国"]`, `A in ["\中\u56fd"]`},
 		{`A in ["\\中国"]`, `A in ["\\\u4e2d\u56fd"]`},
+		{`A in ["\x41中", "\u4e2d国"]`, `A in ["\x41\u4e2d", "\u4e2d\u56fd"]`},
+		{`A in ["한국", "😀"]`, `A in ["한국", "😀"]`},
 	}
 
 	for _, c := range testcases {
@@ -1684,3 +1705,123 @@ func TestNestedPathWithChinese(t *testing.T) {
 	assert.Equal(t, "年份", paths[1])
 	assert.Equal(t, "月份", paths[2])
 }
+
+func TestExpr_FieldAlias(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+	aliased, err := helper.WithFieldAliases(map[string]string{"age": "Int64Field", "meta": "JSONField"})
+	require.NoError(t, err)
+
+	pairs := [][2]string{
+		{`age > 1`, `Int64Field > 1`},
+		{`meta["a"] == "b" and age in [1, 2]`, `JSONField["a"] == "b" and Int64Field in [1, 2]`},
+		{`age < Int32Field`, `Int64Field < Int32Field`},
+	}
+	for _, pair := range pairs {
+		expr, err := ParseExpr(aliased, pair[0], nil)
+		require.NoError(t, err, pair[0])
+		expected, err := ParseExpr(aliased, pair[1], nil)
+		require.NoError(t, err, pair[1])
+		assert.True(t, proto.Equal(expected, expr), pair[0])
+	}
+}
+
+func TestParseIdentifierPath(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	path, err := ParseIdentifierPath(schema, `JSONField["group"]["name"]`)
+	require.NoError(t, err)
+	assert.Equal(t, "JSONField", path.FieldName)
+	assert.Equal(t, schemapb.DataType_JSON, path.DataType)
+	assert.Equal(t, []string{"group", "name"}, path.NestedPath)
+	assert.False(t, path.IsDynamic)
+
+	path, err = ParseIdentifierPath(schema, `ArrayField[0]`)
+	require.NoError(t, err)
+	assert.Equal(t, schemapb.DataType_Int64, path.ElementType)
+	assert.Equal(t, []string{"0"}, path.NestedPath)
+	assert.True(t, path.IsArrayElement)
+
+	path, err = ParseIdentifierPath(schema, `group`)
+	require.NoError(t, err)
+	assert.Equal(t, common.MetaFieldName, path.FieldName)
+	assert.Equal(t, []string{"group"}, path.NestedPath)
+	assert.True(t, path.IsDynamic)
+
+	path, err = ParseIdentifierPath(schema, `Int64Field`)
+	require.NoError(t, err)
+	assert.Empty(t, path.NestedPath)
+	assert.False(t, path.IsArrayElement)
+
+	for _, identifier := range []string{`ArrayField["a"]`, `Int64Field[0]`, `Int64Field + 1`, `JSONField["a"] > 1`} {
+		_, err = ParseIdentifierPath(schema, identifier)
+		assert.Error(t, err, identifier)
+	}
+	_, err = ParseIdentifierPath(schema, `group`, WithImplicitDynamicField(false))
+	assert.Error(t, err)
+}
+
+func TestParseExpr_CacheRefresh(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	for _, refresh := range []struct {
+		prefix string
+		opts   []ParseOption
+	}{
+		{prefix: "", opts: []ParseOption{WithCacheRefresh()}},
+		{prefix: "/*+ refresh_cache */ ", opts: nil},
+	} {
+		exprStr := `Int64Field > 1 and Int32Field < 100`
+		exprCache.Add(exprStr, fmt.Errorf("stale cache entry"))
+		_, err := ParseExpr(schema, exprStr, nil)
+		assert.ErrorContains(t, err, "stale cache entry")
+
+		_, err = ParseExpr(schema, refresh.prefix+exprStr, nil, refresh.opts...)
+		assert.NoError(t, err)
+		// the cache entry is overwritten.
+		_, err = ParseExpr(schema, exprStr, nil)
+		assert.NoError(t, err)
+	}
+
+	plan, err := CreateSearchPlan(schema, `/*+ refresh_cache, iterative_filter */ Int64Field > 1`, "FloatVectorField", &planpb.QueryInfo{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "iterative_filter", plan.GetVectorAnns().GetQueryInfo().GetHints())
+	_, err = CreateRetrievePlan(schema, `/*+ refresh_cache */ Int64Field > 1`, nil)
+	assert.NoError(t, err)
+}
+
+func TestParseExpr_RecoverPanic(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	SetFieldAuthorizer(func(ctx context.Context, collectionID int64, fieldID int64) error {
+		panic("unexpected")
+	})
+	defer SetFieldAuthorizer(nil)
+
+	_, err := ParseExpr(schema, `Int64Field > 1`, nil)
+	assert.ErrorIs(t, err, merr.ErrServiceInternal)
+	assert.ErrorContains(t, err, "panic while parsing expression: unexpected")
+	_, err = ValidateExpr(schema, `Int64Field > 1`)
+	assert.ErrorIs(t, err, merr.ErrServiceInternal)
+}
+
+func TestCreateLimitedRequeryPlan(t *testing.T) {
+	pkField := &schemapb.FieldSchema{FieldID: 100, Name: "id", IsPrimaryKey: true, DataType: schemapb.DataType_Int64}
+	ids := &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2, 3, 4, 5}}}}
+
+	plan, remainder := CreateLimitedRequeryPlan(pkField, ids, 2)
+	assert.Equal(t, int64(2), plan.GetQuery().GetLimit())
+	assert.Len(t, plan.GetQuery().GetPredicates().GetTermExpr().GetValues(), 2)
+	assert.Equal(t, []int64{3, 4, 5}, remainder.GetIntId().GetData())
+
+	plan, remainder = CreateLimitedRequeryPlan(pkField, remainder, 3)
+	assert.Equal(t, int64(3), plan.GetQuery().GetLimit())
+	assert.Nil(t, remainder)
+
+	plan, remainder = CreateLimitedRequeryPlan(pkField, ids, 0)
+	assert.Equal(t, int64(5), plan.GetQuery().GetLimit())
+	assert.Nil(t, remainder)
+
+	strField := &schemapb.FieldSchema{FieldID: 100, Name: "id", IsPrimaryKey: true, DataType: schemapb.DataType_VarChar}
+	strIDs := &schemapb.IDs{IdField: &schemapb.IDs_StrId{StrId: &schemapb.StringArray{Data: []string{"a", "b", "c"}}}}
+	plan, remainder = CreateLimitedRequeryPlan(strField, strIDs, 2)
+	assert.Equal(t, "b", plan.GetQuery().GetPredicates().GetTermExpr().GetValues()[1].GetStringVal())
+	assert.Equal(t, []string{"c"}, remainder.GetStrId().GetData())
+}
@@ -0,0 +1,66 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// WithMaxPlanSize rejects plans larger than maxBytes once serialized, before they are sent to query nodes.
+// Non-positive values leave the size unlimited.
+func WithMaxPlanSize(maxBytes int64) ParseOption {
+	return func(options *parseOptions) {
+		options.maxPlanSize = maxBytes
+	}
+}
+
+// checkPlanSize returns an error naming the largest value list of the plan if the plan exceeds the size limit.
+func checkPlanSize(plan *planpb.PlanNode, maxBytes int64) error {
+	if maxBytes <= 0 {
+		return nil
+	}
+	size := int64(proto.Size(plan))
+	if size <= maxBytes {
+		return nil
+	}
+	predicates := plan.GetVectorAnns().GetPredicates()
+	if predicates == nil {
+		predicates = plan.GetQuery().GetPredicates()
+	}
+	if n := largestValueList(predicates); n > 0 {
+		return merr.WrapErrParameterTooLarge("plan",
+			fmt.Sprintf("IN list of %d values produces a %s plan, limit is %s", n, formatBytes(size), formatBytes(maxBytes)))
+	}
+	return merr.WrapErrParameterTooLarge("plan", fmt.Sprintf("plan of %s exceeds the limit %s", formatBytes(size), formatBytes(maxBytes)))
+}
+
+// largestValueList returns the number of values of the largest term list or json_contains list.
+func largestValueList(expr *planpb.Expr) int {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryExpr:
+		return largestValueList(e.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		return max(largestValueList(e.BinaryExpr.GetLeft()), largestValueList(e.BinaryExpr.GetRight()))
+	case *planpb.Expr_RandomSampleExpr:
+		return largestValueList(e.RandomSampleExpr.GetPredicate())
+	case *planpb.Expr_TermExpr:
+		return len(e.TermExpr.GetValues())
+	case *planpb.Expr_JsonContainsExpr:
+		return len(e.JsonContainsExpr.GetElements())
+	}
+	return 0
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
@@ -0,0 +1,42 @@
+package planparserv2
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+func TestPlanSize(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	ids := make([]string, 0, 10000)
+	for i := 0; i < 10000; i++ {
+		ids = append(ids, fmt.Sprint(i*1000003))
+	}
+	exprStr := "Int8Field > 1 and not (Int64Field in [" + strings.Join(ids, ",") + "])"
+
+	plan, err := CreateRetrievePlan(schema, exprStr, nil, WithMaxPlanSize(1<<20))
+	require.NoError(t, err)
+	assert.NotNil(t, plan)
+	_, err = CreateRetrievePlan(schema, exprStr, nil, WithMaxPlanSize(0))
+	assert.NoError(t, err)
+
+	_, err = CreateRetrievePlan(schema, exprStr, nil, WithMaxPlanSize(16<<10))
+	assert.ErrorIs(t, err, merr.ErrParameterTooLarge)
+	assert.ErrorContains(t, err, "IN list of 10000 values produces a")
+	assert.ErrorContains(t, err, "plan, limit is 16.0KB")
+
+	_, err = CreateSearchPlan(schema, exprStr, "FloatVectorField", &planpb.QueryInfo{Topk: 10}, nil, WithMaxPlanSize(16<<10))
+	assert.ErrorContains(t, err, "IN list of 10000 values")
+
+	_, err = CreateSearchPlan(schema, "", "FloatVectorField", &planpb.QueryInfo{Topk: 10, SearchParams: strings.Repeat(" ", 100)}, nil, WithMaxPlanSize(64))
+	assert.ErrorContains(t, err, "exceeds the limit 64B")
+
+	assert.Equal(t, "1.5MB", formatBytes(3<<19))
+	assert.Equal(t, "2.0KB", formatBytes(2048))
+}
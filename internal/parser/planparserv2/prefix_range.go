@@ -0,0 +1,91 @@
+package planparserv2
+
+import (
+	"strings"
+
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// WithPrefixRangeScan rewrites `field like "abc%"` into the bounded range `"abc" <= field < "abd"` on the string
+// fields with a sorted index, Trie or STL_SORT among indexInfos, so that segments scan the index range instead of
+// matching every key.
+func WithPrefixRangeScan(indexInfos []*indexpb.IndexInfo) ParseOption {
+	return func(options *parseOptions) {
+		options.prefixRangeFields = make(map[int64]struct{})
+		for _, info := range indexInfos {
+			if isSortedIndexType(funcutil.KeyValuePair2Map(info.GetIndexParams())[common.IndexTypeKey]) {
+				options.prefixRangeFields[info.GetFieldID()] = struct{}{}
+			}
+		}
+	}
+}
+
+func isSortedIndexType(indexType string) bool {
+	return indexType == IndexTypeSTLSort || strings.EqualFold(indexType, IndexTypeTrie)
+}
+
+// rewritePrefixRanges replaces the prefix matches on the fields by ranges.
+func rewritePrefixRanges(expr *planpb.Expr, fields map[int64]struct{}) *planpb.Expr {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryExpr:
+		e.UnaryExpr.Child = rewritePrefixRanges(e.UnaryExpr.GetChild(), fields)
+	case *planpb.Expr_BinaryExpr:
+		e.BinaryExpr.Left = rewritePrefixRanges(e.BinaryExpr.GetLeft(), fields)
+		e.BinaryExpr.Right = rewritePrefixRanges(e.BinaryExpr.GetRight(), fields)
+	case *planpb.Expr_RandomSampleExpr:
+		if e.RandomSampleExpr.GetPredicate() != nil {
+			e.RandomSampleExpr.Predicate = rewritePrefixRanges(e.RandomSampleExpr.GetPredicate(), fields)
+		}
+	case *planpb.Expr_UnaryRangeExpr:
+		info := e.UnaryRangeExpr.GetColumnInfo()
+		if _, ok := fields[info.GetFieldId()]; !ok || e.UnaryRangeExpr.GetOp() != planpb.OpType_PrefixMatch ||
+			len(info.GetNestedPath()) != 0 || !typeutil.IsStringType(info.GetDataType()) {
+			return expr
+		}
+		prefix := e.UnaryRangeExpr.GetValue().GetStringVal()
+		if prefix == "" {
+			return expr
+		}
+		upper, ok := prefixUpperBound(prefix)
+		if !ok {
+			return &planpb.Expr{
+				Expr: &planpb.Expr_UnaryRangeExpr{
+					UnaryRangeExpr: &planpb.UnaryRangeExpr{
+						ColumnInfo: info,
+						Op:         planpb.OpType_GreaterEqual,
+						Value:      NewString(prefix),
+					},
+				},
+			}
+		}
+		return &planpb.Expr{
+			Expr: &planpb.Expr_BinaryRangeExpr{
+				BinaryRangeExpr: &planpb.BinaryRangeExpr{
+					ColumnInfo:     info,
+					LowerInclusive: true,
+					UpperInclusive: false,
+					LowerValue:     NewString(prefix),
+					UpperValue:     NewString(upper),
+				},
+			},
+		}
+	}
+	return expr
+}
+
+// prefixUpperBound returns the smallest string greater than all the strings starting with the prefix, in byte
+// order, and false if there is none because the prefix is made of 0xff bytes.
+func prefixUpperBound(prefix string) (string, bool) {
+	upper := []byte(prefix)
+	for i := len(upper) - 1; i >= 0; i-- {
+		if upper[i] != 0xff {
+			upper[i]++
+			return string(upper[:i+1]), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,54 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+)
+
+func TestPrefixUpperBound(t *testing.T) {
+	upper, ok := prefixUpperBound("abc")
+	assert.True(t, ok)
+	assert.Equal(t, "abd", upper)
+
+	upper, ok = prefixUpperBound("ab\xff\xff")
+	assert.True(t, ok)
+	assert.Equal(t, "ac", upper)
+
+	_, ok = prefixUpperBound("\xff")
+	assert.False(t, ok)
+}
+
+func TestPrefixRangeScan(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+	field, err := helper.GetFieldFromName("VarCharField")
+	require.NoError(t, err)
+	indexInfos := []*indexpb.IndexInfo{{
+		FieldID:     field.GetFieldID(),
+		IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: "TRIE"}},
+	}}
+
+	expr, err := ParseExpr(helper, `VarCharField like "abc%" and VarCharField like "%abc"`, nil, WithPrefixRangeScan(indexInfos))
+	require.NoError(t, err)
+	rangeExpr := expr.GetBinaryExpr().GetLeft().GetBinaryRangeExpr()
+	require.NotNil(t, rangeExpr)
+	assert.Equal(t, "abc", rangeExpr.GetLowerValue().GetStringVal())
+	assert.Equal(t, "abd", rangeExpr.GetUpperValue().GetStringVal())
+	assert.True(t, rangeExpr.GetLowerInclusive())
+	assert.False(t, rangeExpr.GetUpperInclusive())
+	assert.Equal(t, "%abc", expr.GetBinaryExpr().GetRight().GetUnaryRangeExpr().GetValue().GetStringVal())
+
+	// the fields without a sorted index keep the prefix match.
+	expr, err = ParseExpr(helper, `VarCharField like "abc%"`, nil, WithPrefixRangeScan(nil))
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+
+	expr, err = ParseExpr(helper, `JSONField["a"] like "abc%"`, nil, WithPrefixRangeScan(indexInfos))
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+}
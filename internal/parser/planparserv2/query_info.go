@@ -0,0 +1,169 @@
+package planparserv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// vectorMetricTypes are the metric types supported by each vector type.
+var vectorMetricTypes = map[schemapb.DataType][]metric.MetricType{
+	schemapb.DataType_FloatVector:       {metric.L2, metric.IP, metric.COSINE},
+	schemapb.DataType_Float16Vector:     {metric.L2, metric.IP, metric.COSINE},
+	schemapb.DataType_BFloat16Vector:    {metric.L2, metric.IP, metric.COSINE},
+	schemapb.DataType_Int8Vector:        {metric.L2, metric.IP, metric.COSINE},
+	schemapb.DataType_BinaryVector:      {metric.HAMMING, metric.JACCARD, metric.SUBSTRUCTURE, metric.SUPERSTRUCTURE},
+	schemapb.DataType_SparseFloatVector: {metric.IP, metric.BM25},
+}
+
+// metricScoreRange is the range of the scores of a metric type, or of the distances for the metrics which
+// aren't positively related.
+type metricScoreRange struct {
+	min, max float64
+}
+
+// metricScoreRanges are the ranges of the scores of the metric types, IP is unbounded.
+var metricScoreRanges = map[metric.MetricType]metricScoreRange{
+	metric.L2:      {0, math.Inf(1)},
+	metric.COSINE:  {-1, 1},
+	metric.HAMMING: {0, math.Inf(1)},
+	metric.JACCARD: {0, 1},
+	metric.BM25:    {0, math.Inf(1)},
+}
+
+// WithTopKLimit rejects search plans whose topk, which includes the offset, is not in (0, limit].
+func WithTopKLimit(limit int64) ParseOption {
+	return func(options *parseOptions) {
+		options.topKLimit = limit
+	}
+}
+
+// validateQueryInfo checks the query info of a search on the vector field before the plan is sent to query nodes.
+// The topk is checked if WithTopKLimit is given, and the metric type and group by field are checked if they're set.
+func validateQueryInfo(schema *typeutil.SchemaHelper, vectorField *schemapb.FieldSchema, queryInfo *planpb.QueryInfo, options *parseOptions) error {
+	if queryInfo == nil {
+		return nil
+	}
+	if options.topKLimit > 0 && (queryInfo.GetTopk() <= 0 || queryInfo.GetTopk() > options.topKLimit) {
+		return merr.WrapErrParameterInvalidRange(int64(1), options.topKLimit, queryInfo.GetTopk(), "topk+offset is out of range")
+	}
+	if queryInfo.GetTopk() < 0 {
+		return merr.WrapErrParameterInvalidMsg("topk %d is negative", queryInfo.GetTopk())
+	}
+	if err := validateMetricType(vectorField, queryInfo.GetMetricType()); err != nil {
+		return err
+	}
+	if err := validateRangeSearch(queryInfo); err != nil {
+		return err
+	}
+	return validateGroupBy(schema, queryInfo)
+}
+
+func validateMetricType(vectorField *schemapb.FieldSchema, metricType string) error {
+	if metricType == "" {
+		return nil
+	}
+	supported := false
+	for _, m := range vectorMetricTypes[vectorField.GetDataType()] {
+		if strings.EqualFold(metricType, m) {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return merr.WrapErrParameterInvalidMsg("metric type %s is not supported by field %s of type %s",
+			metricType, vectorField.GetName(), vectorField.GetDataType())
+	}
+	for _, param := range vectorField.GetIndexParams() {
+		if param.GetKey() == common.MetricTypeKey && !strings.EqualFold(param.GetValue(), metricType) {
+			return merr.WrapErrParameterInvalidMsg("metric type %s doesn't match the metric type %s of the index on field %s",
+				metricType, param.GetValue(), vectorField.GetName())
+		}
+	}
+	return nil
+}
+
+// validateRangeSearch checks that the radius and range_filter of range searches, which score filters are
+// translated into, are in the range of the scores of the metric type. Searches with a radius at the best end of
+// the range, like a radius of 1 with COSINE, can't return anything.
+func validateRangeSearch(queryInfo *planpb.QueryInfo) error {
+	scoreRange, ok := metricScoreRanges[strings.ToUpper(queryInfo.GetMetricType())]
+	if !ok || queryInfo.GetSearchParams() == "" {
+		return nil
+	}
+	params := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(queryInfo.GetSearchParams()), &params); err != nil {
+		return merr.WrapErrParameterInvalidMsg("invalid search params: %s", err.Error())
+	}
+	for _, key := range []string{radiusKey, rangeFilterKey} {
+		value, ok := params[key].(float64)
+		if !ok {
+			continue
+		}
+		if value < scoreRange.min || value > scoreRange.max {
+			return merr.WrapErrParameterInvalidRange(scoreRange.min, scoreRange.max, value,
+				fmt.Sprintf("%s is out of the range of the scores of metric %s", key, queryInfo.GetMetricType()))
+		}
+	}
+	if radius, ok := params[radiusKey].(float64); ok {
+		if metric.PositivelyRelated(queryInfo.GetMetricType()) && radius == scoreRange.max {
+			return merr.WrapErrParameterInvalidMsg("%s %v of metric %s excludes all the scores, which must be greater than it",
+				radiusKey, radius, queryInfo.GetMetricType())
+		}
+		if !metric.PositivelyRelated(queryInfo.GetMetricType()) && radius == scoreRange.min {
+			return merr.WrapErrParameterInvalidMsg("%s %v of metric %s excludes all the distances, which must be less than it",
+				radiusKey, radius, queryInfo.GetMetricType())
+		}
+	}
+	return nil
+}
+
+func validateGroupBy(schema *typeutil.SchemaHelper, queryInfo *planpb.QueryInfo) error {
+	if queryInfo.GetGroupByFieldId() <= 0 {
+		return nil
+	}
+	field, err := schema.GetFieldFromID(queryInfo.GetGroupByFieldId())
+	if err != nil {
+		return merr.WrapErrFieldNotFound(queryInfo.GetGroupByFieldId(), "group by field not found in schema")
+	}
+	if err := checkGroupByType(field); err != nil {
+		return err
+	}
+	if field.GetNullable() {
+		return merr.WrapErrParameterInvalidMsg("group by field %s is nullable", field.GetName())
+	}
+	if queryInfo.GetGroupSize() < 0 {
+		return merr.WrapErrParameterInvalidMsg("group size %d is negative", queryInfo.GetGroupSize())
+	}
+	if queryInfo.GetSearchIteratorV2Info() != nil {
+		return merr.WrapErrParameterInvalidMsg("group by is not supported by search iterators")
+	}
+	if queryInfo.GetSearchParams() != "" {
+		params := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(queryInfo.GetSearchParams()), &params); err != nil {
+			return merr.WrapErrParameterInvalidMsg("invalid search params: %s", err.Error())
+		}
+		if _, ok := params[radiusKey]; ok {
+			return merr.WrapErrParameterInvalidMsg("group by is not supported by range search")
+		}
+	}
+	return nil
+}
+
+func checkGroupByType(field *schemapb.FieldSchema) error {
+	switch field.GetDataType() {
+	case schemapb.DataType_Bool, schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32,
+		schemapb.DataType_Int64, schemapb.DataType_VarChar, schemapb.DataType_String:
+		return nil
+	default:
+		return merr.WrapErrParameterInvalidMsg("group by field %s of type %s is not supported", field.GetName(), field.GetDataType())
+	}
+}
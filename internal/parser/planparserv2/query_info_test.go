@@ -0,0 +1,110 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestCreateSearchPlan_ValidateQueryInfo(t *testing.T) {
+	schema := newTestSchema(true)
+	for _, field := range schema.Fields {
+		switch field.GetName() {
+		case "FloatVectorField":
+			field.IndexParams = append(field.IndexParams, &commonpb.KeyValuePair{Key: common.MetricTypeKey, Value: "COSINE"})
+		case "Int32Field":
+			field.Nullable = true
+		}
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+	limit := WithTopKLimit(100)
+
+	for _, c := range []struct {
+		field     string
+		queryInfo *planpb.QueryInfo
+	}{
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 100, MetricType: "cosine"}},
+		{"Float16VectorField", &planpb.QueryInfo{Topk: 10, MetricType: "L2"}},
+		{"BinaryVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "JACCARD"}},
+		{"SparseFloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "BM25"}},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, GroupByFieldId: 105, GroupSize: 3}},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, GroupByFieldId: 121, SearchParams: `{"nprobe":10}`}},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, GroupByFieldId: -1, SearchParams: `{"radius":1}`}},
+	} {
+		_, err := CreateSearchPlan(helper, ``, c.field, c.queryInfo, nil, limit)
+		assert.NoError(t, err, c.queryInfo.String())
+	}
+
+	for _, c := range []struct {
+		field     string
+		queryInfo *planpb.QueryInfo
+	}{
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 101}},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 0}},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "L2"}},
+		{"Float16VectorField", &planpb.QueryInfo{Topk: 10, MetricType: "HAMMING"}},
+		{"BinaryVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "IP"}},
+		{"SparseFloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "L2"}},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, GroupByFieldId: 110}},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, GroupByFieldId: 104}},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, GroupByFieldId: 105, GroupSize: -1}},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, GroupByFieldId: 105, SearchParams: `{"radius":1}`}},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, GroupByFieldId: 105, SearchIteratorV2Info: &planpb.SearchIteratorV2Info{}}},
+	} {
+		_, err := CreateSearchPlan(helper, ``, c.field, c.queryInfo, nil, limit)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid, c.queryInfo.String())
+	}
+
+	_, err = CreateSearchPlan(helper, ``, "FloatVectorField", &planpb.QueryInfo{Topk: 10, GroupByFieldId: 999}, nil, limit)
+	assert.ErrorIs(t, err, merr.ErrFieldNotFound)
+
+	// topk is not bounded without the limit.
+	_, err = CreateSearchPlan(helper, ``, "FloatVectorField", &planpb.QueryInfo{Topk: 1000}, nil)
+	assert.NoError(t, err)
+	_, err = CreateSearchPlan(helper, ``, "FloatVectorField", &planpb.QueryInfo{Topk: -1}, nil)
+	assert.Error(t, err)
+}
+
+func TestCreateSearchPlan_ValidateRangeSearch(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	for _, c := range []struct {
+		field     string
+		queryInfo *planpb.QueryInfo
+		opts      []ParseOption
+	}{
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "COSINE", SearchParams: `{"radius":0.5,"range_filter":1}`}, nil},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "IP", SearchParams: `{"radius":-100,"range_filter":100}`}, nil},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "L2", SearchParams: `{"radius":10,"range_filter":0}`}, nil},
+		{"BinaryVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "JACCARD", SearchParams: `{"radius":0.9}`}, nil},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "COSINE"}, []ParseOption{WithScoreFilter(`similarity > 0.8`)}},
+	} {
+		_, err := CreateSearchPlan(helper, ``, c.field, c.queryInfo, nil, c.opts...)
+		assert.NoError(t, err, c.queryInfo.String())
+	}
+
+	for _, c := range []struct {
+		field     string
+		queryInfo *planpb.QueryInfo
+		opts      []ParseOption
+	}{
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "COSINE", SearchParams: `{"radius":1.5}`}, nil},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "COSINE", SearchParams: `{"radius":1}`}, nil},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "COSINE", SearchParams: `{"radius":0.5,"range_filter":2}`}, nil},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "L2", SearchParams: `{"radius":-1}`}, nil},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "L2", SearchParams: `{"radius":0}`}, nil},
+		{"BinaryVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "JACCARD", SearchParams: `{"radius":2}`}, nil},
+		{"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "COSINE"}, []ParseOption{WithScoreFilter(`similarity > 1.2`)}},
+	} {
+		_, err := CreateSearchPlan(helper, ``, c.field, c.queryInfo, nil, c.opts...)
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid, c.queryInfo.String())
+	}
+}
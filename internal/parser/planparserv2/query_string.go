@@ -0,0 +1,391 @@
+package planparserv2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	parser "github.com/milvus-io/milvus/internal/parser/planparserv2/generated"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// queryStringFunctionName is the function accepting a lucene-style query string on text fields:
+//
+//	query_string(title, 'body:(foo OR bar) AND -baz AND "hello world"~2')
+//
+// Terms without a field prefix are matched against the first argument. The query string is
+// lowered into text_match/phrase_match predicates combined with the logical operators.
+const queryStringFunctionName = "query_string"
+
+type qsOccur int
+
+const (
+	qsShould qsOccur = iota
+	qsMust
+	qsMustNot
+)
+
+// qsNode is either a term, a phrase or a group of clauses.
+type qsNode struct {
+	field   string
+	text    string
+	phrase  bool
+	slop    int64
+	clauses []*qsClause
+}
+
+type qsClause struct {
+	occur qsOccur
+	node  *qsNode
+}
+
+type qsTokenType int
+
+const (
+	qsTokenEOF qsTokenType = iota
+	qsTokenWord
+	qsTokenField
+	qsTokenPhrase
+	qsTokenLParen
+	qsTokenRParen
+	qsTokenAnd
+	qsTokenOr
+	qsTokenNot
+	qsTokenPlus
+	qsTokenMinus
+)
+
+type qsToken struct {
+	typ  qsTokenType
+	text string
+	slop int64
+}
+
+func (v *ParserVisitor) visitQueryString(ctx *parser.CallContext) interface{} {
+	if len(ctx.AllExpr()) != 2 {
+		return fmt.Errorf("%s requires a text field and a query string, got: %s", queryStringFunctionName, ctx.GetText())
+	}
+	if _, ok := ctx.Expr(0).(*parser.IdentifierContext); !ok {
+		return fmt.Errorf("the first argument of %s must be a field, got: %s", queryStringFunctionName, ctx.Expr(0).GetText())
+	}
+	query := ctx.Expr(1).Accept(v)
+	if err := getError(query); err != nil {
+		return err
+	}
+	queryValue := getGenericValue(query)
+	if !IsString(queryValue) {
+		return fmt.Errorf("the second argument of %s must be a string literal, got: %s", queryStringFunctionName, ctx.Expr(1).GetText())
+	}
+
+	root, err := parseQueryString(queryValue.GetStringVal())
+	if err != nil {
+		return err
+	}
+	expr, err := v.lowerQueryString(ctx.Expr(0).GetText(), root)
+	if err != nil {
+		return err
+	}
+	return expr
+}
+
+func tokenizeQueryString(query string) ([]qsToken, error) {
+	var tokens []qsToken
+	runes := []rune(query)
+	n := len(runes)
+	for i := 0; i < n; {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, qsToken{typ: qsTokenLParen})
+			i++
+		case r == ')':
+			tokens = append(tokens, qsToken{typ: qsTokenRParen})
+			i++
+		case r == '+':
+			tokens = append(tokens, qsToken{typ: qsTokenPlus})
+			i++
+		case r == '-':
+			tokens = append(tokens, qsToken{typ: qsTokenMinus})
+			i++
+		case r == '!':
+			tokens = append(tokens, qsToken{typ: qsTokenNot})
+			i++
+		case r == '&' && i+1 < n && runes[i+1] == '&':
+			tokens = append(tokens, qsToken{typ: qsTokenAnd})
+			i += 2
+		case r == '|' && i+1 < n && runes[i+1] == '|':
+			tokens = append(tokens, qsToken{typ: qsTokenOr})
+			i += 2
+		case r == '"':
+			var b strings.Builder
+			i++
+			closed := false
+			for i < n {
+				if runes[i] == '\\' && i+1 < n {
+					b.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated phrase in query string: %s", query)
+			}
+			token := qsToken{typ: qsTokenPhrase, text: b.String()}
+			if i < n && runes[i] == '~' {
+				j := i + 1
+				for j < n && unicode.IsDigit(runes[j]) {
+					j++
+				}
+				slop, err := strconv.ParseInt(string(runes[i+1:j]), 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid phrase slop in query string: %s", query)
+				}
+				token.slop = slop
+				i = j
+			}
+			tokens = append(tokens, token)
+		default:
+			var b strings.Builder
+			for i < n && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()":`, runes[i]) {
+				if runes[i] == '\\' && i+1 < n {
+					b.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			word := b.String()
+			if i < n && runes[i] == ':' {
+				if word == "" {
+					return nil, fmt.Errorf("missing field name in query string: %s", query)
+				}
+				tokens = append(tokens, qsToken{typ: qsTokenField, text: word})
+				i++
+				continue
+			}
+			switch word {
+			case "AND":
+				tokens = append(tokens, qsToken{typ: qsTokenAnd})
+			case "OR":
+				tokens = append(tokens, qsToken{typ: qsTokenOr})
+			case "NOT":
+				tokens = append(tokens, qsToken{typ: qsTokenNot})
+			case "":
+				return nil, fmt.Errorf("unexpected character %q in query string: %s", runes[i], query)
+			default:
+				tokens = append(tokens, qsToken{typ: qsTokenWord, text: word})
+			}
+		}
+	}
+	return append(tokens, qsToken{typ: qsTokenEOF}), nil
+}
+
+type qsParser struct {
+	tokens []qsToken
+	pos    int
+	query  string
+}
+
+func parseQueryString(query string) (*qsNode, error) {
+	tokens, err := tokenizeQueryString(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &qsParser{tokens: tokens, query: query}
+	root, err := p.parseGroup()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().typ != qsTokenEOF {
+		return nil, fmt.Errorf("unbalanced parentheses in query string: %s", query)
+	}
+	return root, nil
+}
+
+func (p *qsParser) peek() qsToken {
+	return p.tokens[p.pos]
+}
+
+func (p *qsParser) next() qsToken {
+	token := p.tokens[p.pos]
+	if token.typ != qsTokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+// parseGroup follows the lucene classic query parser: clauses are optional unless
+// marked by `+`/`AND`, and prohibited when marked by `-`/`NOT`.
+func (p *qsParser) parseGroup() (*qsNode, error) {
+	group := &qsNode{}
+	for {
+		token := p.peek()
+		if token.typ == qsTokenEOF || token.typ == qsTokenRParen {
+			break
+		}
+
+		conjunction := qsTokenEOF
+		if token.typ == qsTokenAnd || token.typ == qsTokenOr {
+			if len(group.clauses) == 0 {
+				return nil, fmt.Errorf("query string cannot start with a conjunction: %s", p.query)
+			}
+			conjunction = p.next().typ
+		}
+
+		occur := qsShould
+		switch p.peek().typ {
+		case qsTokenPlus:
+			p.next()
+			occur = qsMust
+		case qsTokenMinus, qsTokenNot:
+			p.next()
+			occur = qsMustNot
+		}
+
+		if conjunction == qsTokenAnd {
+			if last := group.clauses[len(group.clauses)-1]; last.occur == qsShould {
+				last.occur = qsMust
+			}
+			if occur == qsShould {
+				occur = qsMust
+			}
+		}
+
+		node, err := p.parseClause()
+		if err != nil {
+			return nil, err
+		}
+		group.clauses = append(group.clauses, &qsClause{occur: occur, node: node})
+	}
+	if len(group.clauses) == 0 {
+		return nil, fmt.Errorf("empty query string or group: %s", p.query)
+	}
+	return group, nil
+}
+
+func (p *qsParser) parseClause() (*qsNode, error) {
+	field := ""
+	if p.peek().typ == qsTokenField {
+		field = p.next().text
+	}
+	token := p.next()
+	switch token.typ {
+	case qsTokenWord:
+		return &qsNode{field: field, text: token.text}, nil
+	case qsTokenPhrase:
+		return &qsNode{field: field, text: token.text, phrase: true, slop: token.slop}, nil
+	case qsTokenLParen:
+		group, err := p.parseGroup()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().typ != qsTokenRParen {
+			return nil, fmt.Errorf("unbalanced parentheses in query string: %s", p.query)
+		}
+		group.field = field
+		return group, nil
+	default:
+		return nil, fmt.Errorf("unexpected token in query string: %s", p.query)
+	}
+}
+
+func (v *ParserVisitor) lowerQueryString(defaultField string, node *qsNode) (*ExprWithType, error) {
+	field := defaultField
+	if node.field != "" {
+		field = node.field
+	}
+	if node.clauses == nil {
+		if node.phrase {
+			return v.translatePhraseMatch(field, node.text, node.slop)
+		}
+		return v.translateTextMatch(field, node.text)
+	}
+
+	var musts, mustNots, shoulds []*planpb.Expr
+	// text_match already has the OR semantic between tokens, optional terms on the same field are merged.
+	var shouldTerms []string
+	shouldTermFields := make(map[string][]string)
+	for _, clause := range node.clauses {
+		child := clause.node
+		if clause.occur == qsShould && child.clauses == nil && !child.phrase {
+			childField := field
+			if child.field != "" {
+				childField = child.field
+			}
+			if _, ok := shouldTermFields[childField]; !ok {
+				shouldTerms = append(shouldTerms, childField)
+			}
+			shouldTermFields[childField] = append(shouldTermFields[childField], child.text)
+			continue
+		}
+		expr, err := v.lowerQueryString(field, child)
+		if err != nil {
+			return nil, err
+		}
+		switch clause.occur {
+		case qsMust:
+			musts = append(musts, expr.expr)
+		case qsMustNot:
+			mustNots = append(mustNots, expr.expr)
+		default:
+			shoulds = append(shoulds, expr.expr)
+		}
+	}
+	for _, childField := range shouldTerms {
+		expr, err := v.translateTextMatch(childField, strings.Join(shouldTermFields[childField], " "))
+		if err != nil {
+			return nil, err
+		}
+		shoulds = append(shoulds, expr.expr)
+	}
+
+	// optional clauses don't filter anything once there are required ones.
+	var conjuncts []*planpb.Expr
+	conjuncts = append(conjuncts, musts...)
+	if len(musts) == 0 && len(shoulds) > 0 {
+		conjuncts = append(conjuncts, combineExprs(shoulds, planpb.BinaryExpr_LogicalOr))
+	}
+	for _, expr := range mustNots {
+		conjuncts = append(conjuncts, &planpb.Expr{
+			Expr: &planpb.Expr_UnaryExpr{
+				UnaryExpr: &planpb.UnaryExpr{
+					Op:    planpb.UnaryExpr_Not,
+					Child: expr,
+				},
+			},
+		})
+	}
+	return &ExprWithType{
+		expr:     combineExprs(conjuncts, planpb.BinaryExpr_LogicalAnd),
+		dataType: schemapb.DataType_Bool,
+	}, nil
+}
+
+// combineExprs folds the non-empty expressions with the logical operator from left to right.
+func combineExprs(exprs []*planpb.Expr, op planpb.BinaryExpr_BinaryOp) *planpb.Expr {
+	ret := exprs[0]
+	for _, expr := range exprs[1:] {
+		ret = &planpb.Expr{
+			Expr: &planpb.Expr_BinaryExpr{
+				BinaryExpr: &planpb.BinaryExpr{
+					Left:  ret,
+					Right: expr,
+					Op:    op,
+				},
+			},
+		}
+	}
+	return ret
+}
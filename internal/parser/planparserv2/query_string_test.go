@@ -0,0 +1,61 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestExpr_QueryString(t *testing.T) {
+	schema := newTestSchema(true)
+	enableMatch(schema)
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+
+	equivalents := []struct {
+		query string
+		expr  string
+	}{
+		{`query_string(VarCharField, "foo")`, `text_match(VarCharField, "foo")`},
+		{`query_string(VarCharField, "foo bar OR baz")`, `text_match(VarCharField, "foo bar baz")`},
+		{`query_string(VarCharField, "foo AND bar")`, `text_match(VarCharField, "foo") and text_match(VarCharField, "bar")`},
+		{`query_string(VarCharField, "foo && -bar")`, `text_match(VarCharField, "foo") and not text_match(VarCharField, "bar")`},
+		{`query_string(VarCharField, "+foo bar")`, `text_match(VarCharField, "foo")`},
+		{`query_string(VarCharField, "NOT foo")`, `not text_match(VarCharField, "foo")`},
+		{`query_string(VarCharField, "\"hello world\"~2")`, `phrase_match(VarCharField, "hello world", 2)`},
+		{`query_string(VarCharField, "StringField:(foo OR bar) AND -baz")`, `text_match(StringField, "foo bar") and not text_match(VarCharField, "baz")`},
+		{`query_string(VarCharField, "foo StringField:bar")`, `text_match(VarCharField, "foo") or text_match(StringField, "bar")`},
+		{`query_string(VarCharField, "(a AND b) c")`, `(text_match(VarCharField, "a") and text_match(VarCharField, "b")) or text_match(VarCharField, "c")`},
+		{`query_string(VarCharField, "a\\:b")`, `text_match(VarCharField, "a:b")`},
+	}
+	for _, c := range equivalents {
+		expr, err := ParseExpr(helper, c.query, nil)
+		require.NoError(t, err, c.query)
+		expected, err := ParseExpr(helper, c.expr, nil)
+		require.NoError(t, err, c.expr)
+		assert.True(t, proto.Equal(expected, expr), c.query)
+	}
+
+	invalid := []string{
+		`query_string(VarCharField)`,
+		`query_string(VarCharField, 1)`,
+		`query_string("foo", "foo")`,
+		`query_string(BoolField, "foo")`,
+		`query_string(not_exist, "foo")`,
+		`query_string(VarCharField, "BoolField:foo")`,
+		`query_string(VarCharField, "")`,
+		`query_string(VarCharField, "AND foo")`,
+		`query_string(VarCharField, "(foo")`,
+		`query_string(VarCharField, "foo)")`,
+		`query_string(VarCharField, "\"foo")`,
+		`query_string(VarCharField, "\"foo\"~x")`,
+		`query_string(VarCharField, ":foo")`,
+	}
+	for _, exprStr := range invalid {
+		assertInvalidExpr(t, helper, exprStr)
+	}
+}
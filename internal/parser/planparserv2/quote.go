@@ -0,0 +1,30 @@
+package planparserv2
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/antlr4-go/antlr/v4"
+
+	parser "github.com/milvus-io/milvus/internal/parser/planparserv2/generated"
+)
+
+// QuoteStringLiteral quotes and escapes an arbitrary string, so it's parsed back as exactly the same
+// string literal when included in an expression.
+func QuoteStringLiteral(s string) string {
+	return strconv.Quote(s)
+}
+
+// QuoteIdentifier checks that a field name can be included in an expression as an identifier, and returns it.
+// The grammar has no quoted identifiers, so names which are keywords or contain other characters are rejected.
+func QuoteIdentifier(name string) (string, error) {
+	listener := &errorListenerImpl{}
+	lexer := getLexer(antlr.NewInputStream(convertUnicode(name)), listener)
+	defer putLexer(lexer)
+	tokens := lexer.GetAllTokens()
+	if listener.Error() != nil || len(tokens) != 1 ||
+		(tokens[0].GetTokenType() != parser.PlanLexerIdentifier && tokens[0].GetTokenType() != parser.PlanLexerMeta) {
+		return "", fmt.Errorf("%s cannot be used as an identifier in expressions", strconv.Quote(name))
+	}
+	return name, nil
+}
@@ -0,0 +1,36 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteStringLiteral(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	for _, s := range []string{
+		``, `abc`, `a"b`, `a'b`, `a\b`, `a\"b`, `" or Int64Field > 0 or "`, "line\nbreak\ttab\r", "\a\b\v\x00",
+		"\xff\xfe", `中文`, `%_`, `A`,
+	} {
+		expr, err := ParseExpr(schema, `VarCharField == `+QuoteStringLiteral(s), nil)
+		require.NoError(t, err, s)
+		assert.Equal(t, s, expr.GetUnaryRangeExpr().GetValue().GetStringVal(), s)
+
+		expr, err = ParseExpr(schema, `VarCharField in [`+QuoteStringLiteral(s)+`, "x"]`, nil, WithKeepTermValues(true))
+		require.NoError(t, err, s)
+		assert.Equal(t, s, expr.GetTermExpr().GetValues()[0].GetStringVal(), s)
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	for _, name := range []string{`Int64Field`, `_a1`, `$meta`} {
+		quoted, err := QuoteIdentifier(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, name, quoted)
+	}
+	for _, name := range []string{``, `1a`, `a b`, `a-b`, `and`, `not`, `in`, `true`, `like`, `a["b"]`, `a or b`, `"a"`} {
+		_, err := QuoteIdentifier(name)
+		assert.Error(t, err, name)
+	}
+}
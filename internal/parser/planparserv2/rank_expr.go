@@ -0,0 +1,357 @@
+package planparserv2
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	parser "github.com/milvus-io/milvus/internal/parser/planparserv2/generated"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// rankSimilarity is the variable bound to the score returned by search in rank expressions.
+const rankSimilarity = "similarity"
+
+// RankExpr is a ranking expression which combines the score of search results with numeric scalar fields,
+// such as `0.7 * similarity + 0.3 * log(popularity)`. Results are ordered by the value of the expression,
+// the larger the better.
+type RankExpr struct {
+	exprStr string
+	root    rankNode
+	// FieldIDs are the distinct fields read by the expression, which must be fetched with the results.
+	FieldIDs []int64
+	// Decays are the decay functions of the expression, in order of appearance.
+	Decays []*DecayFunction
+}
+
+// DecayFunction is a decay function of a rank expression, such as `gauss_decay(ts, 1700000000, 86400)`.
+// It scores 1 when the field equals Origin, and 0.5 when the field is Scale away from Origin.
+type DecayFunction struct {
+	Function string
+	FieldID  int64
+	Origin   float64
+	Scale    float64
+}
+
+// RankedSearchPlan is a search plan with the rank expression applied to its results.
+type RankedSearchPlan struct {
+	Plan *planpb.PlanNode
+	Rank *RankExpr
+}
+
+type rankEnv struct {
+	similarity float64
+	values     map[int64]float64
+}
+
+type rankNode interface {
+	eval(env *rankEnv) (float64, error)
+}
+
+type rankConst float64
+
+func (n rankConst) eval(*rankEnv) (float64, error) { return float64(n), nil }
+
+type rankVar struct{}
+
+func (rankVar) eval(env *rankEnv) (float64, error) { return env.similarity, nil }
+
+type rankField struct {
+	fieldID int64
+	name    string
+}
+
+func (n *rankField) eval(env *rankEnv) (float64, error) {
+	value, ok := env.values[n.fieldID]
+	if !ok {
+		return 0, fmt.Errorf("value of field %s is missing", n.name)
+	}
+	return value, nil
+}
+
+type rankBinary struct {
+	op          int
+	left, right rankNode
+}
+
+func (n *rankBinary) eval(env *rankEnv) (float64, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case parser.PlanParserADD:
+		return left + right, nil
+	case parser.PlanParserSUB:
+		return left - right, nil
+	case parser.PlanParserMUL:
+		return left * right, nil
+	case parser.PlanParserDIV:
+		return left / right, nil
+	case parser.PlanParserMOD:
+		return math.Mod(left, right), nil
+	default:
+		return math.Pow(left, right), nil
+	}
+}
+
+type rankCall struct {
+	fn   *rankFunction
+	args []rankNode
+}
+
+func (n *rankCall) eval(env *rankEnv) (float64, error) {
+	args := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(env)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = value
+	}
+	return n.fn.call(args), nil
+}
+
+type rankDecay struct {
+	decay *DecayFunction
+	field *rankField
+	call  func(distance, scale float64) float64
+}
+
+func (n *rankDecay) eval(env *rankEnv) (float64, error) {
+	value, err := n.field.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	return n.call(math.Abs(value-n.decay.Origin), n.decay.Scale), nil
+}
+
+// decayFunctions decay to 0.5 at the distance of scale from origin.
+var decayFunctions = map[string]func(distance, scale float64) float64{
+	"gauss_decay": func(distance, scale float64) float64 {
+		return math.Exp(math.Log(0.5) * distance * distance / (scale * scale))
+	},
+	"linear_decay": func(distance, scale float64) float64 {
+		return math.Max(0, 1-0.5*distance/scale)
+	},
+	"exp_decay": func(distance, scale float64) float64 {
+		return math.Exp(math.Log(0.5) * distance / scale)
+	},
+}
+
+type rankFunction struct {
+	// numArgs is the number of arguments, -1 for one or more.
+	numArgs int
+	call    func(args []float64) float64
+}
+
+var rankFunctions = map[string]*rankFunction{
+	"log":   {numArgs: 1, call: func(args []float64) float64 { return math.Log(args[0]) }},
+	"log1p": {numArgs: 1, call: func(args []float64) float64 { return math.Log1p(args[0]) }},
+	"exp":   {numArgs: 1, call: func(args []float64) float64 { return math.Exp(args[0]) }},
+	"sqrt":  {numArgs: 1, call: func(args []float64) float64 { return math.Sqrt(args[0]) }},
+	"abs":   {numArgs: 1, call: func(args []float64) float64 { return math.Abs(args[0]) }},
+	"min": {numArgs: -1, call: func(args []float64) float64 {
+		ret := args[0]
+		for _, arg := range args[1:] {
+			ret = math.Min(ret, arg)
+		}
+		return ret
+	}},
+	"max": {numArgs: -1, call: func(args []float64) float64 {
+		ret := args[0]
+		for _, arg := range args[1:] {
+			ret = math.Max(ret, arg)
+		}
+		return ret
+	}},
+}
+
+// ParseRankExpr parses a rank expression. It's made of numbers, the `similarity` score, numeric scalar fields,
+// arithmetic operators and the functions log, log1p, exp, sqrt, abs, min and max.
+// The decay functions gauss_decay, linear_decay and exp_decay take a numeric field, a constant origin and
+// a constant positive scale, e.g. `similarity * gauss_decay(publish_time, 1700000000, 86400)`.
+func ParseRankExpr(schema *typeutil.SchemaHelper, exprStr string) (*RankExpr, error) {
+	ast, err := handleInternal(exprStr, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse rank expression: %s, error: %w", displayExpr(exprStr), redactError(err))
+	}
+	builder := &rankBuilder{schema: schema, fields: make(map[int64]struct{})}
+	root, err := builder.build(ast)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse rank expression: %s, error: %w", displayExpr(exprStr), redactError(err))
+	}
+	return &RankExpr{exprStr: exprStr, root: root, FieldIDs: builder.fieldIDs, Decays: builder.decays}, nil
+}
+
+// Eval evaluates the expression for a search result of the score, with the values of the fields it reads.
+func (e *RankExpr) Eval(similarity float64, values map[int64]float64) (float64, error) {
+	return e.root.eval(&rankEnv{similarity: similarity, values: values})
+}
+
+func (e *RankExpr) String() string {
+	return e.exprStr
+}
+
+// CreateRankedSearchPlan creates a search plan like CreateSearchPlan, along with the rank expression ordering its results.
+// The plan itself still returns the results ordered by score, the rank expression is applied on reduce.
+func CreateRankedSearchPlan(schema *typeutil.SchemaHelper, exprStr string, vectorFieldName string, queryInfo *planpb.QueryInfo,
+	exprTemplateValues map[string]*schemapb.TemplateValue, rankExprStr string, opts ...ParseOption,
+) (*RankedSearchPlan, error) {
+	rank, err := ParseRankExpr(schema, rankExprStr)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := CreateSearchPlan(schema, exprStr, vectorFieldName, queryInfo, exprTemplateValues, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &RankedSearchPlan{Plan: plan, Rank: rank}, nil
+}
+
+type rankBuilder struct {
+	schema   *typeutil.SchemaHelper
+	fields   map[int64]struct{}
+	fieldIDs []int64
+	decays   []*DecayFunction
+}
+
+func (b *rankBuilder) build(tree parser.IExprContext) (rankNode, error) {
+	switch ctx := tree.(type) {
+	case *parser.ParensContext:
+		return b.build(ctx.Expr())
+	case *parser.IntegerContext:
+		i, err := strconv.ParseInt(ctx.IntegerConstant().GetText(), 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		return rankConst(i), nil
+	case *parser.FloatingContext:
+		f, err := strconv.ParseFloat(ctx.FloatingConstant().GetText(), 64)
+		if err != nil {
+			return nil, err
+		}
+		return rankConst(f), nil
+	case *parser.IdentifierContext:
+		return b.buildIdentifier(ctx.GetText())
+	case *parser.UnaryContext:
+		child, err := b.build(ctx.Expr())
+		if err != nil {
+			return nil, err
+		}
+		switch ctx.GetOp().GetTokenType() {
+		case parser.PlanParserADD:
+			return child, nil
+		case parser.PlanParserSUB:
+			if c, ok := child.(rankConst); ok {
+				return -c, nil
+			}
+			return &rankBinary{op: parser.PlanParserSUB, left: rankConst(0), right: child}, nil
+		default:
+			return nil, fmt.Errorf("operator %s is not supported in rank expressions", ctx.GetOp().GetText())
+		}
+	case *parser.AddSubContext:
+		return b.buildBinary(ctx.GetOp().GetTokenType(), ctx.Expr(0), ctx.Expr(1))
+	case *parser.MulDivModContext:
+		return b.buildBinary(ctx.GetOp().GetTokenType(), ctx.Expr(0), ctx.Expr(1))
+	case *parser.PowerContext:
+		return b.buildBinary(parser.PlanParserPOW, ctx.Expr(0), ctx.Expr(1))
+	case *parser.CallContext:
+		return b.buildCall(ctx)
+	default:
+		return nil, fmt.Errorf("%s is not supported in rank expressions", tree.GetText())
+	}
+}
+
+func (b *rankBuilder) buildBinary(op int, left, right parser.IExprContext) (rankNode, error) {
+	l, err := b.build(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := b.build(right)
+	if err != nil {
+		return nil, err
+	}
+	return &rankBinary{op: op, left: l, right: r}, nil
+}
+
+func (b *rankBuilder) buildIdentifier(name string) (rankNode, error) {
+	if name == rankSimilarity {
+		return rankVar{}, nil
+	}
+	field, err := b.schema.GetFieldFromName(decodeUnicode(name))
+	if err != nil {
+		return nil, err
+	}
+	if !typeutil.IsIntegerType(field.GetDataType()) && !typeutil.IsFloatingType(field.GetDataType()) {
+		return nil, fmt.Errorf("field %s of type %s cannot be used in rank expressions, only numeric fields are supported",
+			field.GetName(), field.GetDataType())
+	}
+	if _, ok := b.fields[field.GetFieldID()]; !ok {
+		b.fields[field.GetFieldID()] = struct{}{}
+		b.fieldIDs = append(b.fieldIDs, field.GetFieldID())
+	}
+	return &rankField{fieldID: field.GetFieldID(), name: field.GetName()}, nil
+}
+
+func (b *rankBuilder) buildCall(ctx *parser.CallContext) (rankNode, error) {
+	name := strings.ToLower(ctx.Identifier().GetText())
+	if decay, ok := decayFunctions[name]; ok {
+		return b.buildDecay(name, decay, ctx.AllExpr())
+	}
+	fn, ok := rankFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("function %s is not supported in rank expressions", name)
+	}
+	params := ctx.AllExpr()
+	if (fn.numArgs < 0 && len(params) == 0) || (fn.numArgs >= 0 && len(params) != fn.numArgs) {
+		return nil, fmt.Errorf("invalid number of arguments for function %s: %d", name, len(params))
+	}
+	args := make([]rankNode, 0, len(params))
+	for _, param := range params {
+		arg, err := b.build(param)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return &rankCall{fn: fn, args: args}, nil
+}
+
+func (b *rankBuilder) buildDecay(name string, call func(distance, scale float64) float64, params []parser.IExprContext) (rankNode, error) {
+	if len(params) != 3 {
+		return nil, fmt.Errorf("invalid number of arguments for function %s: %d", name, len(params))
+	}
+	field, err := b.build(params[0])
+	if err != nil {
+		return nil, err
+	}
+	f, ok := field.(*rankField)
+	if !ok {
+		return nil, fmt.Errorf("the first argument of function %s must be a field", name)
+	}
+	constants := make([]float64, 2)
+	for i, param := range params[1:] {
+		node, err := b.build(param)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := node.(rankConst); !ok {
+			return nil, fmt.Errorf("the origin and scale of function %s must be constants", name)
+		}
+		constants[i], _ = node.eval(&rankEnv{})
+	}
+	if constants[1] <= 0 {
+		return nil, fmt.Errorf("the scale of function %s must be positive", name)
+	}
+	decay := &DecayFunction{Function: name, FieldID: f.fieldID, Origin: constants[0], Scale: constants[1]}
+	b.decays = append(b.decays, decay)
+	return &rankDecay{decay: decay, field: f, call: call}, nil
+}
@@ -0,0 +1,120 @@
+package planparserv2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestParseRankExpr(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	int64Field, err := schema.GetFieldFromName("Int64Field")
+	require.NoError(t, err)
+	doubleField, err := schema.GetFieldFromName("DoubleField")
+	require.NoError(t, err)
+
+	rank, err := ParseRankExpr(schema, `0.7 * similarity + 0.3 * log(Int64Field) - DoubleField / 2 + Int64Field`)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{int64Field.GetFieldID(), doubleField.GetFieldID()}, rank.FieldIDs)
+	score, err := rank.Eval(0.5, map[int64]float64{int64Field.GetFieldID(): math.E, doubleField.GetFieldID(): 1})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.35+0.3-0.5+math.E, score, 1e-9)
+	_, err = rank.Eval(0.5, map[int64]float64{int64Field.GetFieldID(): 1})
+	assert.ErrorContains(t, err, "value of field DoubleField is missing")
+
+	cases := map[string]float64{
+		`-similarity`:                     -2,
+		`similarity ** 2 % 3`:             1,
+		`max(1, similarity, 0.5)`:         2,
+		`min(similarity, 1.5)`:            1.5,
+		`abs(-similarity) + sqrt(4)`:      4,
+		`exp(0) + log1p(0) + (1 + 2) * 3`: 10,
+	}
+	for exprStr, expected := range cases {
+		rank, err := ParseRankExpr(schema, exprStr)
+		require.NoError(t, err, exprStr)
+		assert.Empty(t, rank.FieldIDs)
+		score, err := rank.Eval(2, nil)
+		require.NoError(t, err, exprStr)
+		assert.InDelta(t, expected, score, 1e-9, exprStr)
+	}
+
+	for _, exprStr := range []string{
+		`VarCharField + 1`,
+		`not_exist * 2`,
+		`JSONField["a"] * 2`,
+		`similarity > 1`,
+		`foo(similarity)`,
+		`log(1, 2)`,
+		`max()`,
+		`~similarity`,
+		`"a"`,
+		`similarity +`,
+	} {
+		_, err := ParseRankExpr(schema, exprStr)
+		assert.Error(t, err, exprStr)
+	}
+}
+
+func TestCreateRankedSearchPlan(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	plan, err := CreateRankedSearchPlan(schema, `Int64Field > 1`, "FloatVectorField", &planpb.QueryInfo{}, nil, `similarity * Int64Field`)
+	require.NoError(t, err)
+	assert.NotNil(t, plan.Plan.GetVectorAnns().GetPredicates())
+	assert.Equal(t, `similarity * Int64Field`, plan.Rank.String())
+
+	_, err = CreateRankedSearchPlan(schema, `Int64Field > 1`, "FloatVectorField", &planpb.QueryInfo{}, nil, `similarity * VarCharField`)
+	assert.Error(t, err)
+	_, err = CreateRankedSearchPlan(schema, `Int64Field >`, "FloatVectorField", &planpb.QueryInfo{}, nil, `similarity`)
+	assert.Error(t, err)
+}
+
+func TestParseRankExpr_Decay(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	int64Field, err := schema.GetFieldFromName("Int64Field")
+	require.NoError(t, err)
+
+	rank, err := ParseRankExpr(schema, `similarity * gauss_decay(Int64Field, 100, 10) + linear_decay(Int64Field, -100, 10) + exp_decay(Int64Field, 100, 10)`)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{int64Field.GetFieldID()}, rank.FieldIDs)
+	assert.Equal(t, []*DecayFunction{
+		{Function: "gauss_decay", FieldID: int64Field.GetFieldID(), Origin: 100, Scale: 10},
+		{Function: "linear_decay", FieldID: int64Field.GetFieldID(), Origin: -100, Scale: 10},
+		{Function: "exp_decay", FieldID: int64Field.GetFieldID(), Origin: 100, Scale: 10},
+	}, rank.Decays)
+
+	for _, c := range []struct {
+		exprStr  string
+		value    float64
+		expected float64
+	}{
+		{`gauss_decay(Int64Field, 100, 10)`, 100, 1},
+		{`gauss_decay(Int64Field, 100, 10)`, 90, 0.5},
+		{`gauss_decay(Int64Field, 100, 10)`, 120, 0.0625},
+		{`linear_decay(Int64Field, 100, 10)`, 110, 0.5},
+		{`linear_decay(Int64Field, 100, 10)`, 200, 0},
+		{`exp_decay(Int64Field, 100, 10)`, 80, 0.25},
+	} {
+		rank, err := ParseRankExpr(schema, c.exprStr)
+		require.NoError(t, err, c.exprStr)
+		score, err := rank.Eval(1, map[int64]float64{int64Field.GetFieldID(): c.value})
+		require.NoError(t, err, c.exprStr)
+		assert.InDelta(t, c.expected, score, 1e-9, c.exprStr)
+	}
+
+	for _, exprStr := range []string{
+		`gauss_decay(Int64Field, 100)`,
+		`gauss_decay(similarity, 100, 10)`,
+		`gauss_decay(Int64Field, Int64Field, 10)`,
+		`gauss_decay(Int64Field, 100, 0)`,
+		`linear_decay(Int64Field, 100, -1)`,
+		`exp_decay(VarCharField, 100, 10)`,
+	} {
+		_, err := ParseRankExpr(schema, exprStr)
+		assert.Error(t, err, exprStr)
+	}
+}
@@ -0,0 +1,55 @@
+package planparserv2
+
+import (
+	"regexp"
+	"sync/atomic"
+)
+
+var (
+	stringLiteralPattern  = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+	numericLiteralPattern = regexp.MustCompile(`\b(?:0[xX][0-9a-fA-F]+|\d+(?:\.\d*)?(?:[eE][+-]?\d+)?)\b`)
+)
+
+var literalRedaction atomic.Bool
+
+// SetLiteralRedaction enables or disables masking the literals of expressions in the logs and errors of the parser.
+func SetLiteralRedaction(enabled bool) {
+	literalRedaction.Store(enabled)
+}
+
+// redactLiterals masks the string and numeric literals of an expression, which may carry personal data,
+// so that the expression can be logged.
+func redactLiterals(exprStr string) string {
+	exprStr = stringLiteralPattern.ReplaceAllString(exprStr, `"***"`)
+	return numericLiteralPattern.ReplaceAllString(exprStr, "?")
+}
+
+// displayExpr returns the expression to be put in logs and errors, with its literals masked if redaction is enabled.
+func displayExpr(exprStr string) string {
+	if literalRedaction.Load() {
+		return redactLiterals(exprStr)
+	}
+	return exprStr
+}
+
+// redactedError masks the literals in the message of an error while keeping it unwrappable.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError masks the literals in the message of err if redaction is enabled.
+func redactError(err error) error {
+	if err == nil || !literalRedaction.Load() {
+		return err
+	}
+	return &redactedError{msg: redactLiterals(err.Error()), err: err}
+}
@@ -0,0 +1,51 @@
+package planparserv2
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactLiterals(t *testing.T) {
+	for exprStr, expected := range map[string]string{
+		`Int64Field > 100`: `Int64Field > ?`,
+		`email == "a@b.com" and name in ['x', "y\"z"]`: `email == "***" and name in ["***", "***"]`,
+		`1.5e3 < DoubleField < 0x1F`:                   `? < DoubleField < ?`,
+		`$meta["tag"] == "v" and Int8Field + 2 == 3`:   `$meta["***"] == "***" and Int8Field + ? == ?`,
+	} {
+		assert.Equal(t, expected, redactLiterals(exprStr), exprStr)
+	}
+}
+
+func TestLiteralRedaction(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	exprStr := `VarCharField == "alice@example.com" and Int64Field > 42 and`
+
+	_, err := ParseExpr(schema, exprStr, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "alice@example.com")
+
+	SetLiteralRedaction(true)
+	defer SetLiteralRedaction(false)
+	_, err = ParseExpr(schema, exprStr, nil)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "alice@example.com")
+	assert.NotContains(t, err.Error(), "42")
+	assert.Contains(t, err.Error(), `VarCharField == "***" and Int64Field > ?`)
+
+	_, err = ParseExpr(schema, `Int64Field + "secret"`, nil)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+
+	_, err = ParseRankExpr(schema, `log(VarCharField == "secret")`)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+
+	cause := errors.New(`invalid value "secret"`)
+	err = redactError(cause)
+	assert.Equal(t, `invalid value "***"`, err.Error())
+	assert.ErrorIs(t, err, cause)
+	assert.Nil(t, redactError(nil))
+}
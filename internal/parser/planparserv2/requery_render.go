@@ -0,0 +1,38 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// requeryRenderedValues is the max number of primary keys RenderRequeryPlan renders, more are summarized by their count.
+const requeryRenderedValues = 16
+
+// RenderRequeryPlan renders the filter of a plan created by CreateRequeryPlan as an expression, for logs and debug
+// endpoints. Large requeries are summarized like `id in [<15000 values>]`.
+func RenderRequeryPlan(schema *typeutil.SchemaHelper, plan *planpb.PlanNode) string {
+	expr := plan.GetQuery().GetPredicates()
+	if expr == nil {
+		return ""
+	}
+	if term := expr.GetTermExpr(); term != nil && len(term.GetValues()) > requeryRenderedValues {
+		field, err := exportASTField(schema, term.GetColumnInfo())
+		if err == nil {
+			if name, err := field.render(); err == nil {
+				return fmt.Sprintf("%s in [<%d values>]", name, len(term.GetValues()))
+			}
+		}
+		return fmt.Sprintf("field %d in [<%d values>]", term.GetColumnInfo().GetFieldId(), len(term.GetValues()))
+	}
+	ast, err := ExportAST(schema, expr)
+	if err != nil {
+		return fmt.Sprintf("<cannot render requery: %s>", err)
+	}
+	rendered, err := ASTToExprString(ast)
+	if err != nil {
+		return fmt.Sprintf("<cannot render requery: %s>", err)
+	}
+	return displayExpr(rendered)
+}
@@ -0,0 +1,43 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestRenderRequeryPlan(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	pkField, err := schema.GetFieldFromName("Int64Field")
+	require.NoError(t, err)
+
+	plan := CreateRequeryPlan(pkField, &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2, 3}}}})
+	rendered := RenderRequeryPlan(schema, plan)
+	assert.Equal(t, "Int64Field in [1, 2, 3]", rendered)
+	_, err = ParseExpr(schema, rendered, nil)
+	assert.NoError(t, err)
+
+	ids := make([]int64, 15000)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	plan = CreateRequeryPlan(pkField, &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}})
+	assert.Equal(t, "Int64Field in [<15000 values>]", RenderRequeryPlan(schema, plan))
+
+	SetLiteralRedaction(true)
+	defer SetLiteralRedaction(false)
+	plan = CreateRequeryPlan(pkField, &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{42}}}})
+	assert.Equal(t, "Int64Field in [?]", RenderRequeryPlan(schema, plan))
+
+	assert.Equal(t, "", RenderRequeryPlan(schema, &planpb.PlanNode{}))
+
+	unknown := &schemapb.FieldSchema{FieldID: 999, Name: "missing", DataType: schemapb.DataType_Int64}
+	plan = CreateRequeryPlan(unknown, &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: ids}}})
+	assert.Equal(t, "field 999 in [<15000 values>]", RenderRequeryPlan(schema, plan))
+	plan = CreateRequeryPlan(unknown, &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1}}}})
+	assert.Contains(t, RenderRequeryPlan(schema, plan), "cannot render requery")
+}
@@ -0,0 +1,51 @@
+package planparserv2
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// RequestContext holds the values of the caller substituted for the context functions of expressions,
+// such as `owner == current_user()`.
+type RequestContext struct {
+	User     string
+	Database string
+	// Time is returned by request_time() in unix milliseconds.
+	Time time.Time
+}
+
+// contextFunctions are the functions substituted by values of the RequestContext at plan-build time.
+var contextFunctions = map[string]func(rc *RequestContext) (*planpb.GenericValue, bool){
+	"current_user": func(rc *RequestContext) (*planpb.GenericValue, bool) {
+		return NewString(rc.User), rc.User != ""
+	},
+	"current_db": func(rc *RequestContext) (*planpb.GenericValue, bool) {
+		return NewString(rc.Database), rc.Database != ""
+	},
+	"request_time": func(rc *RequestContext) (*planpb.GenericValue, bool) {
+		return NewInt(rc.Time.UnixMilli()), !rc.Time.IsZero()
+	},
+}
+
+// WithRequestContext sets the values substituted for current_user(), current_db() and request_time().
+func WithRequestContext(rc RequestContext) ParseOption {
+	return func(options *parseOptions) {
+		options.requestContext = &rc
+	}
+}
+
+func (v *ParserVisitor) translateContextFunction(functionName string, numParams int) (*ExprWithType, error) {
+	if numParams != 0 {
+		return nil, fmt.Errorf("function %s() doesn't take arguments", functionName)
+	}
+	if v.options.requestContext == nil {
+		return nil, fmt.Errorf("function %s() is not available without the context of the request", functionName)
+	}
+	value, ok := contextFunctions[functionName](v.options.requestContext)
+	if !ok {
+		return nil, fmt.Errorf("function %s() is not available, the request doesn't carry it", functionName)
+	}
+	return toValueExpr(value), nil
+}
@@ -0,0 +1,44 @@
+package planparserv2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestRequestContextFunctions(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	now := time.UnixMilli(1700000000123)
+	rc := WithRequestContext(RequestContext{User: "alice", Database: "default", Time: now})
+
+	expr, err := ParseExpr(schema, `VarCharField == current_user()`, nil, rc)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", expr.GetUnaryRangeExpr().GetValue().GetStringVal())
+
+	expr, err = ParseExpr(schema, `$meta["db"] in [current_db(), "shared"]`, nil, rc)
+	require.NoError(t, err)
+	assert.Equal(t, "default", expr.GetTermExpr().GetValues()[0].GetStringVal())
+
+	expr, err = ParseExpr(schema, `Int64Field > request_time() - 3600000`, nil, rc)
+	require.NoError(t, err)
+	assert.Equal(t, planpb.OpType_GreaterThan, expr.GetUnaryRangeExpr().GetOp())
+	assert.Equal(t, now.UnixMilli()-3600000, expr.GetUnaryRangeExpr().GetValue().GetInt64Val())
+
+	for exprStr, msg := range map[string]string{
+		`VarCharField == current_user(1)`: "doesn't take arguments",
+		`VarCharField == CURRENT_DB()`:    "not available without the context of the request",
+	} {
+		_, err := ParseExpr(schema, exprStr, nil)
+		assert.ErrorContains(t, err, msg, exprStr)
+	}
+
+	// the user is unknown if authorization is disabled.
+	_, err = ParseExpr(schema, `VarCharField == current_user()`, nil, WithRequestContext(RequestContext{Database: "default"}))
+	assert.ErrorContains(t, err, "the request doesn't carry it")
+	_, err = ParseExpr(schema, `Int64Field < request_time()`, nil, WithRequestContext(RequestContext{User: "alice"}))
+	assert.ErrorContains(t, err, "the request doesn't carry it")
+}
@@ -0,0 +1,80 @@
+package planparserv2
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// RerankPlan is a search plan which fetches more candidates than requested, reorders them by the
+// rerank expression evaluated over their scores and output fields, and then truncates them to Limit.
+type RerankPlan struct {
+	Plan *planpb.PlanNode
+	Expr *RankExpr
+	// Limit is the number of results left after reranking.
+	Limit int64
+}
+
+// RerankCandidate is a search result to be reranked, with the values of the fields read by the rerank expression.
+type RerankCandidate struct {
+	Offset int
+	Score  float64
+	Values map[int64]float64
+}
+
+// CreateRerankPlan creates a search plan like CreateSearchPlan, which searches topK candidates for the
+// rerank expression and keeps queryInfo.Topk of them. The fields read by the rerank expression are
+// added to the output fields of the plan.
+func CreateRerankPlan(schema *typeutil.SchemaHelper, exprStr string, vectorFieldName string, queryInfo *planpb.QueryInfo,
+	exprTemplateValues map[string]*schemapb.TemplateValue, rerankExprStr string, topK int64, opts ...ParseOption,
+) (*RerankPlan, error) {
+	if queryInfo == nil || queryInfo.GetTopk() <= 0 {
+		return nil, fmt.Errorf("topk of rerank plan must be positive")
+	}
+	if topK < queryInfo.GetTopk() {
+		return nil, fmt.Errorf("number of rerank candidates %d is less than topk %d", topK, queryInfo.GetTopk())
+	}
+	rerank, err := ParseRankExpr(schema, rerankExprStr)
+	if err != nil {
+		return nil, err
+	}
+	limit := queryInfo.GetTopk()
+	candidatesInfo := typeutil.Clone(queryInfo)
+	candidatesInfo.Topk = topK
+	plan, err := CreateSearchPlan(schema, exprStr, vectorFieldName, candidatesInfo, exprTemplateValues, opts...)
+	if err != nil {
+		return nil, err
+	}
+	outputFields := typeutil.NewSet(plan.OutputFieldIds...)
+	for _, fieldID := range rerank.FieldIDs {
+		if !outputFields.Contain(fieldID) {
+			plan.OutputFieldIds = append(plan.OutputFieldIds, fieldID)
+		}
+	}
+	return &RerankPlan{Plan: plan, Expr: rerank, Limit: limit}, nil
+}
+
+// Rerank orders the candidates by the rerank expression, the larger the better, and truncates them to Limit.
+// The order of candidates with equal ranks is kept.
+func (p *RerankPlan) Rerank(candidates []*RerankCandidate) ([]*RerankCandidate, error) {
+	ranks := make(map[*RerankCandidate]float64, len(candidates))
+	for _, candidate := range candidates {
+		rank, err := p.Expr.Eval(candidate.Score, candidate.Values)
+		if err != nil {
+			return nil, err
+		}
+		ranks[candidate] = rank
+	}
+	ret := make([]*RerankCandidate, len(candidates))
+	copy(ret, candidates)
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ranks[ret[i]] > ranks[ret[j]]
+	})
+	if int64(len(ret)) > p.Limit {
+		ret = ret[:p.Limit]
+	}
+	return ret, nil
+}
@@ -0,0 +1,54 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestCreateRerankPlan(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	queryInfo := &planpb.QueryInfo{Topk: 2, MetricType: "L2"}
+	plan, err := CreateRerankPlan(schema, `Int64Field > 0`, "FloatVectorField", queryInfo, nil, `similarity + Int64Field + DoubleField`, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), plan.Limit)
+	assert.Equal(t, int64(10), plan.Plan.GetVectorAnns().GetQueryInfo().GetTopk())
+	assert.Equal(t, "L2", plan.Plan.GetVectorAnns().GetQueryInfo().GetMetricType())
+	assert.Equal(t, int64(2), queryInfo.GetTopk())
+	assert.Equal(t, plan.Expr.FieldIDs, plan.Plan.GetOutputFieldIds())
+
+	int64Field, _ := schema.GetFieldFromName("Int64Field")
+	doubleField, _ := schema.GetFieldFromName("DoubleField")
+	candidate := func(offset int, score, i, d float64) *RerankCandidate {
+		return &RerankCandidate{Offset: offset, Score: score, Values: map[int64]float64{int64Field.GetFieldID(): i, doubleField.GetFieldID(): d}}
+	}
+	reranked, err := plan.Rerank([]*RerankCandidate{
+		candidate(0, 3, 0, 0),
+		candidate(1, 2, 5, 0),
+		candidate(2, 1, 0, 3),
+		candidate(3, 0, 1, 2),
+	})
+	require.NoError(t, err)
+	require.Len(t, reranked, 2)
+	assert.Equal(t, 1, reranked[0].Offset)
+	assert.Equal(t, 2, reranked[1].Offset)
+
+	_, err = plan.Rerank([]*RerankCandidate{{Score: 1}})
+	assert.Error(t, err)
+
+	for _, c := range []struct {
+		queryInfo *planpb.QueryInfo
+		rerank    string
+		topK      int64
+	}{
+		{nil, `similarity`, 10},
+		{&planpb.QueryInfo{Topk: 20}, `similarity`, 10},
+		{&planpb.QueryInfo{Topk: 2}, `similarity + VarCharField`, 10},
+	} {
+		_, err := CreateRerankPlan(schema, ``, "FloatVectorField", c.queryInfo, nil, c.rerank, c.topK)
+		assert.Error(t, err)
+	}
+}
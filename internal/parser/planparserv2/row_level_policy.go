@@ -0,0 +1,89 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+type rowLevelPolicyKey struct {
+	collectionID int64
+	role         string
+}
+
+var rowLevelPolicies = typeutil.NewConcurrentMap[rowLevelPolicyKey, string]()
+
+// RegisterRowLevelPolicy registers a mandatory predicate, such as `tenant_id == {tenant}`, which is ANDed onto
+// every expression parsed for the role on the collection. The collection is identified by the collection id
+// the schema helper is tagged with by WithEpoch, and the role is set by WithRole, the empty role being the one of
+// the requests without roles.
+// The predicate should take per-request values as template variables or context functions, like current_user(),
+// instead of inlining them, so that exprCache, which is keyed by the expression string, holds a single entry shared
+// by all tenants, while the values are only filled into the plan of each request.
+func RegisterRowLevelPolicy(collectionID int64, role string, predicate string) {
+	rowLevelPolicies.Insert(rowLevelPolicyKey{collectionID: collectionID, role: role}, predicate)
+}
+
+// UnregisterRowLevelPolicy removes the predicate registered for the role on the collection.
+func UnregisterRowLevelPolicy(collectionID int64, role string) {
+	rowLevelPolicies.Remove(rowLevelPolicyKey{collectionID: collectionID, role: role})
+}
+
+// WithRole sets the role of the request, and the values of the template variables of its row level policy.
+func WithRole(role string, policyValues map[string]*schemapb.TemplateValue) ParseOption {
+	return func(options *parseOptions) {
+		options.role = role
+		options.policyValues = policyValues
+	}
+}
+
+func getRowLevelPolicy(schema *typeutil.SchemaHelper, options *parseOptions) (string, bool) {
+	if schema == nil {
+		return "", false
+	}
+	return rowLevelPolicies.Get(rowLevelPolicyKey{collectionID: schema.GetCollectionID(), role: options.role})
+}
+
+// HasRowLevelPolicy returns whether a row level policy restricts the expressions parsed with the options, so that
+// the callers skipping the parse of empty expressions parse them nonetheless.
+func HasRowLevelPolicy(schema *typeutil.SchemaHelper, opts ...ParseOption) bool {
+	_, ok := getRowLevelPolicy(schema, newParseOptions(opts...))
+	return ok
+}
+
+// applyRowLevelPolicy ANDs the row level policy of the request onto the expression. The policy is not
+// subject to the FieldAuthorizer, since the role may be denied to filter on the fields it restricts, and is applied
+// after the expression interceptors, so that they can't rewrite it away.
+func applyRowLevelPolicy(schema *typeutil.SchemaHelper, expr *planpb.Expr, options *parseOptions) (*planpb.Expr, error) {
+	predicate, ok := getRowLevelPolicy(schema, options)
+	if !ok {
+		return expr, nil
+	}
+	opts := []ParseOption{withoutFieldAuthorization()}
+	if options.requestContext != nil {
+		opts = append(opts, WithRequestContext(*options.requestContext))
+	}
+	policy, err := parseExpr(schema, predicate, options.policyValues, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid row level policy of role %s: %w", options.role, err)
+	}
+	// the policy skips the optimizations of the expression, but not the rewrites its semantics depend on.
+	if err := dispatchJSONTerms(policy, options.jsonTermCoercion); err != nil {
+		return nil, fmt.Errorf("invalid row level policy of role %s: %w", options.role, err)
+	}
+	policy = guardNegatedJSONContains(policy)
+	if isAlwaysTrueExpr(expr) {
+		return policy, nil
+	}
+	return &planpb.Expr{
+		Expr: &planpb.Expr_BinaryExpr{
+			BinaryExpr: &planpb.BinaryExpr{
+				Left:  policy,
+				Right: expr,
+				Op:    planpb.BinaryExpr_LogicalAnd,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,93 @@
+package planparserv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestParseExpr_RowLevelPolicy(t *testing.T) {
+	schema := newTestSchemaHelper(t).WithEpoch("db", 1, 1)
+	RegisterRowLevelPolicy(1, "tenant", `Int64Field == {tenant}`)
+	defer UnregisterRowLevelPolicy(1, "tenant")
+
+	tenantValues := func(tenant int64) map[string]*schemapb.TemplateValue {
+		return map[string]*schemapb.TemplateValue{"tenant": {Val: &schemapb.TemplateValue_Int64Val{Int64Val: tenant}}}
+	}
+
+	expr, err := ParseExpr(schema, `Int32Field > 1 or Int32Field < 0`, nil, WithRole("tenant", tenantValues(7)))
+	require.NoError(t, err)
+	binary := expr.GetBinaryExpr()
+	require.NotNil(t, binary)
+	assert.Equal(t, planpb.BinaryExpr_LogicalAnd, binary.GetOp())
+	assert.Equal(t, int64(7), binary.GetLeft().GetUnaryRangeExpr().GetValue().GetInt64Val())
+
+	// plans of different tenants don't share values.
+	expr, err = ParseExpr(schema, `Int32Field > 1 or Int32Field < 0`, nil, WithRole("tenant", tenantValues(8)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), expr.GetBinaryExpr().GetLeft().GetUnaryRangeExpr().GetValue().GetInt64Val())
+
+	// empty expressions are restricted as well.
+	expr, err = ParseExpr(schema, ``, nil, WithRole("tenant", tenantValues(7)))
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+	plan, err := CreateSearchPlan(schema, ``, "FloatVectorField", &planpb.QueryInfo{}, nil, WithRole("tenant", tenantValues(7)))
+	require.NoError(t, err)
+	assert.NotNil(t, plan.GetVectorAnns().GetPredicates().GetUnaryRangeExpr())
+
+	// missing policy values fail the request.
+	_, err = ParseExpr(schema, `Int32Field > 1`, nil, WithRole("tenant", nil))
+	assert.ErrorContains(t, err, "invalid row level policy")
+
+	// the policy is not subject to the field authorizer.
+	SetFieldAuthorizer(func(ctx context.Context, collectionID int64, fieldID int64) error {
+		if fieldID == 105 {
+			return merr.WrapErrPrivilegeNotPermitted("field %d", fieldID)
+		}
+		return nil
+	})
+	defer SetFieldAuthorizer(nil)
+	_, err = ParseExpr(schema, `Int32Field > 1`, nil, WithRole("tenant", tenantValues(7)))
+	assert.NoError(t, err)
+
+	// the interceptors can't rewrite the policy away.
+	RegisterExprInterceptor("drop", ExprInterceptorFunc(func(ctx context.Context, schema *typeutil.SchemaHelper, expr *planpb.Expr) (*planpb.Expr, error) {
+		return alwaysTrueExpr(), nil
+	}))
+	expr, err = ParseExpr(schema, `Int32Field > 1`, nil, WithRole("tenant", tenantValues(7)))
+	UnregisterExprInterceptor("drop")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), expr.GetUnaryRangeExpr().GetValue().GetInt64Val())
+
+	// other roles and collections are not restricted.
+	expr, err = ParseExpr(schema, `Int32Field > 1`, nil, WithRole("admin", nil))
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+	expr, err = ParseExpr(newTestSchemaHelper(t), `Int32Field > 1`, nil, WithRole("tenant", tenantValues(7)))
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+
+	assert.False(t, HasRowLevelPolicy(schema, WithRole("admin", nil)))
+	assert.False(t, HasRowLevelPolicy(nil, WithRole("tenant", nil)))
+	assert.True(t, HasRowLevelPolicy(schema, WithRole("tenant", nil)))
+}
+
+func TestParseExpr_RowLevelPolicyRequestContext(t *testing.T) {
+	schema := newTestSchemaHelper(t).WithEpoch("db", 1, 1)
+	RegisterRowLevelPolicy(1, "tenant", `VarCharField == current_user()`)
+	defer UnregisterRowLevelPolicy(1, "tenant")
+
+	expr, err := ParseExpr(schema, `Int32Field > 1`, nil, WithRole("tenant", nil), WithRequestContext(RequestContext{User: "alice"}))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", expr.GetBinaryExpr().GetLeft().GetUnaryRangeExpr().GetValue().GetStringVal())
+
+	_, err = ParseExpr(schema, `Int32Field > 1`, nil, WithRole("tenant", nil))
+	assert.ErrorContains(t, err, "invalid row level policy")
+}
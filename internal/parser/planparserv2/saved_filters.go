@@ -0,0 +1,49 @@
+package planparserv2
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+)
+
+// savedFilterPrefix starts a reference to a saved filter, like `$saved(my_filter)`.
+const savedFilterPrefix = "$saved"
+
+var savedFilterPattern = regexp.MustCompile(`^\$saved\s*\(\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\)`)
+
+// SavedFilterResolver resolves the saved filters referenced by `$saved(name)` in expressions of a collection,
+// which are expanded to the returned sub-expressions before parsing. Saved filters may reference macros and
+// other saved filters.
+type SavedFilterResolver interface {
+	ResolveSavedFilter(ctx context.Context, collectionID int64, name string) (string, error)
+}
+
+var savedFilterResolver atomic.Pointer[SavedFilterResolver]
+
+// SetSavedFilterResolver sets the resolver of saved filters. A nil resolver rejects all references to saved filters.
+func SetSavedFilterResolver(resolver SavedFilterResolver) {
+	if resolver == nil {
+		savedFilterResolver.Store(nil)
+		return
+	}
+	savedFilterResolver.Store(&resolver)
+}
+
+// resolveSavedFilter resolves the saved filter referenced at the start of s, and returns the reference to it,
+// its body and the length of the reference.
+func (e *macroExpander) resolveSavedFilter(s string, pos int) (string, string, int, error) {
+	match := savedFilterPattern.FindStringSubmatch(s)
+	if match == nil {
+		return "", "", 0, fmt.Errorf("invalid saved filter reference at position %d, expected $saved(name)", pos)
+	}
+	resolver := savedFilterResolver.Load()
+	if resolver == nil {
+		return "", "", 0, fmt.Errorf("saved filter %s cannot be resolved, saved filters are not enabled", match[1])
+	}
+	body, err := (*resolver).ResolveSavedFilter(e.ctx, e.collectionID, match[1])
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to resolve saved filter %s: %w", match[1], err)
+	}
+	return savedFilterPrefix + "(" + match[1] + ")", body, len(match[0]), nil
+}
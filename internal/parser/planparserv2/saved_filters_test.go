@@ -0,0 +1,53 @@
+package planparserv2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+type testSavedFilterResolver map[string]string
+
+func (r testSavedFilterResolver) ResolveSavedFilter(ctx context.Context, collectionID int64, name string) (string, error) {
+	if filter, ok := r[name]; ok {
+		return filter, nil
+	}
+	return "", fmt.Errorf("no saved filter %s in collection %d", name, collectionID)
+}
+
+func TestSavedFilters(t *testing.T) {
+	schema := newMacroSchemaHelper(t, map[string]string{"active": `Int64Field > 0`})
+
+	_, err := ParseExpr(schema, `$saved(cheap)`, nil)
+	assert.ErrorContains(t, err, "saved filters are not enabled")
+
+	SetSavedFilterResolver(testSavedFilterResolver{
+		"cheap":        `FloatField < 10`,
+		"active_cheap": `@active() and $saved(cheap)`,
+		"self":         `$saved( self )`,
+	})
+	defer SetSavedFilterResolver(nil)
+
+	expanded, err := expandMacros(schema, `$saved(active_cheap) and $meta["$saved(x)"] == 1`, newParseOptions())
+	require.NoError(t, err)
+	assert.Equal(t, `((Int64Field > 0) and (FloatField < 10)) and $meta["$saved(x)"] == 1`, expanded)
+
+	expr, err := ParseExpr(schema, `$saved(cheap) and DoubleField > 10`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, planpb.OpType_LessThan, expr.GetBinaryExpr().GetLeft().GetUnaryRangeExpr().GetOp())
+
+	for exprStr, msg := range map[string]string{
+		`$saved(unknown)`:      "failed to resolve saved filter unknown: no saved filter unknown",
+		`$saved(self)`:         "$saved(self) references itself",
+		`$saved("cheap")`:      "invalid saved filter reference at position 0",
+		`$savedcheap and true`: "invalid saved filter reference",
+	} {
+		_, err := ParseExpr(schema, exprStr, nil)
+		assert.ErrorContains(t, err, msg, exprStr)
+	}
+}
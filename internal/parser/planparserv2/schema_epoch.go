@@ -0,0 +1,101 @@
+package planparserv2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/antlr4-go/antlr/v4"
+
+	planparserv2 "github.com/milvus-io/milvus/internal/parser/planparserv2/generated"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// LatestSchemaProvider fetches the latest schema of the collection from the authoritative source,
+// for example the root coordinator. The returned helper must be tagged by WithEpoch. ctx is the context of the
+// request, set by WithContext.
+type LatestSchemaProvider func(ctx context.Context, database string, collectionID int64) (*typeutil.SchemaHelper, error)
+
+var latestSchemaProvider atomic.Pointer[LatestSchemaProvider]
+
+// SetLatestSchemaProvider sets the provider used to tell whether a failed expression refers to fields
+// added after the caller's schema snapshot. A nil provider disables the check.
+func SetLatestSchemaProvider(provider LatestSchemaProvider) {
+	if provider == nil {
+		latestSchemaProvider.Store(nil)
+		return
+	}
+	latestSchemaProvider.Store(&provider)
+}
+
+// checkSchemaOutdated turns a failed parse into a retryable error if the expression refers to fields
+// missing in the caller's snapshot but present in the latest schema, so the caller refreshes its schema
+// and retries instead of failing the request.
+// The latest schema is only fetched when the parse fails on unknown fields. With the dynamic field enabled,
+// unknown fields don't fail, they keep resolving into the dynamic field until the snapshot is refreshed.
+// Note that exprCache only holds syntax trees, which don't depend on the schema, so it is not keyed by epoch.
+func checkSchemaOutdated(ctx context.Context, schema *typeutil.SchemaHelper, ast planparserv2.IExprContext, err error) error {
+	provider := latestSchemaProvider.Load()
+	if provider == nil || schema == nil || schema.GetEpoch() == 0 {
+		return err
+	}
+	unknownFields := collectUnknownFields(schema, ast)
+	if len(unknownFields) == 0 {
+		return err
+	}
+	latest, providerErr := (*provider)(ctx, schema.GetDatabaseName(), schema.GetCollectionID())
+	if providerErr != nil || latest == nil || latest.GetEpoch() <= schema.GetEpoch() {
+		return err
+	}
+	for _, name := range unknownFields {
+		if _, fieldErr := latest.GetFieldFromName(name); fieldErr == nil {
+			return merr.WrapErrCollectionSchemaOutdated(schema.GetCollectionID(),
+				fmt.Sprintf("field %s exists in schema epoch %d, later than epoch %d", name, latest.GetEpoch(), schema.GetEpoch()))
+		}
+	}
+	return err
+}
+
+// collectUnknownFields returns the identifiers used as fields but missing in the schema.
+func collectUnknownFields(schema *typeutil.SchemaHelper, tree antlr.Tree) []string {
+	var names []string
+	seen := make(map[string]struct{})
+	var walk func(node antlr.Tree)
+	walk = func(node antlr.Tree) {
+		switch n := node.(type) {
+		case *planparserv2.CallContext, *planparserv2.TemplateVariableContext:
+			// function names and template variables are not fields.
+			for _, child := range n.GetChildren() {
+				if _, ok := child.(antlr.TerminalNode); !ok {
+					walk(child)
+				}
+			}
+			return
+		case antlr.TerminalNode:
+			var name string
+			switch n.GetSymbol().GetTokenType() {
+			case planparserv2.PlanParserIdentifier:
+				name = n.GetText()
+			case planparserv2.PlanParserJSONIdentifier:
+				name = n.GetText()[:strings.Index(n.GetText(), "[")]
+			default:
+				return
+			}
+			if _, ok := seen[name]; ok {
+				return
+			}
+			seen[name] = struct{}{}
+			if _, err := schema.GetFieldFromName(name); err != nil {
+				names = append(names, name)
+			}
+			return
+		}
+		for _, child := range node.GetChildren() {
+			walk(child)
+		}
+	}
+	walk(tree)
+	return names
+}
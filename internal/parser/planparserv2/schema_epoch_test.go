@@ -0,0 +1,87 @@
+package planparserv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestParseExpr_SchemaEpoch(t *testing.T) {
+	newHelper := func(withNewField bool, epoch uint64) *typeutil.SchemaHelper {
+		schema := newTestSchema(false)
+		if withNewField {
+			schema.Fields = append(schema.Fields, &schemapb.FieldSchema{
+				FieldID: 1000, Name: "NewField", DataType: schemapb.DataType_Int64, Nullable: true,
+			})
+		}
+		helper, err := typeutil.CreateSchemaHelper(schema)
+		require.NoError(t, err)
+		return helper.WithEpoch("db", 1, epoch)
+	}
+	stale := newHelper(false, 1)
+
+	// no provider, the error is kept as is.
+	_, err := ParseExpr(stale, `NewField > 1`, nil)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, merr.ErrCollectionSchemaOutdated))
+
+	var latest *typeutil.SchemaHelper
+	var providerErr error
+	calls := 0
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	SetLatestSchemaProvider(func(providerCtx context.Context, database string, collectionID int64) (*typeutil.SchemaHelper, error) {
+		calls++
+		assert.Equal(t, "request", providerCtx.Value(ctxKey{}))
+		assert.Equal(t, "db", database)
+		assert.Equal(t, int64(1), collectionID)
+		return latest, providerErr
+	})
+	defer SetLatestSchemaProvider(nil)
+
+	latest = newHelper(true, 2)
+	_, err = ParseExpr(stale, `Int64Field > 1 and NewField > 1`, nil, WithContext(ctx))
+	assert.ErrorIs(t, err, merr.ErrCollectionSchemaOutdated)
+	_, err = ParseExpr(latest, `Int64Field > 1 and NewField > 1`, nil)
+	assert.NoError(t, err)
+
+	// errors on known fields don't fetch the latest schema.
+	calls = 0
+	_, err = ParseExpr(stale, `VarCharField > 1`, nil, WithContext(ctx))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, merr.ErrCollectionSchemaOutdated))
+	_, err = ParseExpr(stale, `foo(Int64Field) and VarCharField > 1`, nil, WithContext(ctx))
+	assert.Error(t, err)
+	assert.Equal(t, 0, calls)
+
+	// the field doesn't exist in the latest schema either.
+	_, err = ParseExpr(stale, `OtherField["a"] > 1`, nil, WithContext(ctx))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, merr.ErrCollectionSchemaOutdated))
+	assert.Equal(t, 1, calls)
+
+	// the latest schema is not newer, or cannot be fetched.
+	latest = newHelper(true, 1)
+	_, err = ParseExpr(stale, `NewField > 1`, nil, WithContext(ctx))
+	assert.False(t, errors.Is(err, merr.ErrCollectionSchemaOutdated))
+	latest, providerErr = nil, errors.New("mock error")
+	_, err = ParseExpr(stale, `NewField > 1`, nil, WithContext(ctx))
+	assert.False(t, errors.Is(err, merr.ErrCollectionSchemaOutdated))
+
+	// snapshots without epoch are not checked.
+	calls = 0
+	_, err = ParseExpr(newTestSchemaHelper(t), `NewField > 1`, nil)
+	assert.NoError(t, err)
+	helper, err := typeutil.CreateSchemaHelper(newTestSchema(false))
+	require.NoError(t, err)
+	_, err = ParseExpr(helper, `NewField > 1`, nil)
+	assert.Error(t, err)
+	assert.Equal(t, 0, calls)
+}
@@ -0,0 +1,202 @@
+package planparserv2
+
+import (
+	"encoding/json"
+	"fmt"
+
+	parser "github.com/milvus-io/milvus/internal/parser/planparserv2/generated"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+const (
+	radiusKey      = "radius"
+	rangeFilterKey = "range_filter"
+)
+
+// ScoreFilter bounds the scores of search results, such as `similarity > 0.8` or `0.2 < similarity <= 0.9`.
+// A nil bound is unbounded.
+type ScoreFilter struct {
+	Lower          *float64
+	LowerInclusive bool
+	Upper          *float64
+	UpperInclusive bool
+}
+
+// WithScoreFilter drops the search results whose scores don't satisfy the score filter expression.
+// The filter is declared in the search params of the plan as the radius and range_filter of range search.
+func WithScoreFilter(exprStr string) ParseOption {
+	return func(options *parseOptions) {
+		options.scoreFilter = exprStr
+	}
+}
+
+// ParseScoreFilter parses a score filter made of comparisons between `similarity` and constants, joined by `and`.
+func ParseScoreFilter(schema *typeutil.SchemaHelper, exprStr string) (*ScoreFilter, error) {
+	ast, err := handleInternal(exprStr, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse score filter: %s, error: %w", displayExpr(exprStr), redactError(err))
+	}
+	filter := &ScoreFilter{}
+	builder := &rankBuilder{schema: schema, fields: make(map[int64]struct{})}
+	if err := filter.build(builder, ast); err != nil {
+		return nil, fmt.Errorf("cannot parse score filter: %s, error: %w", displayExpr(exprStr), redactError(err))
+	}
+	return filter, nil
+}
+
+func (f *ScoreFilter) build(builder *rankBuilder, tree parser.IExprContext) error {
+	switch ctx := tree.(type) {
+	case *parser.ParensContext:
+		return f.build(builder, ctx.Expr())
+	case *parser.LogicalAndContext:
+		if err := f.build(builder, ctx.Expr(0)); err != nil {
+			return err
+		}
+		return f.build(builder, ctx.Expr(1))
+	case *parser.RelationalContext:
+		left, err := builder.build(ctx.Expr(0))
+		if err != nil {
+			return err
+		}
+		right, err := builder.build(ctx.Expr(1))
+		if err != nil {
+			return err
+		}
+		op := ctx.GetOp().GetTokenType()
+		if _, ok := right.(rankVar); ok {
+			left, right = right, left
+			op = reverseRelationalOp(op)
+		}
+		if _, ok := left.(rankVar); !ok {
+			return fmt.Errorf("%s doesn't compare similarity", ctx.GetText())
+		}
+		value, err := scoreFilterConstant(right)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case parser.PlanParserGT, parser.PlanParserGE:
+			return f.setLower(value, op == parser.PlanParserGE)
+		default:
+			return f.setUpper(value, op == parser.PlanParserLE)
+		}
+	case *parser.RangeContext:
+		return f.buildRange(builder, ctx.Identifier(), ctx.Expr(0), ctx.GetOp1().GetTokenType() == parser.PlanParserLE,
+			ctx.Expr(1), ctx.GetOp2().GetTokenType() == parser.PlanParserLE)
+	case *parser.ReverseRangeContext:
+		return f.buildRange(builder, ctx.Identifier(), ctx.Expr(1), ctx.GetOp2().GetTokenType() == parser.PlanParserGE,
+			ctx.Expr(0), ctx.GetOp1().GetTokenType() == parser.PlanParserGE)
+	default:
+		return fmt.Errorf("%s is not supported in score filters", tree.GetText())
+	}
+}
+
+func (f *ScoreFilter) buildRange(builder *rankBuilder, identifier interface{ GetText() string },
+	lower parser.IExprContext, lowerInclusive bool, upper parser.IExprContext, upperInclusive bool,
+) error {
+	if identifier == nil || identifier.GetText() != rankSimilarity {
+		return fmt.Errorf("range of score filters must be on similarity")
+	}
+	for _, bound := range []struct {
+		expr      parser.IExprContext
+		inclusive bool
+		set       func(float64, bool) error
+	}{{lower, lowerInclusive, f.setLower}, {upper, upperInclusive, f.setUpper}} {
+		node, err := builder.build(bound.expr)
+		if err != nil {
+			return err
+		}
+		value, err := scoreFilterConstant(node)
+		if err != nil {
+			return err
+		}
+		if err := bound.set(value, bound.inclusive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *ScoreFilter) setLower(value float64, inclusive bool) error {
+	if f.Lower != nil {
+		return fmt.Errorf("lower bound of similarity is given more than once")
+	}
+	f.Lower, f.LowerInclusive = &value, inclusive
+	return nil
+}
+
+func (f *ScoreFilter) setUpper(value float64, inclusive bool) error {
+	if f.Upper != nil {
+		return fmt.Errorf("upper bound of similarity is given more than once")
+	}
+	f.Upper, f.UpperInclusive = &value, inclusive
+	return nil
+}
+
+func scoreFilterConstant(node rankNode) (float64, error) {
+	value, ok := node.(rankConst)
+	if !ok {
+		return 0, fmt.Errorf("similarity can only be compared with constants")
+	}
+	return float64(value), nil
+}
+
+func reverseRelationalOp(op int) int {
+	switch op {
+	case parser.PlanParserLT:
+		return parser.PlanParserGT
+	case parser.PlanParserLE:
+		return parser.PlanParserGE
+	case parser.PlanParserGT:
+		return parser.PlanParserLT
+	default:
+		return parser.PlanParserLE
+	}
+}
+
+// applyScoreFilter sets the score filter as the radius and range_filter of the search params. Range search keeps
+// the results where radius < score <= range_filter for similarity metrics, and range_filter <= score < radius for
+// distance metrics, so only such bounds are accepted.
+func applyScoreFilter(filter *ScoreFilter, queryInfo *planpb.QueryInfo) error {
+	if queryInfo.GetMetricType() == "" {
+		return fmt.Errorf("metric type is required by score filters")
+	}
+	radius, rangeFilter := filter.Lower, filter.Upper
+	radiusInclusive, rangeFilterInclusive := filter.LowerInclusive, filter.UpperInclusive
+	if !metric.PositivelyRelated(queryInfo.GetMetricType()) {
+		radius, rangeFilter = filter.Upper, filter.Lower
+		radiusInclusive, rangeFilterInclusive = filter.UpperInclusive, filter.LowerInclusive
+	}
+	if radius == nil {
+		return fmt.Errorf("score filter of metric %s requires the bound of similarity towards worse scores", queryInfo.GetMetricType())
+	}
+	if radiusInclusive || (rangeFilter != nil && !rangeFilterInclusive) {
+		return fmt.Errorf("score filter of metric %s must exclude the bound towards worse scores and include the other one",
+			queryInfo.GetMetricType())
+	}
+
+	params := make(map[string]interface{})
+	if queryInfo.GetSearchParams() != "" {
+		if err := json.Unmarshal([]byte(queryInfo.GetSearchParams()), &params); err != nil {
+			return fmt.Errorf("invalid search params: %w", err)
+		}
+	}
+	if _, ok := params[radiusKey]; ok {
+		return fmt.Errorf("score filter conflicts with the radius of search params")
+	}
+	if _, ok := params[rangeFilterKey]; ok {
+		return fmt.Errorf("score filter conflicts with the range_filter of search params")
+	}
+	params[radiusKey] = *radius
+	if rangeFilter != nil {
+		params[rangeFilterKey] = *rangeFilter
+	}
+	searchParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	queryInfo.SearchParams = string(searchParams)
+	return nil
+}
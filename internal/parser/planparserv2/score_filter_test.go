@@ -0,0 +1,72 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestParseScoreFilter(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	bound := func(v float64) *float64 { return &v }
+
+	for exprStr, expected := range map[string]*ScoreFilter{
+		`similarity > 0.8`:                       {Lower: bound(0.8)},
+		`0.8 <= similarity`:                      {Lower: bound(0.8), LowerInclusive: true},
+		`similarity < -1`:                        {Upper: bound(-1)},
+		`0.2 < similarity <= 0.9`:                {Lower: bound(0.2), Upper: bound(0.9), UpperInclusive: true},
+		`0.9 >= similarity > 0.2`:                {Lower: bound(0.2), Upper: bound(0.9), UpperInclusive: true},
+		`(similarity >= 1) and similarity < 2.5`: {Lower: bound(1), LowerInclusive: true, Upper: bound(2.5)},
+	} {
+		filter, err := ParseScoreFilter(schema, exprStr)
+		require.NoError(t, err, exprStr)
+		assert.Equal(t, expected, filter, exprStr)
+	}
+
+	for _, exprStr := range []string{
+		`similarity == 1`,
+		`similarity > 1 or similarity < 0`,
+		`similarity > 1 and similarity > 2`,
+		`similarity > Int64Field`,
+		`Int64Field > 1`,
+		`0 < Int64Field < 1`,
+		`similarity + 1 > 2`,
+		`similarity >`,
+	} {
+		_, err := ParseScoreFilter(schema, exprStr)
+		assert.Error(t, err, exprStr)
+	}
+}
+
+func TestCreateSearchPlan_ScoreFilter(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	plan, err := CreateSearchPlan(schema, `Int64Field > 0`, "FloatVectorField",
+		&planpb.QueryInfo{MetricType: "COSINE", SearchParams: `{"nprobe":10}`}, nil, WithScoreFilter(`0.5 < similarity <= 0.9`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"nprobe":10,"radius":0.5,"range_filter":0.9}`, plan.GetVectorAnns().GetQueryInfo().GetSearchParams())
+
+	plan, err = CreateSearchPlan(schema, ``, "FloatVectorField", &planpb.QueryInfo{MetricType: "L2"}, nil, WithScoreFilter(`similarity < 2`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"radius":2}`, plan.GetVectorAnns().GetQueryInfo().GetSearchParams())
+
+	for _, c := range []struct {
+		queryInfo *planpb.QueryInfo
+		filter    string
+	}{
+		{&planpb.QueryInfo{}, `similarity > 0.5`},
+		{&planpb.QueryInfo{MetricType: "IP"}, `similarity >= 0.5`},
+		{&planpb.QueryInfo{MetricType: "IP"}, `similarity < 0.5`},
+		{&planpb.QueryInfo{MetricType: "IP"}, `0.5 < similarity < 0.9`},
+		{&planpb.QueryInfo{MetricType: "L2"}, `similarity > 0.5`},
+		{&planpb.QueryInfo{MetricType: "L2"}, `similarity <= 0.5`},
+		{&planpb.QueryInfo{MetricType: "IP", SearchParams: `{"radius":0.1}`}, `similarity > 0.5`},
+		{&planpb.QueryInfo{MetricType: "IP", SearchParams: `{`}, `similarity > 0.5`},
+	} {
+		_, err := CreateSearchPlan(schema, ``, "FloatVectorField", c.queryInfo, nil, WithScoreFilter(c.filter))
+		assert.Error(t, err, c.filter)
+	}
+}
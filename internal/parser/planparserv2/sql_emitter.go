@@ -0,0 +1,393 @@
+package planparserv2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// SQLDialect decides how identifiers, literals and JSON accesses are rendered by EmitSQL.
+type SQLDialect int
+
+const (
+	SQLDialectANSI SQLDialect = iota
+	SQLDialectPostgres
+	SQLDialectMySQL
+	SQLDialectSQLite
+)
+
+func (d SQLDialect) String() string {
+	switch d {
+	case SQLDialectANSI:
+		return "ansi"
+	case SQLDialectPostgres:
+		return "postgres"
+	case SQLDialectMySQL:
+		return "mysql"
+	case SQLDialectSQLite:
+		return "sqlite"
+	default:
+		return "unknown"
+	}
+}
+
+// EmitSQL renders a parsed expression as a SQL WHERE clause (without the WHERE keyword).
+// Field ids are resolved to column names through the schema, JSON and array accesses are
+// rendered with the json functions of the dialect.
+// Predicates without a relational counterpart, such as text_match or random_sample, are rejected.
+func EmitSQL(schema *typeutil.SchemaHelper, expr *planpb.Expr, dialect SQLDialect) (string, error) {
+	if dialect < SQLDialectANSI || dialect > SQLDialectSQLite {
+		return "", fmt.Errorf("unknown sql dialect: %d", dialect)
+	}
+	e := &sqlEmitter{schema: schema, dialect: dialect}
+	return e.emit(expr)
+}
+
+type sqlEmitter struct {
+	schema  *typeutil.SchemaHelper
+	dialect SQLDialect
+}
+
+var sqlCompareOps = map[planpb.OpType]string{
+	planpb.OpType_GreaterThan:  ">",
+	planpb.OpType_GreaterEqual: ">=",
+	planpb.OpType_LessThan:     "<",
+	planpb.OpType_LessEqual:    "<=",
+	planpb.OpType_Equal:        "=",
+	planpb.OpType_NotEqual:     "<>",
+}
+
+var sqlArithOps = map[planpb.ArithOpType]string{
+	planpb.ArithOpType_Add: "+",
+	planpb.ArithOpType_Sub: "-",
+	planpb.ArithOpType_Mul: "*",
+	planpb.ArithOpType_Div: "/",
+	planpb.ArithOpType_Mod: "%",
+}
+
+func (e *sqlEmitter) emit(expr *planpb.Expr) (string, error) {
+	if expr.GetIsTemplate() {
+		if err := checkTemplateFilled(expr); err != nil {
+			return "", err
+		}
+	}
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_AlwaysTrueExpr:
+		return e.boolLiteral(true), nil
+	case *planpb.Expr_UnaryExpr:
+		child, err := e.emit(realExpr.UnaryExpr.GetChild())
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + child + ")", nil
+	case *planpb.Expr_BinaryExpr:
+		left, err := e.emit(realExpr.BinaryExpr.GetLeft())
+		if err != nil {
+			return "", err
+		}
+		right, err := e.emit(realExpr.BinaryExpr.GetRight())
+		if err != nil {
+			return "", err
+		}
+		op := "AND"
+		if realExpr.BinaryExpr.GetOp() == planpb.BinaryExpr_LogicalOr {
+			op = "OR"
+		}
+		return fmt.Sprintf("(%s %s %s)", left, op, right), nil
+	case *planpb.Expr_TermExpr:
+		return e.emitTerm(realExpr.TermExpr)
+	case *planpb.Expr_CompareExpr:
+		return e.emitCompare(realExpr.CompareExpr)
+	case *planpb.Expr_UnaryRangeExpr:
+		return e.emitUnaryRange(realExpr.UnaryRangeExpr)
+	case *planpb.Expr_BinaryRangeExpr:
+		return e.emitBinaryRange(realExpr.BinaryRangeExpr)
+	case *planpb.Expr_BinaryArithOpEvalRangeExpr:
+		return e.emitArithOpEvalRange(realExpr.BinaryArithOpEvalRangeExpr)
+	case *planpb.Expr_NullExpr:
+		column, err := e.column(realExpr.NullExpr.GetColumnInfo())
+		if err != nil {
+			return "", err
+		}
+		if realExpr.NullExpr.GetOp() == planpb.NullExpr_IsNotNull {
+			return column + " IS NOT NULL", nil
+		}
+		return column + " IS NULL", nil
+	case *planpb.Expr_ExistsExpr:
+		column, err := e.column(realExpr.ExistsExpr.GetInfo())
+		if err != nil {
+			return "", err
+		}
+		return column + " IS NOT NULL", nil
+	default:
+		return "", fmt.Errorf("expression %T has no sql equivalent", realExpr)
+	}
+}
+
+func checkTemplateFilled(expr *planpb.Expr) error {
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_TermExpr:
+		if realExpr.TermExpr.GetValues() == nil {
+			return fmt.Errorf("template variable {%s} is not filled", realExpr.TermExpr.GetTemplateVariableName())
+		}
+	case *planpb.Expr_UnaryRangeExpr:
+		if realExpr.UnaryRangeExpr.GetValue() == nil {
+			return fmt.Errorf("template variable {%s} is not filled", realExpr.UnaryRangeExpr.GetTemplateVariableName())
+		}
+	}
+	return nil
+}
+
+func (e *sqlEmitter) quoteIdentifier(name string) string {
+	if e.dialect == SQLDialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (e *sqlEmitter) quoteString(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if e.dialect == SQLDialectMySQL {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + s + "'"
+}
+
+func (e *sqlEmitter) boolLiteral(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (e *sqlEmitter) literal(value *planpb.GenericValue) (string, error) {
+	switch v := value.GetVal().(type) {
+	case *planpb.GenericValue_BoolVal:
+		return e.boolLiteral(v.BoolVal), nil
+	case *planpb.GenericValue_Int64Val:
+		return strconv.FormatInt(v.Int64Val, 10), nil
+	case *planpb.GenericValue_FloatVal:
+		return strconv.FormatFloat(v.FloatVal, 'g', -1, 64), nil
+	case *planpb.GenericValue_StringVal:
+		return e.quoteString(v.StringVal), nil
+	default:
+		return "", fmt.Errorf("literal %v has no sql equivalent", value)
+	}
+}
+
+// jsonPath renders nested path [a, 0] as $."a"[0] for the json functions.
+func jsonPath(nestedPath []string) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, path := range nestedPath {
+		if _, err := strconv.ParseUint(path, 10, 64); err == nil {
+			b.WriteString("[" + path + "]")
+		} else {
+			b.WriteString(`."` + strings.ReplaceAll(path, `"`, `\"`) + `"`)
+		}
+	}
+	return b.String()
+}
+
+func (e *sqlEmitter) column(info *planpb.ColumnInfo) (string, error) {
+	field, err := e.schema.GetFieldFromID(info.GetFieldId())
+	if err != nil {
+		return "", err
+	}
+	column := e.quoteIdentifier(field.GetName())
+	nestedPath := info.GetNestedPath()
+	if len(nestedPath) == 0 {
+		return column, nil
+	}
+
+	if typeutil.IsArrayType(info.GetDataType()) && e.dialect == SQLDialectPostgres {
+		// postgres arrays are 1-based.
+		index, err := strconv.ParseInt(nestedPath[0], 10, 64)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s[%d]", column, index+1), nil
+	}
+
+	switch e.dialect {
+	case SQLDialectPostgres:
+		quoted := make([]string, 0, len(nestedPath))
+		for _, path := range nestedPath {
+			quoted = append(quoted, `"`+strings.ReplaceAll(path, `"`, `\"`)+`"`)
+		}
+		return fmt.Sprintf("(%s #>> %s)", column, e.quoteString("{"+strings.Join(quoted, ",")+"}")), nil
+	case SQLDialectMySQL:
+		return fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, %s))", column, e.quoteString(jsonPath(nestedPath))), nil
+	case SQLDialectSQLite:
+		return fmt.Sprintf("json_extract(%s, %s)", column, e.quoteString(jsonPath(nestedPath))), nil
+	default:
+		return fmt.Sprintf("JSON_VALUE(%s, %s)", column, e.quoteString(jsonPath(nestedPath))), nil
+	}
+}
+
+// typedColumn renders the column compared with the value. Postgres extracts json values as text,
+// they are cast to the type of the value to be comparable.
+func (e *sqlEmitter) typedColumn(info *planpb.ColumnInfo, value *planpb.GenericValue) (string, error) {
+	column, err := e.column(info)
+	if err != nil {
+		return "", err
+	}
+	if e.dialect != SQLDialectPostgres || len(info.GetNestedPath()) == 0 || !typeutil.IsJSONType(info.GetDataType()) {
+		return column, nil
+	}
+	switch value.GetVal().(type) {
+	case *planpb.GenericValue_Int64Val, *planpb.GenericValue_FloatVal:
+		return column + "::numeric", nil
+	case *planpb.GenericValue_BoolVal:
+		return column + "::boolean", nil
+	default:
+		return column, nil
+	}
+}
+
+func (e *sqlEmitter) emitTerm(expr *planpb.TermExpr) (string, error) {
+	if len(expr.GetValues()) == 0 {
+		// in [] never matches.
+		return e.boolLiteral(false), nil
+	}
+	column, err := e.typedColumn(expr.GetColumnInfo(), expr.GetValues()[0])
+	if err != nil {
+		return "", err
+	}
+	values := make([]string, 0, len(expr.GetValues()))
+	for _, value := range expr.GetValues() {
+		literal, err := e.literal(value)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, literal)
+	}
+	return fmt.Sprintf("%s IN (%s)", column, strings.Join(values, ", ")), nil
+}
+
+func (e *sqlEmitter) emitCompare(expr *planpb.CompareExpr) (string, error) {
+	op, ok := sqlCompareOps[expr.GetOp()]
+	if !ok {
+		return "", fmt.Errorf("compare operator %s has no sql equivalent", expr.GetOp())
+	}
+	left, err := e.column(expr.GetLeftColumnInfo())
+	if err != nil {
+		return "", err
+	}
+	right, err := e.column(expr.GetRightColumnInfo())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s", left, op, right), nil
+}
+
+// likePattern recovers the like pattern from the translated match operation,
+// the operand keeps the escaped wildcards of the original pattern.
+func likePattern(op planpb.OpType, operand string) string {
+	switch op {
+	case planpb.OpType_PrefixMatch:
+		return operand + "%"
+	case planpb.OpType_PostfixMatch:
+		return "%" + operand
+	default:
+		return operand
+	}
+}
+
+func (e *sqlEmitter) emitUnaryRange(expr *planpb.UnaryRangeExpr) (string, error) {
+	column, err := e.typedColumn(expr.GetColumnInfo(), expr.GetValue())
+	if err != nil {
+		return "", err
+	}
+	if op, ok := sqlCompareOps[expr.GetOp()]; ok {
+		literal, err := e.literal(expr.GetValue())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s %s", column, op, literal), nil
+	}
+
+	switch expr.GetOp() {
+	case planpb.OpType_PrefixMatch, planpb.OpType_PostfixMatch, planpb.OpType_Match:
+	default:
+		return "", fmt.Errorf("operator %s has no sql equivalent", expr.GetOp())
+	}
+	// the pattern of like expressions already follows the sql convention.
+	pattern := likePattern(expr.GetOp(), expr.GetValue().GetStringVal())
+	return fmt.Sprintf("%s LIKE %s ESCAPE %s", column, e.quoteString(pattern), e.quoteString(`\`)), nil
+}
+
+func (e *sqlEmitter) emitBinaryRange(expr *planpb.BinaryRangeExpr) (string, error) {
+	column, err := e.typedColumn(expr.GetColumnInfo(), expr.GetLowerValue())
+	if err != nil {
+		return "", err
+	}
+	lower, err := e.literal(expr.GetLowerValue())
+	if err != nil {
+		return "", err
+	}
+	upper, err := e.literal(expr.GetUpperValue())
+	if err != nil {
+		return "", err
+	}
+	if expr.GetLowerInclusive() && expr.GetUpperInclusive() {
+		return fmt.Sprintf("%s BETWEEN %s AND %s", column, lower, upper), nil
+	}
+	lowerOp, upperOp := ">", "<"
+	if expr.GetLowerInclusive() {
+		lowerOp = ">="
+	}
+	if expr.GetUpperInclusive() {
+		upperOp = "<="
+	}
+	return fmt.Sprintf("(%s %s %s AND %s %s %s)", column, lowerOp, lower, column, upperOp, upper), nil
+}
+
+func (e *sqlEmitter) arrayLength(column string) (string, error) {
+	switch e.dialect {
+	case SQLDialectPostgres:
+		return fmt.Sprintf("cardinality(%s)", column), nil
+	case SQLDialectMySQL:
+		return fmt.Sprintf("JSON_LENGTH(%s)", column), nil
+	case SQLDialectSQLite:
+		return fmt.Sprintf("json_array_length(%s)", column), nil
+	default:
+		return "", fmt.Errorf("array_length has no equivalent in dialect %s", e.dialect)
+	}
+}
+
+func (e *sqlEmitter) emitArithOpEvalRange(expr *planpb.BinaryArithOpEvalRangeExpr) (string, error) {
+	column, err := e.typedColumn(expr.GetColumnInfo(), expr.GetValue())
+	if err != nil {
+		return "", err
+	}
+	cmpOp, ok := sqlCompareOps[expr.GetOp()]
+	if !ok {
+		return "", fmt.Errorf("compare operator %s has no sql equivalent", expr.GetOp())
+	}
+	value, err := e.literal(expr.GetValue())
+	if err != nil {
+		return "", err
+	}
+
+	var left string
+	if expr.GetArithOp() == planpb.ArithOpType_ArrayLength {
+		if left, err = e.arrayLength(column); err != nil {
+			return "", err
+		}
+	} else {
+		arithOp, ok := sqlArithOps[expr.GetArithOp()]
+		if !ok {
+			return "", fmt.Errorf("arithmetic operator %s has no sql equivalent", expr.GetArithOp())
+		}
+		operand, err := e.literal(expr.GetRightOperand())
+		if err != nil {
+			return "", err
+		}
+		left = fmt.Sprintf("(%s %s %s)", column, arithOp, operand)
+	}
+	return fmt.Sprintf("%s %s %s", left, cmpOp, value), nil
+}
@@ -0,0 +1,81 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestEmitSQL(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	cases := []struct {
+		expr    string
+		dialect SQLDialect
+		sql     string
+	}{
+		{``, SQLDialectANSI, `TRUE`},
+		{`Int64Field in [1, 2]`, SQLDialectANSI, `"Int64Field" IN (1, 2)`},
+		{`Int64Field in []`, SQLDialectANSI, `FALSE`},
+		{`Int64Field > 1 and not (VarCharField == "it's")`, SQLDialectANSI, `("Int64Field" > 1 AND NOT ("VarCharField" = 'it''s'))`},
+		{`Int64Field < 1 or BoolField == true`, SQLDialectMySQL, "(`Int64Field` < 1 OR `BoolField` = TRUE)"},
+		{`VarCharField like "ab%"`, SQLDialectANSI, `"VarCharField" LIKE 'ab%' ESCAPE '\'`},
+		{`VarCharField like "a_b%"`, SQLDialectPostgres, `"VarCharField" LIKE 'a_b%' ESCAPE '\'`},
+		{`VarCharField like "a\\%b%"`, SQLDialectANSI, `"VarCharField" LIKE 'a\%b%' ESCAPE '\'`},
+		{`VarCharField like "%a\\_b"`, SQLDialectSQLite, `"VarCharField" LIKE '%a\_b' ESCAPE '\'`},
+		{`VarCharField like "a\\%b%"`, SQLDialectMySQL, "`VarCharField` LIKE 'a\\\\%b%' ESCAPE '\\\\'"},
+		{`1 <= Int64Field <= 10`, SQLDialectANSI, `"Int64Field" BETWEEN 1 AND 10`},
+		{`1 < DoubleField <= 10`, SQLDialectANSI, `("DoubleField" > 1 AND "DoubleField" <= 10)`},
+		{`Int64Field + 1 == 3`, SQLDialectANSI, `("Int64Field" + 1) = 3`},
+		{`Int64Field == Int32Field`, SQLDialectSQLite, `"Int64Field" = "Int32Field"`},
+		{`Int64Field is null`, SQLDialectANSI, `"Int64Field" IS NULL`},
+		{`JSONField["a"]["b"] != "x"`, SQLDialectPostgres, `("JSONField" #>> '{"a","b"}') <> 'x'`},
+		{`JSONField["a"] > 1`, SQLDialectPostgres, `("JSONField" #>> '{"a"}')::numeric > 1`},
+		{`JSONField["a"] in [1, 2.5]`, SQLDialectPostgres, `("JSONField" #>> '{"a"}')::numeric IN (1, 2.5)`},
+		{`1 < JSONField["a"] < 3`, SQLDialectPostgres, `(("JSONField" #>> '{"a"}')::numeric > 1 AND ("JSONField" #>> '{"a"}')::numeric < 3)`},
+		{`JSONField["a"] == true`, SQLDialectPostgres, `("JSONField" #>> '{"a"}')::boolean = TRUE`},
+		{`A + 1 == 2`, SQLDialectPostgres, `(("$meta" #>> '{"A"}')::numeric + 1) = 2`},
+		{`JSONField["a"][0] == 1`, SQLDialectMySQL, "JSON_UNQUOTE(JSON_EXTRACT(`JSONField`, '$.\"a\"[0]')) = 1"},
+		{`A == 1`, SQLDialectSQLite, `json_extract("$meta", '$."A"') = 1`},
+		{`exists JSONField["a"]`, SQLDialectANSI, `JSON_VALUE("JSONField", '$."a"') IS NOT NULL`},
+		{`ArrayField[0] == 1`, SQLDialectPostgres, `"ArrayField"[1] = 1`},
+		{`array_length(ArrayField) == 1`, SQLDialectSQLite, `json_array_length("ArrayField") = 1`},
+	}
+	for _, c := range cases {
+		expr, err := ParseExpr(helper, c.expr, nil)
+		require.NoError(t, err, c.expr)
+		sql, err := EmitSQL(helper, expr, c.dialect)
+		assert.NoError(t, err, c.expr)
+		assert.Equal(t, c.sql, sql, c.expr)
+	}
+
+	unsupported := []string{
+		`json_contains(JSONField, 1)`,
+		`random_sample(0.1)`,
+	}
+	for _, exprStr := range unsupported {
+		expr, err := ParseExpr(helper, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		_, err = EmitSQL(helper, expr, SQLDialectANSI)
+		assert.Error(t, err, exprStr)
+	}
+
+	expr, err := ParseExpr(helper, `array_length(ArrayField) == 1`, nil)
+	require.NoError(t, err)
+	_, err = EmitSQL(helper, expr, SQLDialectANSI)
+	assert.Error(t, err)
+
+	_, err = EmitSQL(helper, expr, SQLDialect(100))
+	assert.Error(t, err)
+
+	_, err = EmitSQL(helper, &planpb.Expr{
+		Expr: &planpb.Expr_TermExpr{
+			TermExpr: &planpb.TermExpr{ColumnInfo: &planpb.ColumnInfo{FieldId: 105}, TemplateVariableName: "ids"},
+		},
+		IsTemplate: true,
+	}, SQLDialectANSI)
+	assert.Error(t, err)
+}
@@ -0,0 +1,223 @@
+package planparserv2
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// Default selectivities used when no statistics are known for a field.
+const (
+	defaultEqualSelectivity = 0.1
+	defaultRangeSelectivity = 1.0 / 3
+	defaultOtherSelectivity = 0.5
+)
+
+// FieldStats are the statistics of a field used to estimate the selectivity of predicates.
+type FieldStats struct {
+	// NDV is the number of distinct values, 0 if unknown.
+	NDV int64
+	// Min and Max are the bounds of the values, nil if unknown.
+	Min, Max *planpb.GenericValue
+	// NullFraction is the fraction of null values.
+	NullFraction float64
+	// Histogram is the sorted upper bounds of equi-depth buckets, empty if unknown.
+	Histogram []*planpb.GenericValue
+}
+
+// StatsProvider provides the statistics of fields, such as the ones collected by the query coordinator.
+type StatsProvider interface {
+	// FieldStats returns the statistics of the field of the collection, and false if there are none.
+	FieldStats(collectionID int64, fieldID int64) (*FieldStats, bool)
+}
+
+var statsProvider atomic.Pointer[StatsProvider]
+
+// SetStatsProvider sets the provider of field statistics. A nil provider, the default, knows no statistics,
+// and the conjunctions of parsed expressions are only reordered by selectivity once a provider is set.
+func SetStatsProvider(provider StatsProvider) {
+	if provider == nil {
+		statsProvider.Store(nil)
+		return
+	}
+	statsProvider.Store(&provider)
+}
+
+func getFieldStats(collectionID int64, info *planpb.ColumnInfo) *FieldStats {
+	provider := statsProvider.Load()
+	if provider == nil || len(info.GetNestedPath()) != 0 {
+		return nil
+	}
+	stats, ok := (*provider).FieldStats(collectionID, info.GetFieldId())
+	if !ok {
+		return nil
+	}
+	return stats
+}
+
+// EstimateSelectivity estimates the fraction of entities matched by the expression, from the reported execution
+// feedback, the statistics of the StatsProvider, or from default selectivities if there are none.
+func EstimateSelectivity(schema *typeutil.SchemaHelper, expr *planpb.Expr) float64 {
+	if hasExecutionFeedback() {
+		if feedback, ok := GetExecutionFeedback(schema.GetCollectionID(), expr); ok {
+			return feedback.Selectivity
+		}
+	}
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_AlwaysTrueExpr:
+		return 1
+	case *planpb.Expr_UnaryExpr:
+		return 1 - EstimateSelectivity(schema, realExpr.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		left := EstimateSelectivity(schema, realExpr.BinaryExpr.GetLeft())
+		right := EstimateSelectivity(schema, realExpr.BinaryExpr.GetRight())
+		if realExpr.BinaryExpr.GetOp() == planpb.BinaryExpr_LogicalAnd {
+			return left * right
+		}
+		return left + right - left*right
+	case *planpb.Expr_TermExpr:
+		stats := getFieldStats(schema.GetCollectionID(), realExpr.TermExpr.GetColumnInfo())
+		n := float64(len(realExpr.TermExpr.GetValues()))
+		return notNull(stats, clampSelectivity(n*equalSelectivity(stats)))
+	case *planpb.Expr_UnaryRangeExpr:
+		e := realExpr.UnaryRangeExpr
+		stats := getFieldStats(schema.GetCollectionID(), e.GetColumnInfo())
+		switch e.GetOp() {
+		case planpb.OpType_Equal:
+			return notNull(stats, equalSelectivity(stats))
+		case planpb.OpType_NotEqual:
+			return notNull(stats, 1-equalSelectivity(stats))
+		case planpb.OpType_LessThan, planpb.OpType_LessEqual:
+			return notNull(stats, fractionBelow(stats, e.GetValue()))
+		case planpb.OpType_GreaterThan, planpb.OpType_GreaterEqual:
+			return notNull(stats, 1-fractionBelow(stats, e.GetValue()))
+		default:
+			return defaultOtherSelectivity
+		}
+	case *planpb.Expr_BinaryRangeExpr:
+		e := realExpr.BinaryRangeExpr
+		stats := getFieldStats(schema.GetCollectionID(), e.GetColumnInfo())
+		if stats == nil {
+			return defaultRangeSelectivity
+		}
+		return notNull(stats, clampSelectivity(fractionBelow(stats, e.GetUpperValue())-fractionBelow(stats, e.GetLowerValue())))
+	case *planpb.Expr_NullExpr:
+		stats := getFieldStats(schema.GetCollectionID(), realExpr.NullExpr.GetColumnInfo())
+		if stats == nil {
+			return defaultOtherSelectivity
+		}
+		if realExpr.NullExpr.GetOp() == planpb.NullExpr_IsNull {
+			return stats.NullFraction
+		}
+		return 1 - stats.NullFraction
+	default:
+		return defaultOtherSelectivity
+	}
+}
+
+func equalSelectivity(stats *FieldStats) float64 {
+	if stats == nil || stats.NDV <= 0 {
+		return defaultEqualSelectivity
+	}
+	return 1 / float64(stats.NDV)
+}
+
+// fractionBelow estimates the fraction of non-null values less than the value, from the histogram,
+// or by interpolating between the min and max.
+func fractionBelow(stats *FieldStats, value *planpb.GenericValue) float64 {
+	if stats == nil {
+		return defaultRangeSelectivity
+	}
+	if len(stats.Histogram) != 0 {
+		n := sort.Search(len(stats.Histogram), func(i int) bool {
+			return !valueIs(Less(stats.Histogram[i], value))
+		})
+		return float64(n) / float64(len(stats.Histogram))
+	}
+	v, ok := toFloat(value)
+	lower, lowerOk := toFloat(stats.Min)
+	upper, upperOk := toFloat(stats.Max)
+	if !ok || !lowerOk || !upperOk || upper <= lower {
+		return defaultRangeSelectivity
+	}
+	return clampSelectivity((v - lower) / (upper - lower))
+}
+
+func notNull(stats *FieldStats, selectivity float64) float64 {
+	if stats == nil {
+		return selectivity
+	}
+	return selectivity * (1 - stats.NullFraction)
+}
+
+func clampSelectivity(selectivity float64) float64 {
+	if selectivity < 0 {
+		return 0
+	}
+	if selectivity > 1 {
+		return 1
+	}
+	return selectivity
+}
+
+func toFloat(value *planpb.GenericValue) (float64, bool) {
+	switch {
+	case IsInteger(value):
+		return float64(value.GetInt64Val()), true
+	case IsFloating(value):
+		return value.GetFloatVal(), true
+	default:
+		return 0, false
+	}
+}
+
+// reorderConjunctions orders the operands of conjunctions by their estimated selectivity, so that the most
+// selective predicates are evaluated first. It does nothing until a StatsProvider is set or feedback is reported.
+func reorderConjunctions(schema *typeutil.SchemaHelper, expr *planpb.Expr) *planpb.Expr {
+	if statsProvider.Load() == nil && !hasExecutionFeedback() {
+		return expr
+	}
+	return reorderConjunction(schema, expr)
+}
+
+func reorderConjunction(schema *typeutil.SchemaHelper, expr *planpb.Expr) *planpb.Expr {
+	binary := expr.GetBinaryExpr()
+	if binary == nil {
+		return expr
+	}
+	if binary.GetOp() != planpb.BinaryExpr_LogicalAnd {
+		return &planpb.Expr{
+			Expr: &planpb.Expr_BinaryExpr{
+				BinaryExpr: &planpb.BinaryExpr{
+					Left:  reorderConjunction(schema, binary.GetLeft()),
+					Right: reorderConjunction(schema, binary.GetRight()),
+					Op:    binary.GetOp(),
+				},
+			},
+		}
+	}
+	conjuncts := flattenConjunction(expr)
+	selectivities := make(map[*planpb.Expr]float64, len(conjuncts))
+	for i, conjunct := range conjuncts {
+		conjuncts[i] = reorderConjunction(schema, conjunct)
+		selectivities[conjuncts[i]] = EstimateSelectivity(schema, conjuncts[i])
+	}
+	sort.SliceStable(conjuncts, func(i, j int) bool {
+		return selectivities[conjuncts[i]] < selectivities[conjuncts[j]]
+	})
+	ret := conjuncts[0]
+	for _, conjunct := range conjuncts[1:] {
+		ret = &planpb.Expr{
+			Expr: &planpb.Expr_BinaryExpr{
+				BinaryExpr: &planpb.BinaryExpr{
+					Left:  ret,
+					Right: conjunct,
+					Op:    planpb.BinaryExpr_LogicalAnd,
+				},
+			},
+		}
+	}
+	return ret
+}
@@ -0,0 +1,68 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+type mockStatsProvider map[int64]*FieldStats
+
+func (p mockStatsProvider) FieldStats(collectionID int64, fieldID int64) (*FieldStats, bool) {
+	stats, ok := p[fieldID]
+	return stats, ok
+}
+
+func TestEstimateSelectivity(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	estimate := func(exprStr string) float64 {
+		expr, err := ParseExpr(schema, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		return EstimateSelectivity(schema, expr)
+	}
+
+	assert.InDelta(t, 0.1, estimate(`Int64Field == 1`), 1e-9)
+	assert.InDelta(t, 0.9, estimate(`Int64Field != 1`), 1e-9)
+	assert.InDelta(t, 0.2, estimate(`Int64Field in [1, 2]`), 1e-9)
+	assert.InDelta(t, 0.01, estimate(`Int64Field == 1 and Int32Field == 1`), 1e-9)
+	assert.InDelta(t, 0.19, estimate(`Int64Field == 1 or Int32Field == 1`), 1e-9)
+	assert.InDelta(t, 1.0, estimate(``), 1e-9)
+
+	SetStatsProvider(mockStatsProvider{
+		105: {NDV: 1000, Min: NewInt(0), Max: NewInt(100), NullFraction: 0.5},
+		104: {Histogram: []*planpb.GenericValue{NewInt(10), NewInt(20), NewInt(30), NewInt(40)}},
+	})
+	defer SetStatsProvider(nil)
+
+	assert.InDelta(t, 0.0005, estimate(`Int64Field == 1`), 1e-9)
+	assert.InDelta(t, 0.125, estimate(`Int64Field < 25`), 1e-9)
+	assert.InDelta(t, 0.375, estimate(`Int64Field > 25`), 1e-9)
+	assert.InDelta(t, 0.25, estimate(`25 < Int64Field < 75`), 1e-9)
+	assert.InDelta(t, 0.5, estimate(`Int64Field is null`), 1e-9)
+	assert.InDelta(t, 0.5, estimate(`Int32Field < 25`), 1e-9)
+	assert.InDelta(t, 0.25, estimate(`Int32Field >= 40`), 1e-9)
+	assert.InDelta(t, 0.1, estimate(`Int16Field == 1`), 1e-9)
+}
+
+func TestParseExpr_ReorderConjunctions(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	exprStr := `Int32Field > 1 and (Int16Field == 1 or Int8Field == 1) and Int64Field == 1`
+
+	// conjunctions are kept as is without statistics.
+	expr, err := ParseExpr(schema, exprStr, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(105), expr.GetBinaryExpr().GetRight().GetUnaryRangeExpr().GetColumnInfo().GetFieldId())
+
+	SetStatsProvider(mockStatsProvider{105: {NDV: 1000}})
+	defer SetStatsProvider(nil)
+	expr, err = ParseExpr(schema, exprStr, nil)
+	require.NoError(t, err)
+	conjuncts := flattenConjunction(expr)
+	require.Len(t, conjuncts, 3)
+	assert.Equal(t, int64(105), conjuncts[0].GetUnaryRangeExpr().GetColumnInfo().GetFieldId())
+	assert.NotNil(t, conjuncts[1].GetBinaryExpr())
+	assert.Equal(t, int64(104), conjuncts[2].GetUnaryRangeExpr().GetColumnInfo().GetFieldId())
+}
@@ -0,0 +1,119 @@
+package planparserv2
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+
+	parser "github.com/milvus-io/milvus/internal/parser/planparserv2/generated"
+)
+
+// SyntaxReport is the result of checking an expression against the grammar only.
+type SyntaxReport struct {
+	// Diagnostics are the syntax errors of the expression, empty if it is well-formed.
+	Diagnostics []*SyntaxDiagnostic
+	// Identifiers are the distinct field names the expression references, in order of appearance,
+	// the field of a json path like meta["a"] is meta.
+	Identifiers []string
+	// Functions are the distinct names of the functions the expression calls.
+	Functions []string
+	// TemplateVariables are the distinct names of the template variables of the expression.
+	TemplateVariables []string
+}
+
+// SyntaxDiagnostic is a syntax error at the line and column of the expression.
+type SyntaxDiagnostic struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d *SyntaxDiagnostic) String() string {
+	return fmt.Sprintf("line %d:%d %s", d.Line, d.Column, d.Message)
+}
+
+type diagnosticListener struct {
+	*antlr.DefaultErrorListener
+	diagnostics []*SyntaxDiagnostic
+}
+
+func (l *diagnosticListener) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{}, line, column int, msg string, e antlr.RecognitionException) {
+	l.diagnostics = append(l.diagnostics, &SyntaxDiagnostic{Line: line, Column: column, Message: msg})
+}
+
+// ParseExprSyntaxOnly checks the expression against the grammar without a schema, so that clients can lint
+// filters before the collection exists. All the syntax errors are reported instead of the first one, and
+// neither the fields nor the types are checked. Macros need the collection, and are reported as syntax errors.
+func ParseExprSyntaxOnly(exprStr string) *SyntaxReport {
+	report := &SyntaxReport{}
+	_, exprStr, err := ParseHints(exprStr)
+	if err != nil {
+		report.Diagnostics = append(report.Diagnostics, &SyntaxDiagnostic{Line: 1, Message: err.Error()})
+		return report
+	}
+	if isEmptyExpression(exprStr) {
+		return report
+	}
+
+	listener := &diagnosticListener{DefaultErrorListener: antlr.NewDefaultErrorListener()}
+	lexer := parser.NewPlanLexer(antlr.NewInputStream(rewriteIsBoolean(convertUnicode(exprStr))))
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(listener)
+	planParser := parser.NewPlanParser(antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel))
+	planParser.RemoveErrorListeners()
+	planParser.AddErrorListener(listener)
+	planParser.BuildParseTrees = true
+
+	ast := planParser.Expr()
+	if token := planParser.GetCurrentToken(); token.GetTokenType() != antlr.TokenEOF && len(listener.diagnostics) == 0 {
+		listener.diagnostics = append(listener.diagnostics, &SyntaxDiagnostic{
+			Line:    token.GetLine(),
+			Column:  token.GetColumn(),
+			Message: fmt.Sprintf("extraneous input '%s' at the end of the expression", token.GetText()),
+		})
+	}
+	report.Diagnostics = listener.diagnostics
+	report.collect(ast)
+	return report
+}
+
+// collect records the identifiers of the syntax tree, which may be partial if there are syntax errors.
+func (r *SyntaxReport) collect(tree antlr.Tree) {
+	switch ctx := tree.(type) {
+	case *parser.CallContext:
+		if ctx.Identifier() != nil {
+			r.Functions = appendDistinct(r.Functions, strings.ToLower(ctx.Identifier().GetText()))
+		}
+		for _, expr := range ctx.AllExpr() {
+			r.collect(expr)
+		}
+		return
+	case *parser.TemplateVariableContext:
+		if ctx.Identifier() != nil {
+			r.TemplateVariables = appendDistinct(r.TemplateVariables, ctx.Identifier().GetText())
+		}
+		return
+	case antlr.TerminalNode:
+		switch ctx.GetSymbol().GetTokenType() {
+		case parser.PlanParserIdentifier, parser.PlanParserMeta:
+			r.Identifiers = appendDistinct(r.Identifiers, decodeUnicode(ctx.GetText()))
+		case parser.PlanParserJSONIdentifier:
+			if fieldName, _, err := splitJSONIdentifier(ctx.GetText()); err == nil {
+				r.Identifiers = appendDistinct(r.Identifiers, fieldName)
+			}
+		}
+		return
+	}
+	for _, child := range tree.GetChildren() {
+		r.collect(child)
+	}
+}
+
+func appendDistinct(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
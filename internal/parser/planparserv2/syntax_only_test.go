@@ -0,0 +1,45 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExprSyntaxOnly(t *testing.T) {
+	report := ParseExprSyntaxOnly(`/*+ iterative_filter */ age > 18 and meta["a"]["b"] in {ids} and json_contains(tags, "x") and ` +
+		`my_func(score, 1) < 2 and 1 < age < 99 and $meta["c"] == 1 and text_match(title, "milvus")`)
+	assert.Empty(t, report.Diagnostics)
+	assert.Equal(t, []string{"age", "meta", "tags", "score", "$meta", "title"}, report.Identifiers)
+	assert.Equal(t, []string{"my_func"}, report.Functions)
+	assert.Equal(t, []string{"ids"}, report.TemplateVariables)
+
+	report = ParseExprSyntaxOnly(`   `)
+	assert.Empty(t, report.Diagnostics)
+	assert.Empty(t, report.Identifiers)
+
+	// the fields aren't checked without a schema.
+	report = ParseExprSyntaxOnly(`unknown_field like "a%" and other_field is null`)
+	assert.Empty(t, report.Diagnostics)
+	assert.Equal(t, []string{"unknown_field", "other_field"}, report.Identifiers)
+
+	for _, exprStr := range []string{
+		`age >`,
+		`age > 1 )`,
+		`(age > 1`,
+		`age > 1 age`,
+		`/*+ iterative_filter age > 1`,
+		`@macro() and age > 1`,
+	} {
+		report = ParseExprSyntaxOnly(exprStr)
+		assert.NotEmpty(t, report.Diagnostics, exprStr)
+	}
+
+	// all the syntax errors are reported.
+	report = ParseExprSyntaxOnly("age > 1 ; and\nname == \"a\" ;")
+	require.Len(t, report.Diagnostics, 2)
+	assert.Equal(t, 1, report.Diagnostics[0].Line)
+	assert.Equal(t, 2, report.Diagnostics[1].Line)
+	assert.Contains(t, report.Identifiers, "age")
+}
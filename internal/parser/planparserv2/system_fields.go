@@ -0,0 +1,68 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// primaryKeyFunctionName is the function resolving to the primary key field of the collection, such as `pk() in [1, 2]`.
+const primaryKeyFunctionName = "pk"
+
+// systemFieldFunctions maps the functions reading system fields, such as `timestamp() > 456`, to the fields.
+var systemFieldFunctions = map[string]int64{
+	"timestamp": common.TimeStampField,
+	"row_id":    common.RowIDField,
+}
+
+// WithSystemFields allows filtering on the system fields with the functions timestamp() and row_id(),
+// which read the insert timestamp and the row id of entities.
+func WithSystemFields(enabled bool) ParseOption {
+	return func(options *parseOptions) {
+		options.systemFields = enabled
+	}
+}
+
+func systemFieldName(fieldID int64) (string, bool) {
+	switch fieldID {
+	case common.TimeStampField:
+		return common.TimeStampFieldName, true
+	case common.RowIDField:
+		return common.RowIDFieldName, true
+	default:
+		return "", false
+	}
+}
+
+func (v *ParserVisitor) translateSystemField(functionName string, fieldID int64, numParams int) (*ExprWithType, error) {
+	if numParams != 0 {
+		return nil, fmt.Errorf("function %s() doesn't take arguments", functionName)
+	}
+	return &ExprWithType{
+		expr: &planpb.Expr{
+			Expr: &planpb.Expr_ColumnExpr{
+				ColumnExpr: &planpb.ColumnExpr{
+					Info: &planpb.ColumnInfo{
+						FieldId:  fieldID,
+						DataType: schemapb.DataType_Int64,
+					},
+				},
+			},
+		},
+		dataType:      schemapb.DataType_Int64,
+		nodeDependent: true,
+	}, nil
+}
+
+func (v *ParserVisitor) translatePrimaryKey(numParams int) (*ExprWithType, error) {
+	if numParams != 0 {
+		return nil, fmt.Errorf("function %s() doesn't take arguments", primaryKeyFunctionName)
+	}
+	field, err := v.schema.GetPrimaryKeyField()
+	if err != nil {
+		return nil, err
+	}
+	return v.translateIdentifier(field.GetName())
+}
@@ -0,0 +1,76 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestParseExpr_SystemFields(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	enabled := WithSystemFields(true)
+
+	expr, err := ParseExpr(schema, `timestamp() > 456`, nil, enabled)
+	require.NoError(t, err)
+	unary := expr.GetUnaryRangeExpr()
+	require.NotNil(t, unary)
+	assert.Equal(t, int64(common.TimeStampField), unary.GetColumnInfo().GetFieldId())
+	assert.Equal(t, planpb.OpType_GreaterThan, unary.GetOp())
+	assert.Equal(t, int64(456), unary.GetValue().GetInt64Val())
+
+	expr, err = ParseExpr(schema, `100 <= ROW_ID() and Int64Field in [1, 2]`, nil, enabled)
+	require.NoError(t, err)
+	assert.Equal(t, int64(common.RowIDField), expr.GetBinaryExpr().GetLeft().GetUnaryRangeExpr().GetColumnInfo().GetFieldId())
+
+	report, err := ValidateExpr(schema, `timestamp() > 456 and Int64Field > 1`, enabled)
+	require.NoError(t, err)
+	require.Len(t, report.Fields, 2)
+	assert.Equal(t, common.TimeStampFieldName, report.Fields[0].FieldName)
+
+	for _, exprStr := range []string{
+		`timestamp(1) > 456`,
+		`timestamp() > "a"`,
+		`timestamp() like "a%"`,
+	} {
+		_, err := ParseExpr(schema, exprStr, nil, enabled)
+		assert.Error(t, err, exprStr)
+	}
+
+	// without the option, timestamp() is an ordinary function call.
+	_, err = ParseExpr(schema, `timestamp() > 456`, nil)
+	assert.Error(t, err)
+}
+
+func TestParseExpr_PrimaryKey(t *testing.T) {
+	schema := newTestSchema(true)
+	for _, field := range schema.Fields {
+		if field.GetName() == "VarCharField" {
+			field.IsPrimaryKey = true
+		}
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+
+	expr, err := ParseExpr(helper, `pk() in ["a", "b"]`, nil)
+	require.NoError(t, err)
+	term := expr.GetTermExpr()
+	require.NotNil(t, term)
+	assert.Equal(t, int64(121), term.GetColumnInfo().GetFieldId())
+	assert.True(t, term.GetColumnInfo().GetIsPrimaryKey())
+
+	expr, err = ParseExpr(helper, `PK() == "a" or pk() > "c"`, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetBinaryExpr())
+
+	_, err = ParseExpr(helper, `pk(VarCharField) == "a"`, nil)
+	assert.Error(t, err)
+	_, err = ParseExpr(helper, `pk() == 1`, nil)
+	assert.Error(t, err)
+	_, err = ParseExpr(newTestSchemaHelper(t), `pk() == 1`, nil)
+	assert.Error(t, err)
+}
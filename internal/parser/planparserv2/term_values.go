@@ -0,0 +1,72 @@
+package planparserv2
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// WithKeepTermValues keeps the values of `in` lists as written, instead of deduplicating and sorting them.
+func WithKeepTermValues(keep bool) ParseOption {
+	return func(options *parseOptions) {
+		options.keepTermValues = keep
+	}
+}
+
+// normalizeTermValues deduplicates and sorts the values of the term predicates, which makes plans smaller and
+// deterministic, and lets query nodes binary search the values. Lists mixing value types, which only JSON
+// fields take, and lists of arrays are left as is.
+func normalizeTermValues(expr *planpb.Expr) {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryExpr:
+		normalizeTermValues(e.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		normalizeTermValues(e.BinaryExpr.GetLeft())
+		normalizeTermValues(e.BinaryExpr.GetRight())
+	case *planpb.Expr_RandomSampleExpr:
+		normalizeTermValues(e.RandomSampleExpr.GetPredicate())
+	case *planpb.Expr_TermExpr:
+		if compare := termValueComparator(e.TermExpr.GetValues()); compare != nil {
+			values := slices.Clone(e.TermExpr.GetValues())
+			slices.SortStableFunc(values, compare)
+			e.TermExpr.Values = slices.CompactFunc(values, func(a, b *planpb.GenericValue) bool {
+				return compare(a, b) == 0
+			})
+		}
+	}
+}
+
+// termValueComparator returns the order of the values if they are of the same scalar type, or nil.
+func termValueComparator(values []*planpb.GenericValue) func(a, b *planpb.GenericValue) int {
+	if len(values) < 2 {
+		return nil
+	}
+	same := func(is func(*planpb.GenericValue) bool) bool {
+		for _, value := range values {
+			if !is(value) {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case same(IsInteger):
+		return func(a, b *planpb.GenericValue) int { return cmp.Compare(a.GetInt64Val(), b.GetInt64Val()) }
+	case same(IsFloating):
+		return func(a, b *planpb.GenericValue) int { return cmp.Compare(a.GetFloatVal(), b.GetFloatVal()) }
+	case same(IsString):
+		return func(a, b *planpb.GenericValue) int { return cmp.Compare(a.GetStringVal(), b.GetStringVal()) }
+	case same(IsBool):
+		return func(a, b *planpb.GenericValue) int {
+			if a.GetBoolVal() == b.GetBoolVal() {
+				return 0
+			} else if b.GetBoolVal() {
+				return -1
+			}
+			return 1
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestNormalizeTermValues(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+	values := func(expr *planpb.Expr) []interface{} {
+		ret := make([]interface{}, 0)
+		for _, value := range expr.GetTermExpr().GetValues() {
+			switch v := value.GetVal().(type) {
+			case *planpb.GenericValue_Int64Val:
+				ret = append(ret, v.Int64Val)
+			case *planpb.GenericValue_FloatVal:
+				ret = append(ret, v.FloatVal)
+			case *planpb.GenericValue_StringVal:
+				ret = append(ret, v.StringVal)
+			case *planpb.GenericValue_BoolVal:
+				ret = append(ret, v.BoolVal)
+			}
+		}
+		return ret
+	}
+
+	for exprStr, expected := range map[string][]interface{}{
+		`Int64Field in [3, 1, 2, 3, 1]`:        {int64(1), int64(2), int64(3)},
+		`DoubleField in [2.5, -1, 2.5]`:        {-1.0, 2.5},
+		`VarCharField in ["b", "a", "b", "c"]`: {"a", "b", "c"},
+		`BoolField in [true, false, true]`:     {false, true},
+		`$meta["a"] in [2, 1, 2]`:              {int64(1), int64(2)},
+		`Int64Field in [7]`:                    {int64(7)},
+	} {
+		expr, err := ParseExpr(schema, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		assert.Equal(t, expected, values(expr), exprStr)
+	}
+
+	expr, err := ParseExpr(schema, `Int8Field > 1 and Int64Field in {v}`, map[string]*schemapb.TemplateValue{
+		"v": generateTemplateValue(schemapb.DataType_Array, generateTemplateArrayValue(schemapb.DataType_Int64, []int64{5, 4, 5})),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(4), int64(5)}, values(expr.GetBinaryExpr().GetRight()))
+
+	expr, err = ParseExpr(schema, `Int64Field in [3, 1, 3]`, nil, WithKeepTermValues(true))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(3), int64(1), int64(3)}, values(expr))
+}
@@ -0,0 +1,58 @@
+package planparserv2
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// TextAnalyzer returns the tokens of the text, analyzed like the text of the field by the analyzer configured
+// in its analyzer_params.
+type TextAnalyzer func(ctx context.Context, field *schemapb.FieldSchema, text string) ([]string, error)
+
+var textAnalyzer atomic.Pointer[TextAnalyzer]
+
+// SetTextAnalyzer sets the hook used to check the queries of text_match and phrase_match when parsing, instead of
+// finding out at query time that they match nothing. A nil analyzer disables the check.
+func SetTextAnalyzer(analyzer TextAnalyzer) {
+	if analyzer == nil {
+		textAnalyzer.Store(nil)
+		return
+	}
+	textAnalyzer.Store(&analyzer)
+}
+
+// checkTextQuery analyzes the query of a text or phrase match, and warns if it has no token left, such as
+// a query made of stop words, since it can't match anything.
+func (v *ParserVisitor) checkTextQuery(op planpb.OpType, info *planpb.ColumnInfo, queryText string) error {
+	analyzer := textAnalyzer.Load()
+	if analyzer == nil {
+		return nil
+	}
+	field, err := v.schema.GetFieldFromID(info.GetFieldId())
+	if err != nil {
+		return err
+	}
+	tokens, err := (*analyzer)(v.options.ctx, field, queryText)
+	if err != nil {
+		return fmt.Errorf("cannot analyze the query of %s on field %s: %w", textMatchName(op), field.GetName(), err)
+	}
+	if len(tokens) == 0 && v.options.warnings != nil {
+		*v.options.warnings = append(*v.options.warnings, &ExprWarning{
+			Code: WarnCodeEmptyTextQuery,
+			Message: fmt.Sprintf("the query %s of %s on field %s has no token left after analysis, it matches nothing",
+				QuoteStringLiteral(queryText), textMatchName(op), field.GetName()),
+		})
+	}
+	return nil
+}
+
+func textMatchName(op planpb.OpType) string {
+	if op == planpb.OpType_PhraseMatch {
+		return "phrase_match"
+	}
+	return "text_match"
+}
@@ -0,0 +1,68 @@
+package planparserv2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestSetTextAnalyzer(t *testing.T) {
+	schema := newTestSchema(true)
+	enableMatch(schema)
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+
+	var analyzed []string
+	SetTextAnalyzer(func(ctx context.Context, field *schemapb.FieldSchema, text string) ([]string, error) {
+		analyzed = append(analyzed, field.GetName())
+		if text == "broken" {
+			return nil, fmt.Errorf("analyzer failure")
+		}
+		var tokens []string
+		for _, token := range strings.Fields(text) {
+			if token != "the" && token != "a" {
+				tokens = append(tokens, token)
+			}
+		}
+		return tokens, nil
+	})
+	defer SetTextAnalyzer(nil)
+
+	var warnings []*ExprWarning
+	_, err = ParseExpr(helper, `text_match(VarCharField, "the cat")`, nil, WithWarnings(&warnings))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, []string{"VarCharField"}, analyzed)
+
+	_, err = ParseExpr(helper, `text_match(VarCharField, "the a") or phrase_match(VarCharField, "the", 1)`, nil, WithWarnings(&warnings))
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+	assert.Equal(t, WarnCodeEmptyTextQuery, warnings[0].Code)
+	assert.Contains(t, warnings[0].Message, "text_match on field VarCharField")
+	assert.Contains(t, warnings[1].Message, "phrase_match")
+
+	// warnings are reset by each parse.
+	_, err = ParseExpr(helper, `Int64Field > 1`, nil, WithWarnings(&warnings))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	// the empty queries are accepted without the option.
+	_, err = ParseExpr(helper, `text_match(VarCharField, "the")`, nil)
+	assert.NoError(t, err)
+
+	_, err = ParseExpr(helper, `text_match(VarCharField, "broken")`, nil)
+	assert.ErrorContains(t, err, "cannot analyze the query of text_match on field VarCharField")
+
+	SetTextAnalyzer(nil)
+	analyzed = nil
+	_, err = ParseExpr(helper, `text_match(VarCharField, "broken")`, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, analyzed)
+}
@@ -0,0 +1,181 @@
+package planparserv2
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// TimeUnitKey is the type param tagging an integer field as a unix epoch time in seconds, milliseconds or
+// microseconds. The times compared with such fields, timestamp strings like "2024-01-02T15:04:05Z", now() and
+// interval("1h"), are converted to the unit of the field.
+const TimeUnitKey = "time_unit"
+
+const (
+	TimeUnitSeconds = "seconds"
+	TimeUnitMillis  = "millis"
+	TimeUnitMicros  = "micros"
+)
+
+var timeUnits = map[string]time.Duration{
+	TimeUnitSeconds: time.Second,
+	TimeUnitMillis:  time.Millisecond,
+	TimeUnitMicros:  time.Microsecond,
+}
+
+const (
+	nowFunctionName      = "now"
+	intervalFunctionName = "interval"
+)
+
+// timestampLayouts are the layouts of the timestamp strings, dates are at midnight UTC.
+var timestampLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"}
+
+// translateTimeFunction translates now(), the time of the request, and interval("1h30m"), a duration,
+// into values in milliseconds which are converted to the unit of the fields they are compared with.
+// Sums and differences of such values, like `now() - interval("1d")`, are converted too.
+func (v *ParserVisitor) translateTimeFunction(functionName string, params []*ExprWithType) (*ExprWithType, error) {
+	var millis int64
+	switch functionName {
+	case nowFunctionName:
+		if len(params) != 0 {
+			return nil, fmt.Errorf("function %s() doesn't take arguments", functionName)
+		}
+		if v.options.requestContext == nil || v.options.requestContext.Time.IsZero() {
+			return nil, fmt.Errorf("function %s() is not available without the time of the request", functionName)
+		}
+		millis = v.options.requestContext.Time.UnixMilli()
+	case intervalFunctionName:
+		if len(params) != 1 || !IsString(params[0].expr.GetValueExpr().GetValue()) {
+			return nil, fmt.Errorf("function %s() takes a duration string, like \"1h30m\"", functionName)
+		}
+		duration, err := parseInterval(params[0].expr.GetValueExpr().GetValue().GetStringVal())
+		if err != nil {
+			return nil, err
+		}
+		millis = duration.Milliseconds()
+	}
+	ret := toValueExpr(NewInt(millis))
+	ret.timeValue = true
+	return ret, nil
+}
+
+// parseInterval parses durations like time.ParseDuration, which also accept days, like "7d".
+func parseInterval(s string) (time.Duration, error) {
+	var days time.Duration
+	if before, after, ok := strings.Cut(s, "d"); ok {
+		var n int64
+		if _, err := fmt.Sscanf(before, "%d", &n); err != nil || fmt.Sprint(n) != before {
+			return 0, fmt.Errorf("invalid interval: %s", s)
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		if s = after; s == "" {
+			return days, nil
+		}
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval: %w", err)
+	}
+	return days + duration, nil
+}
+
+// fieldTimeUnit returns the unit of the field of the column, if it's tagged with one.
+func (v *ParserVisitor) fieldTimeUnit(info *planpb.ColumnInfo) (time.Duration, bool, error) {
+	if info == nil || len(info.GetNestedPath()) != 0 || !typeutil.IsIntegerType(info.GetDataType()) {
+		return 0, false, nil
+	}
+	field, err := v.schema.GetFieldFromID(info.GetFieldId())
+	if err != nil {
+		return 0, false, nil
+	}
+	unitName, ok := funcutil.KeyValuePair2Map(field.GetTypeParams())[TimeUnitKey]
+	if !ok {
+		return 0, false, nil
+	}
+	unit, ok := timeUnits[strings.ToLower(unitName)]
+	if !ok {
+		return 0, false, fmt.Errorf("invalid %s of field %s: %s, must be one of %s, %s or %s",
+			TimeUnitKey, field.GetName(), unitName, TimeUnitSeconds, TimeUnitMillis, TimeUnitMicros)
+	}
+	return unit, true, nil
+}
+
+// convertTimeValue converts a time compared with the column to the unit of its field. The conversion of times
+// which aren't a whole number of units rounds up if roundUp is set, so that the comparison keeps its meaning:
+// `seconds >= 1500ms` is `seconds >= 2` while `seconds > 1500ms` is `seconds > 1`.
+func (v *ParserVisitor) convertTimeValue(info *planpb.ColumnInfo, operand interface{}, roundUp bool) (interface{}, error) {
+	valueExpr := getValueExpr(operand)
+	if valueExpr == nil || isTemplateExpr(valueExpr) {
+		return operand, nil
+	}
+	unit, ok, err := v.fieldTimeUnit(info)
+	if err != nil || !ok {
+		return operand, err
+	}
+	var nanos int64
+	switch value := valueExpr.GetValue(); {
+	case getExpr(operand).timeValue:
+		millis := value.GetInt64Val()
+		if millis > math.MaxInt64/int64(time.Millisecond) || millis < math.MinInt64/int64(time.Millisecond) {
+			return nil, fmt.Errorf("time out of range: %d ms", millis)
+		}
+		nanos = millis * int64(time.Millisecond)
+	case IsString(value):
+		t, err := parseTimestamp(value.GetStringVal())
+		if err != nil {
+			return nil, err
+		}
+		nanos = t.UnixNano()
+	default:
+		return operand, nil
+	}
+	converted := nanos / int64(unit)
+	if remainder := nanos % int64(unit); remainder != 0 && (remainder > 0) == roundUp {
+		if roundUp {
+			converted++
+		} else {
+			converted--
+		}
+	}
+	return toValueExpr(NewInt(converted)), nil
+}
+
+// convertTimeOperands converts the time compared with a column, on either side of the operator op,
+// to the unit of its field.
+func (v *ParserVisitor) convertTimeOperands(left, right interface{}, op planpb.OpType) (interface{}, interface{}, error) {
+	var err error
+	if info := columnInfoOf(left); info != nil {
+		right, err = v.convertTimeValue(info, right, op == planpb.OpType_GreaterEqual || op == planpb.OpType_LessThan)
+		return left, right, err
+	}
+	if info := columnInfoOf(right); info != nil {
+		left, err = v.convertTimeValue(info, left, op == planpb.OpType_LessEqual || op == planpb.OpType_GreaterThan)
+		return left, right, err
+	}
+	return left, right, nil
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			if t.Year() < 1678 || t.Year() > 2261 {
+				return time.Time{}, fmt.Errorf("timestamp out of range: %s", s)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp: %s, expected a time like \"2006-01-02T15:04:05Z\" or a date like \"2006-01-02\"", s)
+}
+
+func columnInfoOf(operand interface{}) *planpb.ColumnInfo {
+	if expr := getExpr(operand); expr != nil {
+		return expr.expr.GetColumnExpr().GetInfo()
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package planparserv2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func newTimeUnitSchemaHelper(t *testing.T) *typeutil.SchemaHelper {
+	timeField := func(id int64, name string, unit string) *schemapb.FieldSchema {
+		return &schemapb.FieldSchema{
+			FieldID:    id,
+			Name:       name,
+			DataType:   schemapb.DataType_Int64,
+			TypeParams: []*commonpb.KeyValuePair{{Key: TimeUnitKey, Value: unit}},
+		}
+	}
+	schema := &schemapb.CollectionSchema{
+		Name: "time_unit",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			timeField(101, "created_s", TimeUnitSeconds),
+			timeField(102, "created_ms", TimeUnitMillis),
+			timeField(103, "created_us", TimeUnitMicros),
+			timeField(104, "created_h", "hours"),
+		},
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+	return helper
+}
+
+func TestTimeUnits(t *testing.T) {
+	helper := newTimeUnitSchemaHelper(t)
+	now := time.UnixMilli(1_700_000_000_500)
+	opt := WithRequestContext(RequestContext{Time: now})
+
+	value := func(exprStr string) int64 {
+		expr, err := ParseExpr(helper, exprStr, nil, opt)
+		require.NoError(t, err, exprStr)
+		return expr.GetUnaryRangeExpr().GetValue().GetInt64Val()
+	}
+	// timestamp strings and now() are converted to the unit of the field.
+	assert.Equal(t, int64(1704067200), value(`created_s >= "2024-01-01T00:00:00Z"`))
+	assert.Equal(t, int64(1704067200000), value(`created_ms == "2024-01-01"`))
+	assert.Equal(t, int64(1704067200000000), value(`created_us < "2024-01-01T00:00:00Z"`))
+	assert.Equal(t, int64(1_700_000_000_500), value(`created_ms > now()`))
+	assert.Equal(t, int64(1_700_000_000_500_000), value(`created_us > now()`))
+	assert.Equal(t, int64(1_700_000_000_500-3_600_000), value(`created_ms > now() - interval("1h")`))
+	assert.Equal(t, int64(1_700_000_000-7*86400), value(`created_s > now() - interval("7d")`))
+
+	// times between two units are rounded so that comparisons keep their meaning.
+	assert.Equal(t, int64(1_700_000_000), value(`created_s > now()`))
+	assert.Equal(t, int64(1_700_000_001), value(`created_s >= now()`))
+	assert.Equal(t, int64(1_700_000_001), value(`created_s < now()`))
+	assert.Equal(t, int64(1_700_000_000), value(`created_s <= now()`))
+	assert.Equal(t, int64(1_700_000_001), value(`now() <= created_s`))
+
+	expr, err := ParseExpr(helper, `now() - interval("1d") <= created_s < now()`, nil, opt)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1_700_000_001-86400), expr.GetBinaryRangeExpr().GetLowerValue().GetInt64Val())
+	assert.Equal(t, int64(1_700_000_001), expr.GetBinaryRangeExpr().GetUpperValue().GetInt64Val())
+	expr, err = ParseExpr(helper, `now() > created_s > "2023-11-14"`, nil, opt)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1699920000), expr.GetBinaryRangeExpr().GetLowerValue().GetInt64Val())
+	assert.Equal(t, int64(1_700_000_001), expr.GetBinaryRangeExpr().GetUpperValue().GetInt64Val())
+
+	// plain numbers are in the unit of the field already.
+	assert.Equal(t, int64(1700000000), value(`created_s > 1700000000`))
+	assert.Equal(t, int64(1_700_000_000_500), value(`pk > request_time()`))
+
+	invalid := []string{
+		`created_s > "yesterday"`,
+		`created_h > now()`,
+		`created_s > interval("soon")`,
+		`created_s > interval(1)`,
+		`created_s > now(1)`,
+	}
+	for _, exprStr := range invalid {
+		_, err := ParseExpr(helper, exprStr, nil, opt)
+		assert.Error(t, err, exprStr)
+	}
+	_, err = ParseExpr(helper, `created_s > now()`, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package planparserv2
+
+import (
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// IsTriviallyTrue returns true if the predicate provably matches every entity, such as `not (x in [])`.
+func IsTriviallyTrue(expr *planpb.Expr) bool {
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_AlwaysTrueExpr:
+		return true
+	case *planpb.Expr_ValueExpr:
+		return realExpr.ValueExpr.GetValue().GetBoolVal()
+	case *planpb.Expr_UnaryExpr:
+		return realExpr.UnaryExpr.GetOp() == planpb.UnaryExpr_Not && IsTriviallyFalse(realExpr.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		left, right := realExpr.BinaryExpr.GetLeft(), realExpr.BinaryExpr.GetRight()
+		if realExpr.BinaryExpr.GetOp() == planpb.BinaryExpr_LogicalAnd {
+			return IsTriviallyTrue(left) && IsTriviallyTrue(right)
+		}
+		return IsTriviallyTrue(left) || IsTriviallyTrue(right)
+	default:
+		return false
+	}
+}
+
+// IsTriviallyFalse returns true if the predicate provably matches no entity, such as `x in []` or `5 < x < 3`.
+// Queries with such predicates don't need to be dispatched to query nodes.
+func IsTriviallyFalse(expr *planpb.Expr) bool {
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_ValueExpr:
+		value := realExpr.ValueExpr.GetValue()
+		return value.GetVal() != nil && IsBool(value) && !value.GetBoolVal()
+	case *planpb.Expr_UnaryExpr:
+		return realExpr.UnaryExpr.GetOp() == planpb.UnaryExpr_Not && IsTriviallyTrue(realExpr.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		left, right := realExpr.BinaryExpr.GetLeft(), realExpr.BinaryExpr.GetRight()
+		if realExpr.BinaryExpr.GetOp() == planpb.BinaryExpr_LogicalAnd {
+			return IsTriviallyFalse(left) || IsTriviallyFalse(right)
+		}
+		return IsTriviallyFalse(left) && IsTriviallyFalse(right)
+	case *planpb.Expr_TermExpr:
+		// the values of template variables are nil until filled, possibly with an empty list.
+		values := realExpr.TermExpr.GetValues()
+		return len(values) == 0 && (realExpr.TermExpr.GetTemplateVariableName() == "" || values != nil)
+	case *planpb.Expr_BinaryRangeExpr:
+		return isEmptyRange(realExpr.BinaryRangeExpr)
+	default:
+		return false
+	}
+}
+
+func isEmptyRange(expr *planpb.BinaryRangeExpr) bool {
+	lower, upper := expr.GetLowerValue(), expr.GetUpperValue()
+	if lower.GetVal() == nil || upper.GetVal() == nil {
+		return false
+	}
+	if expr.GetLowerInclusive() && expr.GetUpperInclusive() {
+		return valueIs(Less(upper, lower))
+	}
+	return valueIs(LessEqual(upper, lower))
+}
@@ -0,0 +1,53 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestIsTriviallyTrueOrFalse(t *testing.T) {
+	schema := newTestSchemaHelper(t)
+
+	for exprStr, expected := range map[string][2]bool{
+		``:                                         {true, false},
+		`Int64Field in []`:                         {false, true},
+		`Int64Field not in []`:                     {true, false},
+		`not (Int64Field in [])`:                   {true, false},
+		`Int64Field > 1 and Int64Field in []`:      {false, true},
+		`Int64Field > 1 or Int64Field in []`:       {false, false},
+		`Int64Field in [] or VarCharField in []`:   {false, true},
+		`Int64Field > 1 or not (Int64Field in [])`: {true, false},
+		`3 <= Int64Field <= 3`:                     {false, false},
+		`Int64Field > 1`:                           {false, false},
+	} {
+		expr, err := ParseExpr(schema, exprStr, nil)
+		require.NoError(t, err, exprStr)
+		assert.Equal(t, expected[0], IsTriviallyTrue(expr), exprStr)
+		assert.Equal(t, expected[1], IsTriviallyFalse(expr), exprStr)
+	}
+
+	assert.True(t, IsTriviallyTrue(&planpb.Expr{Expr: &planpb.Expr_ValueExpr{ValueExpr: &planpb.ValueExpr{Value: NewBool(true)}}}))
+	assert.True(t, IsTriviallyFalse(&planpb.Expr{Expr: &planpb.Expr_ValueExpr{ValueExpr: &planpb.ValueExpr{Value: NewBool(false)}}}))
+	assert.False(t, IsTriviallyFalse(&planpb.Expr{Expr: &planpb.Expr_ValueExpr{ValueExpr: &planpb.ValueExpr{}}}))
+	assert.False(t, IsTriviallyTrue(nil))
+	assert.False(t, IsTriviallyFalse(nil))
+
+	binaryRange := func(lower, upper *planpb.GenericValue, lowerInclusive, upperInclusive bool) *planpb.Expr {
+		return &planpb.Expr{Expr: &planpb.Expr_BinaryRangeExpr{BinaryRangeExpr: &planpb.BinaryRangeExpr{
+			LowerValue: lower, UpperValue: upper, LowerInclusive: lowerInclusive, UpperInclusive: upperInclusive,
+		}}}
+	}
+	assert.True(t, IsTriviallyFalse(binaryRange(NewInt(5), NewInt(3), true, true)))
+	assert.True(t, IsTriviallyFalse(binaryRange(NewInt(3), NewInt(3), false, true)))
+	assert.True(t, IsTriviallyFalse(binaryRange(NewString("b"), NewString("a"), true, true)))
+	assert.False(t, IsTriviallyFalse(binaryRange(NewInt(3), NewInt(3), true, true)))
+	assert.False(t, IsTriviallyFalse(binaryRange(NewInt(3), NewFloat(3.5), true, false)))
+	assert.False(t, IsTriviallyFalse(binaryRange(nil, NewInt(3), true, true)))
+
+	// unfilled templates are not known to be empty.
+	assert.False(t, IsTriviallyFalse(&planpb.Expr{Expr: &planpb.Expr_TermExpr{TermExpr: &planpb.TermExpr{TemplateVariableName: "v"}}}))
+}
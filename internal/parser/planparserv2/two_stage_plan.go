@@ -0,0 +1,64 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// TwoStageSearchPlan is a coarse-then-refine search. The coarse stage searches a cheap vector field, such as
+// a binary or quantized one, for candidates, which the refine stage searches again on a full-precision
+// vector field along with the filter.
+type TwoStageSearchPlan struct {
+	Coarse  *planpb.PlanNode
+	refine  *planpb.PlanNode
+	pkField *schemapb.FieldSchema
+}
+
+// CreateTwoStageSearchPlan creates the plans of a two-stage search. The coarse stage returns coarseQueryInfo.Topk
+// candidates, and the refine stage, created by Refine, keeps refineQueryInfo.Topk of them.
+func CreateTwoStageSearchPlan(schema *typeutil.SchemaHelper, exprStr string,
+	coarseFieldName string, coarseQueryInfo *planpb.QueryInfo,
+	refineFieldName string, refineQueryInfo *planpb.QueryInfo,
+	exprTemplateValues map[string]*schemapb.TemplateValue, opts ...ParseOption,
+) (*TwoStageSearchPlan, error) {
+	if coarseQueryInfo.GetTopk() < refineQueryInfo.GetTopk() {
+		return nil, fmt.Errorf("topk of the coarse stage %d is less than topk of the refine stage %d",
+			coarseQueryInfo.GetTopk(), refineQueryInfo.GetTopk())
+	}
+	pkField, err := schema.GetPrimaryKeyField()
+	if err != nil {
+		return nil, err
+	}
+	coarse, err := CreateSearchPlan(schema, "", coarseFieldName, coarseQueryInfo, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	refine, err := CreateSearchPlan(schema, exprStr, refineFieldName, refineQueryInfo, exprTemplateValues, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &TwoStageSearchPlan{Coarse: coarse, refine: refine, pkField: pkField}, nil
+}
+
+// Refine returns the plan of the refine stage, which only searches the candidates returned by the coarse stage.
+func (p *TwoStageSearchPlan) Refine(candidates *schemapb.IDs) *planpb.PlanNode {
+	plan := typeutil.Clone(p.refine)
+	anns := plan.GetVectorAnns()
+	predicate := newPrimaryKeyTermExpr(p.pkField, candidates)
+	if anns.GetPredicates() != nil {
+		predicate = &planpb.Expr{
+			Expr: &planpb.Expr_BinaryExpr{
+				BinaryExpr: &planpb.BinaryExpr{
+					Left:  predicate,
+					Right: anns.GetPredicates(),
+					Op:    planpb.BinaryExpr_LogicalAnd,
+				},
+			},
+		}
+	}
+	anns.Predicates = predicate
+	return plan
+}
@@ -0,0 +1,65 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestCreateTwoStageSearchPlan(t *testing.T) {
+	schema := newTestSchema(true)
+	for _, field := range schema.Fields {
+		if field.GetName() == "Int64Field" {
+			field.IsPrimaryKey = true
+		}
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+
+	plan, err := CreateTwoStageSearchPlan(helper, `Int32Field > 1`,
+		"BinaryVectorField", &planpb.QueryInfo{Topk: 100, MetricType: "HAMMING"},
+		"FloatVectorField", &planpb.QueryInfo{Topk: 10, MetricType: "L2"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, planpb.VectorType_BinaryVector, plan.Coarse.GetVectorAnns().GetVectorType())
+	assert.Nil(t, plan.Coarse.GetVectorAnns().GetPredicates())
+	assert.Equal(t, int64(100), plan.Coarse.GetVectorAnns().GetQueryInfo().GetTopk())
+
+	refine := plan.Refine(&schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{1, 2, 3}}}})
+	anns := refine.GetVectorAnns()
+	assert.Equal(t, planpb.VectorType_FloatVector, anns.GetVectorType())
+	assert.Equal(t, int64(10), anns.GetQueryInfo().GetTopk())
+	binary := anns.GetPredicates().GetBinaryExpr()
+	require.NotNil(t, binary)
+	assert.Equal(t, planpb.BinaryExpr_LogicalAnd, binary.GetOp())
+	assert.True(t, binary.GetLeft().GetTermExpr().GetColumnInfo().GetIsPrimaryKey())
+	assert.Len(t, binary.GetLeft().GetTermExpr().GetValues(), 3)
+	assert.NotNil(t, binary.GetRight().GetUnaryRangeExpr())
+
+	// refine plans don't share candidates.
+	refine = plan.Refine(&schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{4}}}})
+	assert.Len(t, refine.GetVectorAnns().GetPredicates().GetBinaryExpr().GetLeft().GetTermExpr().GetValues(), 1)
+
+	plan, err = CreateTwoStageSearchPlan(helper, ``,
+		"BinaryVectorField", &planpb.QueryInfo{Topk: 100}, "FloatVectorField", &planpb.QueryInfo{Topk: 10}, nil)
+	require.NoError(t, err)
+	refine = plan.Refine(&schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{4}}}})
+	assert.NotNil(t, refine.GetVectorAnns().GetPredicates().GetTermExpr())
+
+	_, err = CreateTwoStageSearchPlan(helper, ``,
+		"BinaryVectorField", &planpb.QueryInfo{Topk: 5}, "FloatVectorField", &planpb.QueryInfo{Topk: 10}, nil)
+	assert.Error(t, err)
+	_, err = CreateTwoStageSearchPlan(helper, `Int32Field >`,
+		"BinaryVectorField", &planpb.QueryInfo{Topk: 100}, "FloatVectorField", &planpb.QueryInfo{Topk: 10}, nil)
+	assert.Error(t, err)
+	_, err = CreateTwoStageSearchPlan(helper, ``,
+		"Int64Field", &planpb.QueryInfo{Topk: 100}, "FloatVectorField", &planpb.QueryInfo{Topk: 10}, nil)
+	assert.Error(t, err)
+	_, err = CreateTwoStageSearchPlan(newTestSchemaHelper(t), ``,
+		"BinaryVectorField", &planpb.QueryInfo{Topk: 100}, "FloatVectorField", &planpb.QueryInfo{Topk: 10}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package planparserv2
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// UnicodeConversion is the strategy of converting non-ASCII characters of expressions before lexing.
+type UnicodeConversion int32
+
+const (
+	// UnicodeConversionHan escapes Han characters only, which is the legacy behavior.
+	UnicodeConversionHan UnicodeConversion = iota
+	// UnicodeConversionFull escapes all non-ASCII characters, of any script, as universal character names.
+	UnicodeConversionFull
+	// UnicodeConversionNative passes expressions to the lexer as is, which reads them as runes.
+	UnicodeConversionNative
+)
+
+var unicodeConversion atomic.Int32
+
+// ParseUnicodeConversion parses the name of a conversion strategy, which is han, full or native.
+// off is accepted as an alias of native.
+func ParseUnicodeConversion(name string) (UnicodeConversion, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "han":
+		return UnicodeConversionHan, nil
+	case "full":
+		return UnicodeConversionFull, nil
+	case "native", "off":
+		return UnicodeConversionNative, nil
+	default:
+		return UnicodeConversionHan, fmt.Errorf("unknown unicode conversion %s, expected han, full or native", name)
+	}
+}
+
+// SetUnicodeConversion sets the strategy of converting non-ASCII characters of expressions.
+// The cached syntax trees are purged, since they depend on the strategy.
+func SetUnicodeConversion(conversion UnicodeConversion) {
+	if UnicodeConversion(unicodeConversion.Swap(int32(conversion))) != conversion {
+		exprCache.Purge()
+	}
+}
+
+// convertUnicode converts the non-ASCII characters of s by the configured strategy.
+func convertUnicode(s string) string {
+	switch UnicodeConversion(unicodeConversion.Load()) {
+	case UnicodeConversionFull:
+		return escapeRunes(s, func(r rune) bool { return r > unicode.MaxASCII })
+	case UnicodeConversionNative:
+		return s
+	default:
+		return convertHanToASCII(s)
+	}
+}
@@ -0,0 +1,56 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUnicodeConversion(t *testing.T) {
+	for name, expected := range map[string]UnicodeConversion{
+		"":       UnicodeConversionHan,
+		"han":    UnicodeConversionHan,
+		"Full":   UnicodeConversionFull,
+		"native": UnicodeConversionNative,
+		" off ":  UnicodeConversionNative,
+	} {
+		conversion, err := ParseUnicodeConversion(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, conversion, name)
+	}
+	_, err := ParseUnicodeConversion("ascii")
+	assert.Error(t, err)
+}
+
+func TestConvertUnicode(t *testing.T) {
+	defer SetUnicodeConversion(UnicodeConversionHan)
+
+	source := `A in ["中国", "한국", "Привет", "😀", "\x41中"]`
+	for conversion, expected := range map[UnicodeConversion]string{
+		UnicodeConversionHan:    `A in ["\u4e2d\u56fd", "한국", "Привет", "😀", "\x41\u4e2d"]`,
+		UnicodeConversionFull:   `A in ["\u4e2d\u56fd", "\ud55c\uad6d", "\u041f\u0440\u0438\u0432\u0435\u0442", "\U0001f600", "\x41\u4e2d"]`,
+		UnicodeConversionNative: source,
+	} {
+		SetUnicodeConversion(conversion)
+		assert.Equal(t, expected, convertUnicode(source), conversion)
+	}
+}
+
+func TestUnicodeConversion_ParseExpr(t *testing.T) {
+	defer SetUnicodeConversion(UnicodeConversionHan)
+	schema := newTestSchemaHelper(t)
+
+	for _, conversion := range []UnicodeConversion{UnicodeConversionHan, UnicodeConversionFull, UnicodeConversionNative} {
+		SetUnicodeConversion(conversion)
+		for _, value := range []string{"中国", "한국", "Привет", "😀", "mixed 中 한 П 😀"} {
+			expr, err := ParseExpr(schema, `VarCharField == "`+value+`"`, nil)
+			require.NoError(t, err, conversion)
+			assert.Equal(t, value, expr.GetUnaryRangeExpr().GetValue().GetStringVal(), conversion)
+
+			expr, err = ParseExpr(schema, `$meta["`+value+`"] > 1`, nil)
+			require.NoError(t, err, conversion)
+			assert.Equal(t, []string{value}, expr.GetUnaryRangeExpr().GetColumnInfo().GetNestedPath(), conversion)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package planparserv2
 
 import (
 	"fmt"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
@@ -239,6 +240,9 @@ func castValue(dataType schemapb.DataType, value *planpb.GenericValue) (*planpb.
 	if typeutil.IsIntegerType(dataType) && IsInteger(value) {
 		return value, nil
 	}
+	if typeutil.IsIntegerType(dataType) && isBeyondInt64(value) {
+		return nil, fmt.Errorf("integer %s is out of the range of %s", big.NewFloat(value.GetFloatVal()).Text('f', 0), dataType.String())
+	}
 
 	return nil, fmt.Errorf("cannot cast value to %s, value: %s", dataType.String(), value)
 }
@@ -603,10 +607,6 @@ func isIntegerColumn(col *planpb.ColumnInfo) bool {
 	return canConvertToIntegerType(col.GetDataType(), col.GetElementType())
 }
 
-func isEscapeCh(ch uint8) bool {
-	return ch == '\\' || ch == 'n' || ch == 't' || ch == 'r' || ch == 'f' || ch == '"' || ch == '\''
-}
-
 func formatUnicode(r uint32) string {
 	return string([]byte{
 		'\\', 'u',
@@ -617,6 +617,10 @@ func formatUnicode(r uint32) string {
 	})
 }
 
+func formatUnicode32(r uint32) string {
+	return `\U` + formatUnicode(r >> 16)[2:] + formatUnicode(r)[2:]
+}
+
 func hexDigit(n uint32) byte {
 	n &= 0xf
 	if n < 10 {
@@ -734,29 +738,34 @@ func parseJSONValue(value interface{}) (*planpb.GenericValue, schemapb.DataType,
 }
 
 func convertHanToASCII(s string) string {
+	return escapeRunes(s, func(r rune) bool { return unicode.Is(unicode.Han, r) })
+}
+
+// escapeRunes replaces the runes matching needEscape by universal character names. Escape sequences are
+// copied as is, even if they are invalid, which the lexer reports.
+func escapeRunes(s string, needEscape func(r rune) bool) string {
 	var builder strings.Builder
 	builder.Grow(len(s) * 6)
 	skipCur := false
-	n := len(s)
-	for i, r := range s {
+	for _, r := range s {
 		if skipCur {
 			builder.WriteRune(r)
 			skipCur = false
 			continue
 		}
 		if r == '\\' {
-			if i+1 < n && !isEscapeCh(s[i+1]) {
-				return s
-			}
 			skipCur = true
 			builder.WriteRune(r)
 			continue
 		}
 
-		if unicode.Is(unicode.Han, r) {
-			builder.WriteString(formatUnicode(uint32(r)))
-		} else {
+		switch {
+		case !needEscape(r):
 			builder.WriteRune(r)
+		case r > 0xffff:
+			builder.WriteString(formatUnicode32(uint32(r)))
+		default:
+			builder.WriteString(formatUnicode(uint32(r)))
 		}
 	}
 
@@ -764,7 +773,7 @@ func convertHanToASCII(s string) string {
 }
 
 func decodeUnicode(input string) string {
-	re := regexp.MustCompile(`\\u[0-9a-fA-F]{4}`)
+	re := regexp.MustCompile(`\\u[0-9a-fA-F]{4}|\\U[0-9a-fA-F]{8}`)
 	return re.ReplaceAllStringFunc(input, func(match string) string {
 		code, _ := strconv.ParseInt(match[2:], 16, 32)
 		return string(rune(code))
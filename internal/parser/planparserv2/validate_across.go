@@ -0,0 +1,116 @@
+package planparserv2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// SchemaCompatibility is the result of validating an expression against one of the schemas.
+type SchemaCompatibility struct {
+	// Name is the name given to the schema, such as the collection an alias points to, or a schema version.
+	Name string
+	// Report is nil if the expression is invalid against the schema.
+	Report *ExprReport
+	Err    error
+}
+
+// FieldConflict is a field the expression reads with different data types depending on the schema, such as an
+// Int32 field which became Int64, or a field which is a static field in a schema and a key of the dynamic field
+// in another one.
+type FieldConflict struct {
+	// Field is the field as written in the expression, like `a` or `meta["b"]`.
+	Field string
+	// DataTypes are the data types of the field by schema name.
+	DataTypes map[string]schemapb.DataType
+}
+
+func (c *FieldConflict) String() string {
+	names := make([]string, 0, len(c.DataTypes))
+	for name := range c.DataTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	types := make([]string, len(names))
+	for i, name := range names {
+		types[i] = fmt.Sprintf("%s in %s", c.DataTypes[name], name)
+	}
+	return fmt.Sprintf("field %s is %s", c.Field, strings.Join(types, ", "))
+}
+
+// CompatibilityReport is the result of ValidateExprAcross.
+type CompatibilityReport struct {
+	// Schemas are the results by schema, sorted by name.
+	Schemas   []*SchemaCompatibility
+	Conflicts []*FieldConflict
+}
+
+// Compatible returns true if the expression is valid against all the schemas, and reads the same data types.
+func (r *CompatibilityReport) Compatible() bool {
+	for _, schema := range r.Schemas {
+		if schema.Err != nil {
+			return false
+		}
+	}
+	return len(r.Conflicts) == 0
+}
+
+// ValidateExprAcross validates the expression against several schemas at once like ValidateExpr, and reports
+// whether it behaves the same on all of them, such as before flipping an alias between two collections.
+func ValidateExprAcross(schemas map[string]*typeutil.SchemaHelper, exprStr string, opts ...ParseOption) *CompatibilityReport {
+	report := &CompatibilityReport{}
+	for name, schema := range schemas {
+		exprReport, err := ValidateExpr(schema, exprStr, opts...)
+		report.Schemas = append(report.Schemas, &SchemaCompatibility{Name: name, Report: exprReport, Err: err})
+	}
+	sort.Slice(report.Schemas, func(i, j int) bool {
+		return report.Schemas[i].Name < report.Schemas[j].Name
+	})
+
+	var fields []string
+	dataTypes := make(map[string]map[string]schemapb.DataType)
+	for _, schema := range report.Schemas {
+		if schema.Err != nil {
+			continue
+		}
+		dynamicField := ""
+		if field, err := schemas[schema.Name].GetDynamicField(); err == nil {
+			dynamicField = field.GetName()
+		}
+		for _, field := range schema.Report.Fields {
+			name := fieldDisplayName(field, dynamicField)
+			if _, ok := dataTypes[name]; !ok {
+				dataTypes[name] = make(map[string]schemapb.DataType)
+				fields = append(fields, name)
+			}
+			dataTypes[name][schema.Name] = field.DataType
+		}
+	}
+	for _, field := range fields {
+		distinct := make(map[schemapb.DataType]struct{})
+		for _, dataType := range dataTypes[field] {
+			distinct[dataType] = struct{}{}
+		}
+		if len(distinct) > 1 {
+			report.Conflicts = append(report.Conflicts, &FieldConflict{Field: field, DataTypes: dataTypes[field]})
+		}
+	}
+	return report
+}
+
+// fieldDisplayName returns the field as written in the expression, the keys of the dynamic field are named
+// like static fields.
+func fieldDisplayName(field *FieldReference, dynamicField string) string {
+	name, path := field.FieldName, field.NestedPath
+	if name == dynamicField && len(path) > 0 {
+		name, path = path[0], path[1:]
+	}
+	rendered, err := (&ASTField{Name: name, Path: path}).render()
+	if err != nil {
+		return name
+	}
+	return rendered
+}
@@ -0,0 +1,57 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestValidateExprAcross(t *testing.T) {
+	newHelper := func(fields ...*schemapb.FieldSchema) *typeutil.SchemaHelper {
+		schema := &schemapb.CollectionSchema{
+			Name:               "test",
+			EnableDynamicField: true,
+			Fields: append([]*schemapb.FieldSchema{
+				{FieldID: 100, Name: "id", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+				{FieldID: 101, Name: "meta", DataType: schemapb.DataType_JSON},
+				{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+			}, fields...),
+		}
+		helper, err := typeutil.CreateSchemaHelper(schema)
+		require.NoError(t, err)
+		return helper
+	}
+	schemas := map[string]*typeutil.SchemaHelper{
+		"v1": newHelper(&schemapb.FieldSchema{FieldID: 103, Name: "age", DataType: schemapb.DataType_Int32}),
+		"v2": newHelper(&schemapb.FieldSchema{FieldID: 103, Name: "age", DataType: schemapb.DataType_Int64},
+			&schemapb.FieldSchema{FieldID: 104, Name: "color", DataType: schemapb.DataType_VarChar}),
+	}
+
+	report := ValidateExprAcross(schemas, `id > 1 and meta["a"] == 1`)
+	assert.True(t, report.Compatible())
+	require.Len(t, report.Schemas, 2)
+	assert.Equal(t, "v1", report.Schemas[0].Name)
+	assert.NotNil(t, report.Schemas[1].Report)
+
+	report = ValidateExprAcross(schemas, `age > 1 and color == "red" and size == 1`)
+	assert.False(t, report.Compatible())
+	for _, schema := range report.Schemas {
+		assert.NoError(t, schema.Err)
+	}
+	require.Len(t, report.Conflicts, 2)
+	assert.Equal(t, "age", report.Conflicts[0].Field)
+	assert.Equal(t, "field age is Int32 in v1, Int64 in v2", report.Conflicts[0].String())
+	// color is a key of the dynamic field in v1.
+	assert.Equal(t, "color", report.Conflicts[1].Field)
+	assert.Equal(t, map[string]schemapb.DataType{"v1": schemapb.DataType_JSON, "v2": schemapb.DataType_VarChar}, report.Conflicts[1].DataTypes)
+
+	report = ValidateExprAcross(schemas, `color like "r%"`, WithImplicitDynamicField(false))
+	assert.False(t, report.Compatible())
+	assert.Error(t, report.Schemas[0].Err)
+	assert.NoError(t, report.Schemas[1].Err)
+	assert.Empty(t, report.Conflicts)
+}
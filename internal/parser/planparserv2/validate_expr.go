@@ -0,0 +1,216 @@
+package planparserv2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// ExprReport describes a validated expression.
+type ExprReport struct {
+	// ResultType is the data type the expression evaluates to.
+	ResultType schemapb.DataType
+	// Executable is true if the expression can be used as a filter on its own.
+	Executable bool
+	// Fields are the distinct fields (and json paths) the expression reads, in order of appearance.
+	Fields []*FieldReference
+	// Features are the sorted distinct features the expression uses, such as "like" or "json_contains".
+	Features []string
+	// TemplateSlots are the template variables which must be filled before execution.
+	TemplateSlots []*TemplateSlot
+	// RepeatedJSONPaths are the json paths read by several predicates, see RepeatedJSONPaths.
+	RepeatedJSONPaths []*JSONPathUsage
+}
+
+// FieldReference is a field read by an expression.
+type FieldReference struct {
+	FieldID    int64
+	FieldName  string
+	DataType   schemapb.DataType
+	NestedPath []string
+}
+
+// TemplateSlot is a template variable of an expression, with the data type of the field it's compared with.
+type TemplateSlot struct {
+	Name     string
+	DataType schemapb.DataType
+}
+
+// ValidateExpr parses and checks the expression against the schema like ParseExpr, but
+// reports what the expression is made of instead of building a plan.
+// Template variables are not required to be filled.
+func ValidateExpr(schema *typeutil.SchemaHelper, exprStr string, opts ...ParseOption) (*ExprReport, error) {
+	ret := handleExpr(schema, exprStr, opts...)
+
+	if err := getError(ret); err != nil {
+		return nil, fmt.Errorf("cannot parse expression: %s, error: %w", displayExpr(exprStr), redactError(err))
+	}
+
+	predicate := getExpr(ret)
+	if predicate == nil {
+		return nil, fmt.Errorf("cannot parse expression: %s", displayExpr(exprStr))
+	}
+
+	collector := newExprReportCollector(schema, &ExprReport{ResultType: predicate.dataType, Executable: canBeExecuted(predicate)})
+	if err := collector.collect(predicate.expr); err != nil {
+		return nil, err
+	}
+	for feature := range collector.features {
+		collector.report.Features = append(collector.report.Features, feature)
+	}
+	sort.Strings(collector.report.Features)
+	collector.report.RepeatedJSONPaths = RepeatedJSONPaths(predicate.expr)
+	return collector.report, nil
+}
+
+type exprReportCollector struct {
+	schema   *typeutil.SchemaHelper
+	report   *ExprReport
+	fields   map[string]struct{}
+	features map[string]struct{}
+	slots    map[string]struct{}
+}
+
+func newExprReportCollector(schema *typeutil.SchemaHelper, report *ExprReport) *exprReportCollector {
+	return &exprReportCollector{
+		schema:   schema,
+		report:   report,
+		fields:   make(map[string]struct{}),
+		features: make(map[string]struct{}),
+		slots:    make(map[string]struct{}),
+	}
+}
+
+func (c *exprReportCollector) addFeature(feature string) {
+	c.features[feature] = struct{}{}
+}
+
+func (c *exprReportCollector) addField(info *planpb.ColumnInfo) error {
+	key := fmt.Sprintf("%d/%s", info.GetFieldId(), strings.Join(info.GetNestedPath(), "/"))
+	if _, ok := c.fields[key]; ok {
+		return nil
+	}
+	field, err := fieldReference(c.schema, info)
+	if err != nil {
+		return err
+	}
+	c.fields[key] = struct{}{}
+	c.report.Fields = append(c.report.Fields, field)
+	return nil
+}
+
+func (c *exprReportCollector) addSlot(name string, info *planpb.ColumnInfo) {
+	if name == "" {
+		return
+	}
+	if _, ok := c.slots[name]; ok {
+		return
+	}
+	dataType := info.GetDataType()
+	if typeutil.IsArrayType(dataType) {
+		dataType = info.GetElementType()
+	}
+	c.slots[name] = struct{}{}
+	c.report.TemplateSlots = append(c.report.TemplateSlots, &TemplateSlot{Name: name, DataType: dataType})
+}
+
+func (c *exprReportCollector) collect(expr *planpb.Expr) error {
+	switch realExpr := expr.GetExpr().(type) {
+	case *planpb.Expr_AlwaysTrueExpr:
+		return nil
+	case *planpb.Expr_UnaryExpr:
+		c.addFeature("not")
+		return c.collect(realExpr.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		c.addFeature("logical")
+		if err := c.collect(realExpr.BinaryExpr.GetLeft()); err != nil {
+			return err
+		}
+		return c.collect(realExpr.BinaryExpr.GetRight())
+	case *planpb.Expr_TermExpr:
+		c.addFeature("in")
+		c.addSlot(realExpr.TermExpr.GetTemplateVariableName(), realExpr.TermExpr.GetColumnInfo())
+		return c.addField(realExpr.TermExpr.GetColumnInfo())
+	case *planpb.Expr_CompareExpr:
+		c.addFeature("column_compare")
+		if err := c.addField(realExpr.CompareExpr.GetLeftColumnInfo()); err != nil {
+			return err
+		}
+		return c.addField(realExpr.CompareExpr.GetRightColumnInfo())
+	case *planpb.Expr_UnaryRangeExpr:
+		e := realExpr.UnaryRangeExpr
+		switch e.GetOp() {
+		case planpb.OpType_PrefixMatch, planpb.OpType_PostfixMatch:
+			c.addFeature("like")
+		case planpb.OpType_Match:
+			c.addFeature("like")
+			c.addFeature("regex")
+		case planpb.OpType_TextMatch:
+			c.addFeature("text_match")
+		case planpb.OpType_PhraseMatch:
+			c.addFeature("phrase_match")
+		default:
+			c.addFeature("compare")
+		}
+		c.addSlot(e.GetTemplateVariableName(), e.GetColumnInfo())
+		return c.addField(e.GetColumnInfo())
+	case *planpb.Expr_BinaryRangeExpr:
+		e := realExpr.BinaryRangeExpr
+		c.addFeature("range")
+		c.addSlot(e.GetLowerTemplateVariableName(), e.GetColumnInfo())
+		c.addSlot(e.GetUpperTemplateVariableName(), e.GetColumnInfo())
+		return c.addField(e.GetColumnInfo())
+	case *planpb.Expr_BinaryArithOpEvalRangeExpr:
+		e := realExpr.BinaryArithOpEvalRangeExpr
+		if e.GetArithOp() == planpb.ArithOpType_ArrayLength {
+			c.addFeature("array_length")
+		} else {
+			c.addFeature("arithmetic")
+		}
+		c.addSlot(e.GetOperandTemplateVariableName(), e.GetColumnInfo())
+		c.addSlot(e.GetValueTemplateVariableName(), e.GetColumnInfo())
+		return c.addField(e.GetColumnInfo())
+	case *planpb.Expr_BinaryArithExpr:
+		c.addFeature("arithmetic")
+		if err := c.collect(realExpr.BinaryArithExpr.GetLeft()); err != nil {
+			return err
+		}
+		return c.collect(realExpr.BinaryArithExpr.GetRight())
+	case *planpb.Expr_ColumnExpr:
+		return c.addField(realExpr.ColumnExpr.GetInfo())
+	case *planpb.Expr_ValueExpr:
+		c.addSlot(realExpr.ValueExpr.GetTemplateVariableName(), nil)
+		return nil
+	case *planpb.Expr_NullExpr:
+		c.addFeature("null")
+		return c.addField(realExpr.NullExpr.GetColumnInfo())
+	case *planpb.Expr_ExistsExpr:
+		c.addFeature("exists")
+		return c.addField(realExpr.ExistsExpr.GetInfo())
+	case *planpb.Expr_JsonContainsExpr:
+		e := realExpr.JsonContainsExpr
+		c.addFeature("json_contains")
+		c.addSlot(e.GetTemplateVariableName(), e.GetColumnInfo())
+		return c.addField(e.GetColumnInfo())
+	case *planpb.Expr_CallExpr:
+		c.addFeature("call:" + realExpr.CallExpr.GetFunctionName())
+		for _, param := range realExpr.CallExpr.GetFunctionParameters() {
+			if err := c.collect(param); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *planpb.Expr_RandomSampleExpr:
+		c.addFeature("random_sample")
+		if predicate := realExpr.RandomSampleExpr.GetPredicate(); predicate != nil {
+			return c.collect(predicate)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported expression type: %T", realExpr)
+	}
+}
@@ -0,0 +1,54 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestValidateExpr(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	report, err := ValidateExpr(helper, `Int64Field > {min} and (JSONField["a"] like "x%" or Int64Field in {ids}) and not exists A["b"]`)
+	require.NoError(t, err)
+	assert.Equal(t, schemapb.DataType_Bool, report.ResultType)
+	assert.True(t, report.Executable)
+	assert.Equal(t, []string{"compare", "exists", "in", "like", "logical", "not"}, report.Features)
+
+	fieldNames := make([]string, 0, len(report.Fields))
+	for _, field := range report.Fields {
+		fieldNames = append(fieldNames, field.FieldName)
+	}
+	assert.Equal(t, []string{"Int64Field", "JSONField", "$meta"}, fieldNames)
+	assert.Equal(t, []string{"a"}, report.Fields[1].NestedPath)
+	assert.Equal(t, []string{"A", "b"}, report.Fields[2].NestedPath)
+
+	require.Len(t, report.TemplateSlots, 2)
+	assert.Equal(t, &TemplateSlot{Name: "min", DataType: schemapb.DataType_Int64}, report.TemplateSlots[0])
+	assert.Equal(t, &TemplateSlot{Name: "ids", DataType: schemapb.DataType_Int64}, report.TemplateSlots[1])
+
+	report, err = ValidateExpr(helper, ``)
+	require.NoError(t, err)
+	assert.True(t, report.Executable)
+	assert.Empty(t, report.Fields)
+
+	report, err = ValidateExpr(helper, `Int64Field`)
+	require.NoError(t, err)
+	assert.False(t, report.Executable)
+	assert.Equal(t, schemapb.DataType_Int64, report.ResultType)
+
+	report, err = ValidateExpr(helper, `array_length(ArrayField) == 1 && random_sample(0.1)`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"array_length", "random_sample"}, report.Features)
+
+	_, err = ValidateExpr(helper, `VarCharField > 1`)
+	assert.Error(t, err)
+	// the cause is wrapped for errors.Is checks.
+	assert.NotNil(t, errors.Unwrap(err))
+	_, err = ValidateExpr(helper, `Int64Field >`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package planparserv2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	parser "github.com/milvus-io/milvus/internal/parser/planparserv2/generated"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// valueSetRefFunctionName references a set of values stored outside of the expression, like
+// `pk in ref("s3://bucket/ids.parquet")`, so that tools don't have to inline millions of values.
+const valueSetRefFunctionName = "ref"
+
+// ValueSetResolver returns the values of the set referenced by uri.
+type ValueSetResolver func(ctx context.Context, uri string) ([]*planpb.GenericValue, error)
+
+var valueSetResolver atomic.Pointer[ValueSetResolver]
+
+// SetValueSetResolver sets the resolver of the `in ref("uri")` references, nil removes it.
+func SetValueSetResolver(resolver ValueSetResolver) {
+	if resolver == nil {
+		valueSetResolver.Store(nil)
+		return
+	}
+	valueSetResolver.Store(&resolver)
+}
+
+// WithValueSetRefs allows `field in ref("uri")`, whose values are read by the resolver set with
+// SetValueSetResolver when the plan is built. The uri can point to any storage the resolver can read,
+// so it should only be enabled for administrators.
+func WithValueSetRefs(enabled bool) ParseOption {
+	return func(options *parseOptions) {
+		options.valueSetRefs = enabled
+	}
+}
+
+func isValueSetRef(ctx parser.IExprContext) bool {
+	call, ok := ctx.(*parser.CallContext)
+	return ok && strings.ToLower(call.Identifier().GetText()) == valueSetRefFunctionName
+}
+
+// translateValueSetRef resolves the referenced values into a list, which is cast to the type of the field like
+// the lists written in the expression.
+func (v *ParserVisitor) translateValueSetRef(ctx *parser.CallContext) interface{} {
+	resolver := valueSetResolver.Load()
+	if !v.options.valueSetRefs || resolver == nil {
+		return fmt.Errorf("%s() is not enabled", valueSetRefFunctionName)
+	}
+	if len(ctx.AllExpr()) != 1 {
+		return fmt.Errorf("%s() takes the uri of a value set, like %s(\"s3://bucket/ids.parquet\")", valueSetRefFunctionName, valueSetRefFunctionName)
+	}
+	uri := getGenericValue(ctx.Expr(0).Accept(v))
+	if !IsString(uri) || uri.GetStringVal() == "" {
+		return fmt.Errorf("%s() takes the uri of a value set, like %s(\"s3://bucket/ids.parquet\")", valueSetRefFunctionName, valueSetRefFunctionName)
+	}
+	values, err := (*resolver)(v.options.ctx, uri.GetStringVal())
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s(\"%s\"): %w", valueSetRefFunctionName, uri.GetStringVal(), err)
+	}
+	return toValueExpr(&planpb.GenericValue{
+		Val: &planpb.GenericValue_ArrayVal{
+			ArrayVal: &planpb.Array{Array: values},
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package planparserv2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+func TestValueSetRefs(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	_, err := ParseExpr(helper, `Int64Field in ref("s3://bucket/ids.parquet")`, nil, WithValueSetRefs(true))
+	assert.ErrorContains(t, err, "not enabled")
+
+	SetValueSetResolver(func(ctx context.Context, uri string) ([]*planpb.GenericValue, error) {
+		if uri != "s3://bucket/ids.parquet" {
+			return nil, fmt.Errorf("no such object")
+		}
+		return []*planpb.GenericValue{NewInt(3), NewInt(1), NewInt(2)}, nil
+	})
+	defer SetValueSetResolver(nil)
+	assert.Contains(t, Capabilities(WithValueSetRefs(true)).Functions, "ref")
+	assert.NotContains(t, Capabilities().Functions, "ref")
+
+	expr, err := ParseExpr(helper, `Int64Field in ref("s3://bucket/ids.parquet")`, nil, WithValueSetRefs(true))
+	require.NoError(t, err)
+	values := expr.GetTermExpr().GetValues()
+	require.Len(t, values, 3)
+	assert.Equal(t, int64(1), values[0].GetInt64Val())
+
+	expr, err = ParseExpr(helper, `Int64Field > 0 and Int64Field not in REF("s3://bucket/ids.parquet")`, nil, WithValueSetRefs(true))
+	require.NoError(t, err)
+	assert.NotNil(t, expr.GetBinaryExpr())
+
+	invalid := map[string][]ParseOption{
+		`Int64Field in ref("s3://bucket/ids.parquet")`:   nil,
+		`Int64Field in ref("s3://bucket/other.parquet")`: {WithValueSetRefs(true)},
+		`Int64Field in ref()`:                            {WithValueSetRefs(true)},
+		`Int64Field in ref(1)`:                           {WithValueSetRefs(true)},
+		`VarCharField in ref("s3://bucket/ids.parquet")`: {WithValueSetRefs(true)},
+		`ref("s3://bucket/ids.parquet")`:                 {WithValueSetRefs(true)},
+	}
+	for exprStr, opts := range invalid {
+		_, err := ParseExpr(helper, exprStr, nil, opts...)
+		assert.Error(t, err, exprStr)
+	}
+}
@@ -0,0 +1,159 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// VectorTemplateKey is the key of the json object carrying a vector literal in the template values, so that the
+// expressions comparing with a vector, such as distance filters, can take it as a template variable:
+//
+//	{"$vec": {"type": "FloatVector", "dim": 4, "data": "<base64>"}}
+//
+// The data is the raw vector, laid out like in placeholder groups. The object is the only element of the json
+// data of an array template value.
+const VectorTemplateKey = "$vec"
+
+// VectorTemplate is a vector literal passed as a template value.
+type VectorTemplate struct {
+	DataType schemapb.DataType
+	Dim      int64
+	Data     []byte
+}
+
+type vectorTemplatePayload struct {
+	Type string `json:"type"`
+	Dim  int64  `json:"dim"`
+	Data []byte `json:"data"`
+}
+
+// vectorByteSize returns the size in bytes of a vector of the data type and dim.
+func vectorByteSize(dataType schemapb.DataType, dim int64) (int64, error) {
+	if dim <= 0 {
+		return 0, fmt.Errorf("invalid vector dim %d", dim)
+	}
+	switch dataType {
+	case schemapb.DataType_FloatVector:
+		return dim * 4, nil
+	case schemapb.DataType_Float16Vector, schemapb.DataType_BFloat16Vector:
+		return dim * 2, nil
+	case schemapb.DataType_Int8Vector:
+		return dim, nil
+	case schemapb.DataType_BinaryVector:
+		if dim%8 != 0 {
+			return 0, fmt.Errorf("dim of binary vector must be a multiple of 8, got %d", dim)
+		}
+		return dim / 8, nil
+	default:
+		return 0, fmt.Errorf("%s can't be a vector template value", dataType.String())
+	}
+}
+
+func (v *VectorTemplate) validate() error {
+	size, err := vectorByteSize(v.DataType, v.Dim)
+	if err != nil {
+		return err
+	}
+	if int64(len(v.Data)) != size {
+		return fmt.Errorf("%s of dim %d has %d bytes, got %d", v.DataType.String(), v.Dim, size, len(v.Data))
+	}
+	return nil
+}
+
+// NewVectorTemplateValue returns the template value carrying the vector.
+func NewVectorTemplateValue(dataType schemapb.DataType, dim int64, data []byte) (*schemapb.TemplateValue, error) {
+	vector := &VectorTemplate{DataType: dataType, Dim: dim, Data: data}
+	if err := vector.validate(); err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(map[string]*vectorTemplatePayload{
+		VectorTemplateKey: {Type: dataType.String(), Dim: dim, Data: data},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &schemapb.TemplateValue{
+		Val: &schemapb.TemplateValue_ArrayVal{
+			ArrayVal: &schemapb.TemplateArrayValue{
+				Data: &schemapb.TemplateArrayValue_JsonData{
+					JsonData: &schemapb.JSONArray{Data: [][]byte{payload}},
+				},
+			},
+		},
+	}, nil
+}
+
+// isVectorTemplateValue returns whether the template value is a json object keyed by VectorTemplateKey.
+func isVectorTemplateValue(value *schemapb.TemplateValue) bool {
+	jsons := value.GetArrayVal().GetJsonData().GetData()
+	if len(jsons) != 1 {
+		return false
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(jsons[0], &object); err != nil {
+		return false
+	}
+	_, ok := object[VectorTemplateKey]
+	return ok && len(object) == 1
+}
+
+// GetVectorTemplate decodes the vector carried by the template value.
+func GetVectorTemplate(templateName string, value *schemapb.TemplateValue) (*VectorTemplate, error) {
+	if !isVectorTemplateValue(value) {
+		return nil, fmt.Errorf("the value of template variable {%s} is not a vector", templateName)
+	}
+	var object map[string]*vectorTemplatePayload
+	if err := json.Unmarshal(value.GetArrayVal().GetJsonData().GetData()[0], &object); err != nil {
+		return nil, fmt.Errorf("invalid vector of template variable {%s}: %w", templateName, err)
+	}
+	payload := object[VectorTemplateKey]
+	if payload == nil {
+		return nil, fmt.Errorf("invalid vector of template variable {%s}: empty vector", templateName)
+	}
+	dataType, ok := schemapb.DataType_value[payload.Type]
+	if !ok {
+		return nil, fmt.Errorf("invalid vector of template variable {%s}: unknown type %s", templateName, payload.Type)
+	}
+	vector := &VectorTemplate{DataType: schemapb.DataType(dataType), Dim: payload.Dim, Data: payload.Data}
+	if err := vector.validate(); err != nil {
+		return nil, fmt.Errorf("invalid vector of template variable {%s}: %w", templateName, err)
+	}
+	return vector, nil
+}
+
+// GetVectorTemplateFor decodes the vector of the template variable, and checks it against the type and dim of
+// the vector field it's compared with.
+func GetVectorTemplateFor(schema *typeutil.SchemaHelper, fieldName string, templateName string,
+	exprTemplateValues map[string]*schemapb.TemplateValue,
+) (*VectorTemplate, error) {
+	field, err := schema.GetFieldFromName(fieldName)
+	if err != nil {
+		return nil, err
+	}
+	if !typeutil.IsVectorType(field.GetDataType()) {
+		return nil, fmt.Errorf("field %s is not a vector field", fieldName)
+	}
+	value, ok := exprTemplateValues[templateName]
+	if !ok {
+		return nil, fmt.Errorf("the value of template variable {%s} is not found", templateName)
+	}
+	vector, err := GetVectorTemplate(templateName, value)
+	if err != nil {
+		return nil, err
+	}
+	if vector.DataType != field.GetDataType() {
+		return nil, fmt.Errorf("template variable {%s} is a %s, but field %s is a %s",
+			templateName, vector.DataType.String(), fieldName, field.GetDataType().String())
+	}
+	dim, err := typeutil.GetDim(field)
+	if err != nil {
+		return nil, err
+	}
+	if vector.Dim != dim {
+		return nil, fmt.Errorf("template variable {%s} has dim %d, but field %s has dim %d", templateName, vector.Dim, fieldName, dim)
+	}
+	return vector, nil
+}
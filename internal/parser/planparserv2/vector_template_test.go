@@ -0,0 +1,89 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestVectorTemplateValue(t *testing.T) {
+	value, err := NewVectorTemplateValue(schemapb.DataType_FloatVector, 2, []byte{0, 0, 128, 63, 0, 0, 0, 64})
+	require.NoError(t, err)
+	vector, err := GetVectorTemplate("v", value)
+	require.NoError(t, err)
+	assert.Equal(t, schemapb.DataType_FloatVector, vector.DataType)
+	assert.Equal(t, int64(2), vector.Dim)
+	assert.Equal(t, []byte{0, 0, 128, 63, 0, 0, 0, 64}, vector.Data)
+
+	_, err = NewVectorTemplateValue(schemapb.DataType_FloatVector, 2, []byte{0, 0, 128, 63})
+	assert.Error(t, err)
+	_, err = NewVectorTemplateValue(schemapb.DataType_BinaryVector, 12, []byte{0, 0})
+	assert.Error(t, err)
+	_, err = NewVectorTemplateValue(schemapb.DataType_SparseFloatVector, 2, []byte{0})
+	assert.Error(t, err)
+	_, err = NewVectorTemplateValue(schemapb.DataType_Int64, 1, []byte{0})
+	assert.Error(t, err)
+	value, err = NewVectorTemplateValue(schemapb.DataType_BinaryVector, 16, []byte{1, 2})
+	require.NoError(t, err)
+	_, err = GetVectorTemplate("v", value)
+	assert.NoError(t, err)
+
+	_, err = GetVectorTemplate("v", generateTemplateValue(schemapb.DataType_Int64, int64(1)))
+	assert.Error(t, err)
+	invalid := &schemapb.TemplateValue{Val: &schemapb.TemplateValue_ArrayVal{ArrayVal: &schemapb.TemplateArrayValue{
+		Data: &schemapb.TemplateArrayValue_JsonData{JsonData: &schemapb.JSONArray{Data: [][]byte{[]byte(`{"$vec": {"type": "Unknown", "dim": 1}}`)}}},
+	}}}
+	_, err = GetVectorTemplate("v", invalid)
+	assert.Error(t, err)
+}
+
+func TestGetVectorTemplateFor(t *testing.T) {
+	schema, err := typeutil.CreateSchemaHelper(&schemapb.CollectionSchema{
+		Name: "test",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "id", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "embedding", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: "dim", Value: "2"}}},
+			{FieldID: 102, Name: "hash", DataType: schemapb.DataType_BinaryVector, TypeParams: []*commonpb.KeyValuePair{{Key: "dim", Value: "16"}}},
+		},
+	})
+	require.NoError(t, err)
+	float2, err := NewVectorTemplateValue(schemapb.DataType_FloatVector, 2, make([]byte, 8))
+	require.NoError(t, err)
+	float4, err := NewVectorTemplateValue(schemapb.DataType_FloatVector, 4, make([]byte, 16))
+	require.NoError(t, err)
+	values := map[string]*schemapb.TemplateValue{
+		"v":      float2,
+		"wide":   float4,
+		"scalar": generateTemplateValue(schemapb.DataType_Int64, int64(1)),
+	}
+
+	vector, err := GetVectorTemplateFor(schema, "embedding", "v", values)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), vector.Dim)
+
+	_, err = GetVectorTemplateFor(schema, "embedding", "wide", values)
+	assert.ErrorContains(t, err, "dim")
+	_, err = GetVectorTemplateFor(schema, "hash", "v", values)
+	assert.ErrorContains(t, err, "BinaryVector")
+	_, err = GetVectorTemplateFor(schema, "embedding", "scalar", values)
+	assert.Error(t, err)
+	_, err = GetVectorTemplateFor(schema, "embedding", "missing", values)
+	assert.Error(t, err)
+	_, err = GetVectorTemplateFor(schema, "id", "v", values)
+	assert.Error(t, err)
+	_, err = GetVectorTemplateFor(schema, "unknown", "v", values)
+	assert.Error(t, err)
+
+	// vectors are left to the vector expressions, and can't be used as scalars.
+	_, err = ParseExpr(schema, `id > {scalar}`, values)
+	assert.NoError(t, err)
+	_, err = ParseExpr(schema, `id in {v}`, values)
+	assert.Error(t, err)
+	_, err = ConvertToGenericValue("v", float2)
+	assert.Error(t, err)
+}
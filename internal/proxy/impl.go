@@ -3244,6 +3244,7 @@ func (node *Proxy) search(ctx context.Context, request *milvuspb.SearchRequest,
 			hookutil.RelatedCntKey:      qt.result.GetResults().GetAllSearchCount(),
 		})
 		SetReportValue(qt.result.GetStatus(), v)
+		SetExprWarnings(qt.result.GetStatus(), qt.exprWarnings)
 		if merr.Ok(qt.result.GetStatus()) {
 			metrics.ProxyReportValue.WithLabelValues(nodeID, hookutil.OpTypeSearch, dbName, username).Add(float64(v))
 		}
@@ -3809,6 +3810,7 @@ func (node *Proxy) Query(ctx context.Context, request *milvuspb.QueryRequest) (*
 		hookutil.RelatedCntKey:      qt.allQueryCnt,
 	})
 	SetReportValue(res.Status, v)
+	SetExprWarnings(res.Status, qt.exprWarnings)
 	metrics.ProxyReportValue.WithLabelValues(nodeID, hookutil.OpTypeQuery, request.DbName, username).Add(float64(v))
 	return res, nil
 }
@@ -6669,6 +6671,8 @@ func (node *Proxy) ListImports(ctx context.Context, req *internalpb.ListImportsR
 func DeregisterSubLabel(subLabel string) {
 	rateCol.DeregisterSubLabel(internalpb.RateType_DQLQuery.String(), subLabel)
 	rateCol.DeregisterSubLabel(internalpb.RateType_DQLSearch.String(), subLabel)
+	rateCol.DeregisterSubLabel(metricsinfo.ParseTime, subLabel)
+	rateCol.DeregisterSubLabel(metricsinfo.ParseNodes, subLabel)
 }
 
 // RegisterRestRouter registers the router for the proxy
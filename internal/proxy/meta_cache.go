@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
@@ -32,6 +33,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/log"
@@ -120,6 +122,11 @@ type schemaInfo struct {
 	hasPartitionKeyField bool
 	pkField              *schemapb.FieldSchema
 	schemaHelper         *typeutil.SchemaHelper
+
+	// the constraints are compiled on the first write, after the schema helper is tagged by its epoch.
+	constraintsOnce sync.Once
+	constraints     planparserv2.Constraints
+	constraintsErr  error
 }
 
 func newSchemaInfoWithLoadFields(schema *schemapb.CollectionSchema, loadFields []int64) *schemaInfo {
@@ -150,6 +157,14 @@ func newSchemaInfo(schema *schemapb.CollectionSchema) *schemaInfo {
 	return newSchemaInfoWithLoadFields(schema, nil)
 }
 
+// GetConstraints returns the constraints defined by the properties of the collection, compiled once per schema.
+func (s *schemaInfo) GetConstraints() (planparserv2.Constraints, error) {
+	s.constraintsOnce.Do(func() {
+		s.constraints, s.constraintsErr = planparserv2.CompileConstraints(s.schemaHelper)
+	})
+	return s.constraints, s.constraintsErr
+}
+
 func (s *schemaInfo) MapFieldID(name string) (int64, bool) {
 	return s.fieldMap.Get(name)
 }
@@ -345,19 +360,27 @@ type MetaCache struct {
 	IDLock  sync.RWMutex
 
 	collectionCacheVersion map[UniqueID]uint64 // collectionID -> cacheVersion
+
+	latestSchemaTime map[string]time.Time // database--collectionID -> last refresh by latestSchema
+	latestSchemaMut  sync.Mutex
 }
 
+// latestSchemaInterval is the minimum interval between the schema refreshes of a collection triggered by
+// expressions on unknown fields.
+var latestSchemaInterval = 5 * time.Second
+
 // globalMetaCache is singleton instance of Cache
 var globalMetaCache Cache
 
 // InitMetaCache initializes globalMetaCache
 func InitMetaCache(ctx context.Context, rootCoord types.RootCoordClient, queryCoord types.QueryCoordClient, shardMgr shardClientMgr) error {
-	var err error
-	globalMetaCache, err = NewMetaCache(rootCoord, queryCoord, shardMgr)
+	metaCache, err := NewMetaCache(rootCoord, queryCoord, shardMgr)
 	if err != nil {
 		return err
 	}
+	globalMetaCache = metaCache
 	expr.Register("cache", globalMetaCache)
+	planparserv2.SetLatestSchemaProvider(metaCache.latestSchema)
 
 	// The privilege info is a little more. And to get this info, the query operation of involving multiple table queries is required.
 	resp, err := rootCoord.ListPolicy(ctx, &internalpb.ListPolicyRequest{})
@@ -383,6 +406,7 @@ func NewMetaCache(rootCoord types.RootCoordClient, queryCoord types.QueryCoordCl
 		privilegeInfos:         map[string]struct{}{},
 		userToRoles:            map[string]map[string]struct{}{},
 		collectionCacheVersion: make(map[UniqueID]uint64),
+		latestSchemaTime:       make(map[string]time.Time),
 	}, nil
 }
 
@@ -457,6 +481,10 @@ func (m *MetaCache) update(ctx context.Context, database, collectionName string,
 	}
 
 	schemaInfo := newSchemaInfoWithLoadFields(collection.Schema, loadFields)
+	if schemaInfo.schemaHelper != nil {
+		// the update timestamp grows when the schema is altered, parser uses it to detect stale snapshots.
+		schemaInfo.schemaHelper = schemaInfo.schemaHelper.WithEpoch(database, collection.CollectionID, collection.UpdateTimestamp)
+	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -529,6 +557,39 @@ func (m *MetaCache) UpdateByID(ctx context.Context, database string, collectionI
 	return collection, err
 }
 
+// latestSchema refreshes the collection from rootcoord, so the expression parser can tell stale schema snapshots.
+// The refreshes are limited to one per latestSchemaInterval per collection, so that a client repeating a filter on
+// a misspelled field doesn't turn every request into a rootcoord call, the cached schema is returned in between.
+func (m *MetaCache) latestSchema(ctx context.Context, database string, collectionID int64) (*typeutil.SchemaHelper, error) {
+	if !m.allowLatestSchema(database, collectionID) {
+		if collInfo, ok := m.getCollection(database, "", collectionID); ok {
+			return collInfo.schema.schemaHelper, nil
+		}
+		return nil, fmt.Errorf("schema of collection %d was refreshed less than %s ago", collectionID, latestSchemaInterval)
+	}
+	// the cached collection is returned by UpdateByID as is, so it's removed to fetch the latest schema.
+	m.RemoveCollectionsByID(ctx, collectionID, 0, false)
+	collInfo, err := m.UpdateByID(ctx, database, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	return collInfo.schema.schemaHelper, nil
+}
+
+// allowLatestSchema returns whether the schema of the collection may be refreshed by latestSchema, and records the
+// refresh if so.
+func (m *MetaCache) allowLatestSchema(database string, collectionID int64) bool {
+	key := buildSfKeyById(database, collectionID)
+	now := time.Now()
+	m.latestSchemaMut.Lock()
+	defer m.latestSchemaMut.Unlock()
+	if refreshed, ok := m.latestSchemaTime[key]; ok && now.Sub(refreshed) < latestSchemaInterval {
+		return false
+	}
+	m.latestSchemaTime[key] = now
+	return true
+}
+
 // GetCollectionID returns the corresponding collection id for provided collection name
 func (m *MetaCache) GetCollectionID(ctx context.Context, database, collectionName string) (UniqueID, error) {
 	method := "GetCollectionID"
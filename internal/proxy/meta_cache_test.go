@@ -35,6 +35,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
@@ -385,6 +386,69 @@ func TestMetaCacheGetCollectionWithUpdate(t *testing.T) {
 	})
 }
 
+func TestMetaCache_SchemaEpoch(t *testing.T) {
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	defer planparserv2.SetLatestSchemaProvider(nil)
+	ctx := context.Background()
+	rootCoord := mocks.NewMockRootCoordClient(t)
+	queryCoord := mocks.NewMockQueryCoordClient(t)
+	rootCoord.EXPECT().ListPolicy(mock.Anything, mock.Anything, mock.Anything).Return(&internalpb.ListPolicyResponse{Status: merr.Success()}, nil)
+	mgr := newShardClientMgr()
+	err := InitMetaCache(ctx, rootCoord, queryCoord, mgr)
+	assert.NoError(t, err)
+
+	fields := []*schemapb.FieldSchema{
+		{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+	}
+	describe := func(fields []*schemapb.FieldSchema, updateTimestamp uint64) {
+		rootCoord.EXPECT().DescribeCollection(mock.Anything, mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+			Status:          merr.Success(),
+			CollectionID:    1,
+			Schema:          &schemapb.CollectionSchema{Name: "bar", Fields: fields},
+			UpdateTimestamp: updateTimestamp,
+		}, nil).Once()
+		rootCoord.EXPECT().ShowPartitions(mock.Anything, mock.Anything, mock.Anything).Return(&milvuspb.ShowPartitionsResponse{
+			Status: merr.Success(),
+		}, nil).Once()
+		queryCoord.EXPECT().ShowCollections(mock.Anything, mock.Anything).Return(&querypb.ShowCollectionsResponse{}, nil).Once()
+	}
+
+	describe(fields, 10)
+	c, err := globalMetaCache.GetCollectionInfo(ctx, "foo", "bar", 1)
+	assert.NoError(t, err)
+	stale := c.schema.schemaHelper
+	assert.Equal(t, "foo", stale.GetDatabaseName())
+	assert.Equal(t, int64(1), stale.GetCollectionID())
+	assert.Equal(t, uint64(10), stale.GetEpoch())
+
+	// the field is added by another proxy, the stale snapshot refreshes the cache and fails with a retryable error.
+	describe(append(fields, &schemapb.FieldSchema{FieldID: 101, Name: "new_field", DataType: schemapb.DataType_Int64, Nullable: true}), 20)
+	_, err = planparserv2.ParseExpr(stale, "new_field > 1", nil)
+	assert.ErrorIs(t, err, merr.ErrCollectionSchemaOutdated)
+
+	c, err = globalMetaCache.GetCollectionInfo(ctx, "foo", "bar", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), c.schema.schemaHelper.GetEpoch())
+	_, err = planparserv2.ParseExpr(c.schema.schemaHelper, "new_field > 1", nil)
+	assert.NoError(t, err)
+
+	// the collection was refreshed recently, the misspelled field doesn't call rootcoord again.
+	_, err = planparserv2.ParseExpr(stale, "new_fiel > 1", nil, planparserv2.WithContext(ctx))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, merr.ErrCollectionSchemaOutdated))
+	rootCoord.AssertNumberOfCalls(t, "DescribeCollection", 2)
+
+	interval := latestSchemaInterval
+	latestSchemaInterval = 0
+	defer func() { latestSchemaInterval = interval }()
+	describe(append(fields, &schemapb.FieldSchema{FieldID: 101, Name: "new_field", DataType: schemapb.DataType_Int64, Nullable: true}), 20)
+	_, err = planparserv2.ParseExpr(stale, "new_fiel > 1", nil, planparserv2.WithContext(ctx))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, merr.ErrCollectionSchemaOutdated))
+	rootCoord.AssertNumberOfCalls(t, "DescribeCollection", 3)
+}
+
 func TestMetaCache_GetCollectionName(t *testing.T) {
 	ctx := context.Background()
 	rootCoord := &MockRootCoordClientInterface{}
@@ -74,6 +74,8 @@ func getQuotaMetrics() (*metricsinfo.ProxyQuotaMetrics, error) {
 	getSubLabelRateMetric(internalpb.RateType_DQLSearch.String())
 	getRateMetric(internalpb.RateType_DQLQuery.String())
 	getSubLabelRateMetric(internalpb.RateType_DQLQuery.String())
+	getSubLabelRateMetric(metricsinfo.ParseTime)
+	getSubLabelRateMetric(metricsinfo.ParseNodes)
 	if err != nil {
 		return nil, err
 	}
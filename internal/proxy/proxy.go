@@ -35,12 +35,14 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/internal/allocator"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/internal/proxy/connection"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/internal/util/dependency"
 	"github.com/milvus-io/milvus/internal/util/hookutil"
 	"github.com/milvus-io/milvus/internal/util/sessionutil"
 	"github.com/milvus-io/milvus/internal/util/streamingutil"
+	"github.com/milvus-io/milvus/pkg/v2/config"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/metrics"
 	"github.com/milvus-io/milvus/pkg/v2/mq/msgstream"
@@ -212,6 +214,8 @@ func (node *Proxy) initRateCollector() error {
 	// TODO: add bulkLoad rate
 	rateCol.Register(internalpb.RateType_DQLSearch.String())
 	rateCol.Register(internalpb.RateType_DQLQuery.String())
+	rateCol.Register(metricsinfo.ParseTime)
+	rateCol.Register(metricsinfo.ParseNodes)
 	return nil
 }
 
@@ -303,6 +307,8 @@ func (node *Proxy) Init() error {
 	}
 	log.Debug("init meta cache done", zap.String("role", typeutil.ProxyRole))
 
+	initExprParserConfig()
+
 	node.enableMaterializedView = Params.CommonCfg.EnableMaterializedView.GetAsBool()
 
 	// Enable internal rand pool for UUIDv4 generation
@@ -316,6 +322,34 @@ func (node *Proxy) Init() error {
 	return nil
 }
 
+// initExprParserConfig keeps the literal redaction and unicode conversion of the expression parser in sync with the config.
+func initExprParserConfig() {
+	pt := paramtable.Get()
+	setLiteralRedaction := func() {
+		planparserv2.SetLiteralRedaction(paramtable.Get().ProxyCfg.RedactExprLiterals.GetAsBool())
+	}
+	setUnicodeConversion := func() {
+		conversion, err := planparserv2.ParseUnicodeConversion(paramtable.Get().ProxyCfg.ExprUnicodeConversion.GetValue())
+		if err != nil {
+			log.Warn("invalid unicode conversion of expressions, keep the current one", zap.Error(err))
+			return
+		}
+		planparserv2.SetUnicodeConversion(conversion)
+	}
+	setLiteralRedaction()
+	setUnicodeConversion()
+	pt.Watch(pt.ProxyCfg.RedactExprLiterals.Key, config.NewHandler("proxy."+pt.ProxyCfg.RedactExprLiterals.Key, func(evt *config.Event) {
+		if evt.HasUpdated {
+			setLiteralRedaction()
+		}
+	}))
+	pt.Watch(pt.ProxyCfg.ExprUnicodeConversion.Key, config.NewHandler("proxy."+pt.ProxyCfg.ExprUnicodeConversion.Key, func(evt *config.Event) {
+		if evt.HasUpdated {
+			setUnicodeConversion()
+		}
+	}))
+}
+
 // sendChannelsTimeTickLoop starts a goroutine that synchronizes the time tick information.
 func (node *Proxy) sendChannelsTimeTickLoop() {
 	log := log.Ctx(node.ctx)
@@ -391,6 +391,36 @@ func getPartitionIDs(ctx context.Context, dbName string, collectionName string,
 	return partitionsSet.Collect(), nil
 }
 
+// getTargetedPartitionIDs returns the ids of the partitions targeted by the `partition() in [...]` conjuncts of a filter,
+// restricted to the partitions of the request if it names any.
+func getTargetedPartitionIDs(ctx context.Context, dbName string, collectionName string, requestedNames []string, targets []string, partitionKeyMode bool) ([]UniqueID, error) {
+	if partitionKeyMode {
+		return nil, merr.WrapErrParameterInvalidMsg("not support targeting partitions with partition() if partition key mode is used")
+	}
+	partitionIDs, err := getPartitionIDs(ctx, dbName, collectionName, targets)
+	if err != nil {
+		return nil, err
+	}
+	if len(requestedNames) == 0 {
+		return partitionIDs, nil
+	}
+	requestedIDs, err := getPartitionIDs(ctx, dbName, collectionName, requestedNames)
+	if err != nil {
+		return nil, err
+	}
+	requested := typeutil.NewUniqueSet(requestedIDs...)
+	targeted := make([]UniqueID, 0, len(partitionIDs))
+	for _, partitionID := range partitionIDs {
+		if requested.Contain(partitionID) {
+			targeted = append(targeted, partitionID)
+		}
+	}
+	if len(targeted) == 0 {
+		return nil, merr.WrapErrParameterInvalidMsg("the partitions %v targeted by partition() are not in the partitions of the request", targets)
+	}
+	return targeted, nil
+}
+
 type groupByInfo struct {
 	groupByFieldId  int64
 	groupSize       int64
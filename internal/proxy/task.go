@@ -30,6 +30,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/internal/util/ctokenizer"
 	"github.com/milvus-io/milvus/pkg/v2/common"
@@ -1039,6 +1040,31 @@ func (t *alterCollectionTask) PreExecute(ctx context.Context) error {
 		}
 	}
 
+	if planparserv2.DefinesMacros(t.GetProperties()) {
+		schema, err := globalMetaCache.GetCollectionSchema(ctx, t.GetDbName(), t.CollectionName)
+		if err != nil {
+			return err
+		}
+		if schema.schemaHelper == nil {
+			return merr.WrapErrServiceInternal("schema helper of collection is not ready", t.CollectionName)
+		}
+		if err := planparserv2.ValidateMacros(schema.schemaHelper, t.GetProperties()); err != nil {
+			return merr.WrapErrParameterInvalidMsg(err.Error())
+		}
+	}
+	if planparserv2.DefinesConstraints(t.GetProperties()) {
+		schema, err := globalMetaCache.GetCollectionSchema(ctx, t.GetDbName(), t.CollectionName)
+		if err != nil {
+			return err
+		}
+		if schema.schemaHelper == nil {
+			return merr.WrapErrServiceInternal("schema helper of collection is not ready", t.CollectionName)
+		}
+		if err := planparserv2.ValidateConstraints(schema.schemaHelper, t.GetProperties()); err != nil {
+			return merr.WrapErrParameterInvalidMsg(err.Error())
+		}
+	}
+
 	isPartitionKeyMode, err := isPartitionKeyMode(ctx, t.GetDbName(), t.CollectionName)
 	if err != nil {
 		return err
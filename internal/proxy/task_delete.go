@@ -27,6 +27,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util"
 	"github.com/milvus-io/milvus/pkg/v2/util/commonpbutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
@@ -347,10 +348,20 @@ func (dr *deleteRunner) Init(ctx context.Context) error {
 		return ErrWithLog(log, "Failed to get collection schema", err)
 	}
 
-	dr.plan, err = planparserv2.CreateRetrievePlan(dr.schema.schemaHelper, dr.req.GetExpr(), dr.req.GetExprTemplateValues())
+	dr.plan, err = planparserv2.CreateRetrievePlan(dr.schema.schemaHelper, dr.req.GetExpr(), dr.req.GetExprTemplateValues(),
+		planparserv2.WithDisabledOperators(paramtable.Get().ProxyCfg.DisabledExprOperators.GetAsStrings()...),
+		planparserv2.WithStrictJSONNumbers(paramtable.Get().ProxyCfg.StrictJSONNumberComparison.GetAsBool()),
+		planparserv2.WithLegacySyntax(paramtable.Get().ProxyCfg.LegacyExprSyntax.GetAsBool()),
+		planparserv2.WithMaxPlanSize(paramtable.Get().ProxyCfg.MaxPlanSize.GetAsInt64()),
+		planparserv2.WithValueSetRefs(paramtable.Get().ProxyCfg.EnableExprValueSetRefs.GetAsBool() &&
+			GetCurUserFromContextOrDefault(ctx) == util.UserRoot),
+		planparserv2.WithContext(ctx),
+		exprRole(ctx, dr.schema.schemaHelper),
+		exprLanguage(ctx))
 	if err != nil {
 		return merr.WrapErrAsInputError(merr.WrapErrParameterInvalidMsg("failed to create delete plan: %v", err))
 	}
+	recordExprFeatures(ctx, dr.plan, dr.schema, collName, metrics.DeleteLabel)
 
 	if planparserv2.IsAlwaysTruePlan(dr.plan) {
 		return merr.WrapErrAsInputError(merr.WrapErrParameterInvalidMsg("delete plan can't be empty or always true : %s", dr.req.GetExpr()))
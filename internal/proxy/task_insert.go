@@ -267,6 +267,9 @@ func (it *insertTask) PreExecute(ctx context.Context) error {
 		Validate(it.insertMsg.GetFieldsData(), schema.schemaHelper, it.insertMsg.NRows()); err != nil {
 		return merr.WrapErrAsInputError(err)
 	}
+	if err := checkConstraints(schema, it.insertMsg.GetFieldsData(), it.insertMsg.NRows()); err != nil {
+		return merr.WrapErrAsInputError(err)
+	}
 
 	log.Debug("Proxy Insert PreExecute done")
 
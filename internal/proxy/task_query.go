@@ -65,6 +65,8 @@ type queryTask struct {
 
 	plan             *planpb.PlanNode
 	partitionKeyMode bool
+	// partitionTargets are the partitions targeted by the `partition() in [...]` conjuncts of the filter.
+	partitionTargets []string
 	lb               LBPolicy
 	channelsMvcc     map[string]Timestamp
 	fastSkip         bool
@@ -73,6 +75,9 @@ type queryTask struct {
 	allQueryCnt          int64
 	totalRelatedDataSize int64
 	mustUsePartitionKey  bool
+
+	// exprWarnings are the warnings on the filter, returned in the extra info of the status.
+	exprWarnings []*planparserv2.ExprWarning
 }
 
 type queryParams struct {
@@ -224,8 +229,9 @@ func matchCountRule(outputs []string) bool {
 	return len(outputs) == 1 && strings.ToLower(strings.TrimSpace(outputs[0])) == "count(*)"
 }
 
-func createCntPlan(expr string, schemaHelper *typeutil.SchemaHelper, exprTemplateValues map[string]*schemapb.TemplateValue) (*planpb.PlanNode, error) {
-	if expr == "" {
+func createCntPlan(expr string, schemaHelper *typeutil.SchemaHelper, exprTemplateValues map[string]*schemapb.TemplateValue, opts ...planparserv2.ParseOption) (*planpb.PlanNode, error) {
+	// the empty expressions restricted by a row level policy are parsed to count the allowed rows only.
+	if expr == "" && !planparserv2.HasRowLevelPolicy(schemaHelper, opts...) {
 		return &planpb.PlanNode{
 			Node: &planpb.PlanNode_Query{
 				Query: &planpb.QueryPlanNode{
@@ -235,7 +241,14 @@ func createCntPlan(expr string, schemaHelper *typeutil.SchemaHelper, exprTemplat
 			},
 		}, nil
 	}
-	plan, err := planparserv2.CreateRetrievePlan(schemaHelper, expr, exprTemplateValues)
+	opts = append([]planparserv2.ParseOption{
+		planparserv2.WithDisabledOperators(paramtable.Get().ProxyCfg.DisabledExprOperators.GetAsStrings()...),
+		planparserv2.WithSystemFields(paramtable.Get().ProxyCfg.EnableSystemFieldExpr.GetAsBool()),
+		planparserv2.WithStrictJSONNumbers(paramtable.Get().ProxyCfg.StrictJSONNumberComparison.GetAsBool()),
+		planparserv2.WithLegacySyntax(paramtable.Get().ProxyCfg.LegacyExprSyntax.GetAsBool()),
+		planparserv2.WithMaxPlanSize(paramtable.Get().ProxyCfg.MaxPlanSize.GetAsInt64()),
+	}, opts...)
+	plan, err := planparserv2.CreateRetrievePlan(schemaHelper, expr, exprTemplateValues, opts...)
 	if err != nil {
 		return nil, merr.WrapErrAsInputError(merr.WrapErrParameterInvalidMsg("failed to create query plan: %v", err))
 	}
@@ -248,20 +261,43 @@ func createCntPlan(expr string, schemaHelper *typeutil.SchemaHelper, exprTemplat
 func (t *queryTask) createPlan(ctx context.Context) error {
 	schema := t.schema
 
+	var parseStats planparserv2.ParseStats
+	defer recordParseStats(t.request, &parseStats)
+
 	cntMatch := matchCountRule(t.request.GetOutputFields())
 	if cntMatch {
 		var err error
-		t.plan, err = createCntPlan(t.request.GetExpr(), schema.schemaHelper, t.request.GetExprTemplateValues())
+		t.plan, err = createCntPlan(t.request.GetExpr(), schema.schemaHelper, t.request.GetExprTemplateValues(),
+			planparserv2.WithContext(ctx), exprRole(ctx, schema.schemaHelper), exprRequestContext(ctx, t.request.GetDbName()),
+			exprLanguage(ctx), planparserv2.WithPartitionTargets(&t.partitionTargets), planparserv2.WithWarnings(&t.exprWarnings),
+			planparserv2.WithParseStats(&parseStats))
 		t.userOutputFields = []string{"count(*)"}
-		return err
+		if err != nil {
+			return err
+		}
+		recordExprFeatures(ctx, t.plan, schema, t.collectionName, metrics.QueryLabel)
+		return nil
 	}
 
 	var err error
 	if t.plan == nil {
-		t.plan, err = planparserv2.CreateRetrievePlan(schema.schemaHelper, t.request.Expr, t.request.GetExprTemplateValues())
+		t.plan, err = planparserv2.CreateRetrievePlan(schema.schemaHelper, t.request.Expr, t.request.GetExprTemplateValues(),
+			planparserv2.WithDisabledOperators(paramtable.Get().ProxyCfg.DisabledExprOperators.GetAsStrings()...),
+			planparserv2.WithSystemFields(paramtable.Get().ProxyCfg.EnableSystemFieldExpr.GetAsBool()),
+			planparserv2.WithStrictJSONNumbers(paramtable.Get().ProxyCfg.StrictJSONNumberComparison.GetAsBool()),
+			planparserv2.WithLegacySyntax(paramtable.Get().ProxyCfg.LegacyExprSyntax.GetAsBool()),
+			planparserv2.WithMaxPlanSize(paramtable.Get().ProxyCfg.MaxPlanSize.GetAsInt64()),
+			planparserv2.WithPartitionTargets(&t.partitionTargets),
+			planparserv2.WithWarnings(&t.exprWarnings),
+			planparserv2.WithParseStats(&parseStats),
+			planparserv2.WithContext(ctx),
+			exprRole(ctx, schema.schemaHelper),
+			exprRequestContext(ctx, t.request.GetDbName()),
+			exprLanguage(ctx))
 		if err != nil {
 			return merr.WrapErrAsInputError(merr.WrapErrParameterInvalidMsg("failed to create query plan: %v", err))
 		}
+		recordExprFeatures(ctx, t.plan, schema, t.collectionName, metrics.QueryLabel)
 	}
 
 	t.request.OutputFields, t.userOutputFields, t.userDynamicFields, err = translateOutputFields(t.request.OutputFields, t.schema, true)
@@ -406,7 +442,13 @@ func (t *queryTask) PreExecute(ctx context.Context) error {
 	}
 
 	// convert partition names only when requery is false
-	if !t.reQuery {
+	if !t.reQuery && len(t.partitionTargets) != 0 {
+		t.RetrieveRequest.PartitionIDs, err = getTargetedPartitionIDs(ctx, t.request.GetDbName(), t.request.CollectionName,
+			t.request.GetPartitionNames(), t.partitionTargets, t.partitionKeyMode)
+		if err != nil {
+			return err
+		}
+	} else if !t.reQuery {
 		partitionNames := t.request.GetPartitionNames()
 		if t.partitionKeyMode {
 			expr, err := exprutil.ParseExprFromPlan(t.plan)
@@ -32,10 +32,12 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/internal/util/reduce"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
 	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
@@ -1146,6 +1148,63 @@ func Test_queryTask_createPlan(t *testing.T) {
 		err := tsk.createPlan(context.TODO())
 		assert.Error(t, err)
 	})
+
+	t.Run("count with row level policy", func(t *testing.T) {
+		cache := globalMetaCache
+		defer func() { globalMetaCache = cache }()
+		mockCache := NewMockCache(t)
+		mockCache.EXPECT().GetUserRole("alice").Return([]string{"tenant"}).Maybe()
+		globalMetaCache = mockCache
+
+		schema := newSchemaInfo(collSchema)
+		schema.schemaHelper = schema.schemaHelper.WithEpoch("default", 1, 1)
+		planparserv2.RegisterRowLevelPolicy(1, "", "Int64Field > 2")
+		defer planparserv2.UnregisterRowLevelPolicy(1, "")
+		planparserv2.RegisterRowLevelPolicy(1, "tenant", "Int64Field < 2")
+		defer planparserv2.UnregisterRowLevelPolicy(1, "tenant")
+
+		for _, c := range []struct {
+			ctx context.Context
+			op  planpb.OpType
+		}{
+			{context.TODO(), planpb.OpType_GreaterThan},
+			{GetContext(context.Background(), "alice:123456"), planpb.OpType_LessThan},
+		} {
+			tsk := &queryTask{
+				request: &milvuspb.QueryRequest{
+					OutputFields: []string{"count(*)"},
+				},
+				schema: schema,
+			}
+			err := tsk.createPlan(c.ctx)
+			assert.NoError(t, err)
+			assert.True(t, tsk.plan.GetQuery().GetIsCount())
+			assert.Equal(t, c.op, tsk.plan.GetQuery().GetPredicates().GetUnaryRangeExpr().GetOp())
+		}
+	})
+
+	t.Run("hooks see the request context", func(t *testing.T) {
+		type ctxKey struct{}
+		ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+		var audited []interface{}
+		planparserv2.SetAuditHook(func(ctx context.Context, event *planparserv2.AuditEvent) {
+			audited = append(audited, ctx.Value(ctxKey{}))
+		})
+		defer planparserv2.SetAuditHook(nil)
+
+		for _, outputFields := range [][]string{{"Int64Field"}, {"count(*)"}} {
+			tsk := &queryTask{
+				schema: newSchemaInfo(collSchema),
+				request: &milvuspb.QueryRequest{
+					OutputFields: outputFields,
+					Expr:         "Int64Field > 2",
+				},
+			}
+			err := tsk.createPlan(ctx)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, []interface{}{"request", "request"}, audited)
+	})
 }
 
 func TestQueryTask_IDs2Expr(t *testing.T) {
@@ -88,6 +88,9 @@ type searchTask struct {
 	groupScorer func(group *Group) error
 
 	isIterator bool
+
+	// exprWarnings are the warnings on the filter, returned in the extra info of the status.
+	exprWarnings []*planparserv2.ExprWarning
 }
 
 func (t *searchTask) CanSkipAllocTimestamp() bool {
@@ -356,10 +359,14 @@ func (t *searchTask) initAdvancedSearchRequest(ctx context.Context) error {
 	t.queryInfos = make([]*planpb.QueryInfo, len(t.request.GetSubReqs()))
 	queryFieldIds := []int64{}
 	for index, subReq := range t.request.GetSubReqs() {
-		plan, queryInfo, offset, _, err := t.tryGeneratePlan(subReq.GetSearchParams(), subReq.GetDsl(), subReq.GetExprTemplateValues())
+		var parseStats planparserv2.ParseStats
+		plan, queryInfo, offset, _, err := t.tryGeneratePlan(subReq.GetSearchParams(), subReq.GetDsl(), subReq.GetExprTemplateValues(),
+			planparserv2.WithParseStats(&parseStats))
+		recordParseStats(t.request, &parseStats)
 		if err != nil {
 			return err
 		}
+		recordExprFeatures(ctx, plan, t.schema, t.collectionName, metrics.HybridSearchLabel)
 
 		ignoreGrowing := t.SearchRequest.IgnoreGrowing
 		if !ignoreGrowing {
@@ -470,10 +477,22 @@ func (t *searchTask) initSearchRequest(ctx context.Context) error {
 	log := log.Ctx(ctx).With(zap.Int64("collID", t.GetCollectionID()), zap.String("collName", t.collectionName))
 	// fetch search_growing from search param
 
-	plan, queryInfo, offset, isIterator, err := t.tryGeneratePlan(t.request.GetSearchParams(), t.request.GetDsl(), t.request.GetExprTemplateValues())
+	var partitionTargets []string
+	var parseStats planparserv2.ParseStats
+	plan, queryInfo, offset, isIterator, err := t.tryGeneratePlan(t.request.GetSearchParams(), t.request.GetDsl(), t.request.GetExprTemplateValues(),
+		planparserv2.WithPartitionTargets(&partitionTargets), planparserv2.WithWarnings(&t.exprWarnings), planparserv2.WithParseStats(&parseStats))
+	recordParseStats(t.request, &parseStats)
 	if err != nil {
 		return err
 	}
+	recordExprFeatures(ctx, plan, t.schema, t.collectionName, metrics.SearchLabel)
+	if len(partitionTargets) != 0 {
+		t.SearchRequest.PartitionIDs, err = getTargetedPartitionIDs(ctx, t.request.GetDbName(), t.collectionName,
+			t.request.GetPartitionNames(), partitionTargets, t.partitionKeyMode)
+		if err != nil {
+			return err
+		}
+	}
 
 	t.isIterator = isIterator
 	t.SearchRequest.Offset = offset
@@ -530,7 +549,7 @@ func (t *searchTask) initSearchRequest(ctx context.Context) error {
 	return nil
 }
 
-func (t *searchTask) tryGeneratePlan(params []*commonpb.KeyValuePair, dsl string, exprTemplateValues map[string]*schemapb.TemplateValue) (*planpb.PlanNode, *planpb.QueryInfo, int64, bool, error) {
+func (t *searchTask) tryGeneratePlan(params []*commonpb.KeyValuePair, dsl string, exprTemplateValues map[string]*schemapb.TemplateValue, opts ...planparserv2.ParseOption) (*planpb.PlanNode, *planpb.QueryInfo, int64, bool, error) {
 	annsFieldName, err := funcutil.GetAttrByKeyFromRepeatedKV(AnnsFieldKey, params)
 	if err != nil || len(annsFieldName) == 0 {
 		vecFields := typeutil.GetVectorFieldSchemas(t.schema.CollectionSchema)
@@ -558,7 +577,19 @@ func (t *searchTask) tryGeneratePlan(params []*commonpb.KeyValuePair, dsl string
 	}
 
 	searchInfo.planInfo.QueryFieldId = annField.GetFieldID()
-	plan, planErr := planparserv2.CreateSearchPlan(t.schema.schemaHelper, dsl, annsFieldName, searchInfo.planInfo, exprTemplateValues)
+	opts = append([]planparserv2.ParseOption{
+		planparserv2.WithDisabledOperators(paramtable.Get().ProxyCfg.DisabledExprOperators.GetAsStrings()...),
+		planparserv2.WithSystemFields(paramtable.Get().ProxyCfg.EnableSystemFieldExpr.GetAsBool()),
+		planparserv2.WithStrictJSONNumbers(paramtable.Get().ProxyCfg.StrictJSONNumberComparison.GetAsBool()),
+		planparserv2.WithLegacySyntax(paramtable.Get().ProxyCfg.LegacyExprSyntax.GetAsBool()),
+		planparserv2.WithMaxPlanSize(paramtable.Get().ProxyCfg.MaxPlanSize.GetAsInt64()),
+		planparserv2.WithTopKLimit(paramtable.Get().QuotaConfig.TopKLimit.GetAsInt64()),
+		planparserv2.WithContext(t.ctx),
+		exprRole(t.ctx, t.schema.schemaHelper),
+		exprRequestContext(t.ctx, t.request.GetDbName()),
+		exprLanguage(t.ctx),
+	}, opts...)
+	plan, planErr := planparserv2.CreateSearchPlan(t.schema.schemaHelper, dsl, annsFieldName, searchInfo.planInfo, exprTemplateValues, opts...)
 	if planErr != nil {
 		log.Ctx(t.ctx).Warn("failed to create query plan", zap.Error(planErr),
 			zap.String("dsl", dsl), // may be very large if large term passed.
@@ -907,7 +938,37 @@ func (t *searchTask) Requery(span trace.Span) error {
 		return err
 	}
 	ids := t.result.GetResults().GetIds()
-	plan := planparserv2.CreateRequeryPlan(pkField, ids)
+	// requery the ids by batches no larger than the query result window, instead of failing on large results.
+	maxIDs := Params.QuotaConfig.MaxQueryResultWindow.GetAsInt64()
+	t.result.Results.FieldsData = nil
+	start := 0
+	for remaining := ids; ; {
+		var plan *planpb.PlanNode
+		size := typeutil.GetSizeOfIDs(remaining)
+		plan, remaining = planparserv2.CreateLimitedRequeryPlan(pkField, remaining, maxIDs)
+		end := start + size - typeutil.GetSizeOfIDs(remaining)
+		if err := t.requeryBatch(span, proto.Clone(queryReq).(*milvuspb.QueryRequest), pkField, plan, ids, start, end); err != nil {
+			return err
+		}
+		if remaining == nil {
+			break
+		}
+		start = end
+	}
+
+	t.result.Results.FieldsData = lo.Filter(t.result.Results.FieldsData, func(fieldData *schemapb.FieldData, i int) bool {
+		return lo.Contains(t.request.GetOutputFields(), fieldData.GetFieldName())
+	})
+	return nil
+}
+
+// requeryBatch queries the ids in [start, end) by the plan, and appends their fields to the search results in the
+// order of the ids.
+func (t *searchTask) requeryBatch(span trace.Span, queryReq *milvuspb.QueryRequest, pkField *schemapb.FieldSchema,
+	plan *planpb.PlanNode, ids *schemapb.IDs, start, end int,
+) error {
+	log.Ctx(t.ctx).Debug("search requery", zap.Int64("collectionID", t.CollectionID),
+		zap.String("filter", planparserv2.RenderRequeryPlan(t.schema.schemaHelper, plan)))
 	channelsMvcc := make(map[string]Timestamp)
 	for k, v := range t.queryChannelsTs {
 		channelsMvcc[k] = v
@@ -965,19 +1026,17 @@ func (t *searchTask) Requery(span trace.Span) error {
 		offsets[pk] = i
 	}
 
-	t.result.Results.FieldsData = make([]*schemapb.FieldData, len(queryResult.GetFieldsData()))
-	for i := 0; i < typeutil.GetSizeOfIDs(ids); i++ {
+	if t.result.Results.FieldsData == nil {
+		t.result.Results.FieldsData = make([]*schemapb.FieldData, len(queryResult.GetFieldsData()))
+	}
+	for i := start; i < end; i++ {
 		id := typeutil.GetPK(ids, int64(i))
 		if _, ok := offsets[id]; !ok {
 			return merr.WrapErrInconsistentRequery(fmt.Sprintf("incomplete query result, missing id %s, len(searchIDs) = %d, len(queryIDs) = %d, collection=%d",
-				id, typeutil.GetSizeOfIDs(ids), len(offsets), t.GetCollectionID()))
+				id, end-start, len(offsets), t.GetCollectionID()))
 		}
 		typeutil.AppendFieldData(t.result.Results.FieldsData, queryResult.GetFieldsData(), int64(offsets[id]))
 	}
-
-	t.result.Results.FieldsData = lo.Filter(t.result.Results.FieldsData, func(fieldData *schemapb.FieldData, i int) bool {
-		return lo.Contains(t.request.GetOutputFields(), fieldData.GetFieldName())
-	})
 	return nil
 }
 
@@ -38,6 +38,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/internal/util/dependency"
 	"github.com/milvus-io/milvus/internal/util/function"
@@ -309,6 +310,25 @@ func getInvalidSearchParams(invalidName string) []*commonpb.KeyValuePair {
 	return kvs
 }
 
+func TestSearchTask_tryGeneratePlanContext(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	var audited []interface{}
+	planparserv2.SetAuditHook(func(ctx context.Context, event *planparserv2.AuditEvent) {
+		audited = append(audited, ctx.Value(ctxKey{}))
+	})
+	defer planparserv2.SetAuditHook(nil)
+
+	task := &searchTask{
+		ctx:     ctx,
+		request: &milvuspb.SearchRequest{},
+		schema:  newSchemaInfo(constructCollectionSchema(testInt64Field, testFloatVecField, testVecDim, "test")),
+	}
+	_, _, _, _, err := task.tryGeneratePlan(getValidSearchParams(), testInt64Field+" > 0", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"request"}, audited)
+}
+
 func TestSearchTask_PreExecute(t *testing.T) {
 	var err error
 
@@ -246,6 +246,9 @@ func (it *upsertTask) insertPreExecute(ctx context.Context) error {
 		Validate(it.upsertMsg.InsertMsg.GetFieldsData(), it.schema.schemaHelper, it.upsertMsg.InsertMsg.NRows()); err != nil {
 		return err
 	}
+	if err := checkConstraints(it.schema, it.upsertMsg.InsertMsg.GetFieldsData(), it.upsertMsg.InsertMsg.NRows()); err != nil {
+		return err
+	}
 
 	log.Debug("Proxy Upsert insertPreExecute done")
 
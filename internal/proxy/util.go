@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -38,12 +39,14 @@ import (
 	"github.com/milvus-io/milvus/internal/json"
 	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/exprutil"
 	"github.com/milvus-io/milvus/internal/util/function"
 	"github.com/milvus-io/milvus/internal/util/hookutil"
 	"github.com/milvus-io/milvus/internal/util/indexparamcheck"
 	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/metrics"
 	"github.com/milvus-io/milvus/pkg/v2/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
@@ -54,6 +57,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/v2/util/crypto"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/v2/util/tsoutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
@@ -1153,6 +1157,50 @@ func GetCurUserFromContextOrDefault(ctx context.Context) string {
 	return username
 }
 
+// exprRequestContext returns the option substituting current_user(), current_db() and request_time() in expressions.
+func exprRequestContext(ctx context.Context, dbName string) planparserv2.ParseOption {
+	if dbName == "" {
+		dbName = GetCurDBNameFromContextOrDefault(ctx)
+	}
+	return planparserv2.WithRequestContext(planparserv2.RequestContext{
+		User:     GetCurUserFromContextOrDefault(ctx),
+		Database: dbName,
+		Time:     time.Now(),
+	})
+}
+
+// exprRole returns the option setting the role whose row level policy restricts the expressions of the request on
+// the collection. A user with several roles is restricted by the first of them, in name order, with a policy on the
+// collection, and the requests without roles, like the ones without authentication, by the policy of the empty role.
+func exprRole(ctx context.Context, schema *typeutil.SchemaHelper) planparserv2.ParseOption {
+	username, err := GetCurUserFromContext(ctx)
+	if err != nil || globalMetaCache == nil {
+		return planparserv2.WithRole("", nil)
+	}
+	roles := lo.Uniq(globalMetaCache.GetUserRole(username))
+	if len(roles) == 0 {
+		return planparserv2.WithRole("", nil)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		if planparserv2.HasRowLevelPolicy(schema, planparserv2.WithRole(role, nil)) {
+			return planparserv2.WithRole(role, nil)
+		}
+	}
+	return planparserv2.WithRole(roles[0], nil)
+}
+
+// exprLanguage renders the parse errors in the preferred language of the client, if it sent any.
+func exprLanguage(ctx context.Context) planparserv2.ParseOption {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[util.HeaderLanguage]) == 0 {
+		return planparserv2.WithLanguage("")
+	}
+	lang, _, _ := strings.Cut(md[util.HeaderLanguage][0], ",")
+	lang, _, _ = strings.Cut(lang, ";")
+	return planparserv2.WithLanguage(strings.TrimSpace(lang))
+}
+
 func GetCurDBNameFromContextOrDefault(ctx context.Context) string {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -2117,6 +2165,32 @@ func SetReportValue(status *commonpb.Status, value int) {
 	status.ExtraInfo["report_value"] = strconv.Itoa(value)
 }
 
+// SetExprWarnings returns the warnings on the filter of a successful request in the extra info of its status,
+// one warning per line.
+func SetExprWarnings(status *commonpb.Status, warnings []*planparserv2.ExprWarning) {
+	if len(warnings) == 0 || !merr.Ok(status) {
+		return
+	}
+	if status.ExtraInfo == nil {
+		status.ExtraInfo = make(map[string]string)
+	}
+	lines := lo.Map(warnings, func(warning *planparserv2.ExprWarning, _ int) string {
+		return warning.String()
+	})
+	status.ExtraInfo["expr_warnings"] = strings.Join(lines, "\n")
+}
+
+// recordParseStats reports the cost of parsing the filter of the request to the rate collector, so that the quota
+// center can throttle the collections with expensive filters. Nothing is reported if no expression was parsed.
+func recordParseStats(req any, stats *planparserv2.ParseStats) {
+	if rateCol == nil || stats.CPUTime == 0 {
+		return
+	}
+	subLabel := GetCollectionRateSubLabel(req)
+	rateCol.Add(metricsinfo.ParseTime, float64(stats.CPUTime)/float64(time.Millisecond), subLabel)
+	rateCol.Add(metricsinfo.ParseNodes, float64(stats.Nodes), subLabel)
+}
+
 func GetCostValue(status *commonpb.Status) int {
 	if status == nil || status.ExtraInfo == nil {
 		return 0
@@ -2283,3 +2357,35 @@ func IsBM25FunctionOutputField(field *schemapb.FieldSchema, collSchema *schemapb
 	}
 	return false
 }
+
+// checkConstraints rejects the rows which don't match the constraints defined by the properties of the collection.
+func checkConstraints(schema *schemaInfo, fieldsData []*schemapb.FieldData, numRows uint64) error {
+	if !planparserv2.DefinesConstraints(schema.GetProperties()) {
+		return nil
+	}
+	constraints, err := schema.GetConstraints()
+	if err != nil {
+		return err
+	}
+	return constraints.CheckColumns(fieldsData, int(numRows))
+}
+
+// recordExprFeatures counts the expression features used by the filter of the plan, if the telemetry is enabled.
+func recordExprFeatures(ctx context.Context, plan *planpb.PlanNode, schema *schemaInfo, collectionName string, queryType string) {
+	if !paramtable.Get().ProxyCfg.ExprFeatureTelemetry.GetAsBool() {
+		return
+	}
+	expr, err := exprutil.ParseExprFromPlan(plan)
+	if err != nil || expr == nil {
+		return
+	}
+	features, err := planparserv2.ExprFeatures(schema.schemaHelper, expr)
+	if err != nil {
+		log.Ctx(ctx).Warn("failed to collect expression features", zap.String("collection", collectionName), zap.Error(err))
+		return
+	}
+	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
+	for _, feature := range features {
+		metrics.ProxyExprFeatureCount.WithLabelValues(nodeID, queryType, collectionName, feature).Inc()
+	}
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/json"
 	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/mq/msgstream"
@@ -46,7 +47,9 @@ import (
 	"github.com/milvus-io/milvus/pkg/v2/util"
 	"github.com/milvus-io/milvus/pkg/v2/util/crypto"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/ratelimitutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/tsoutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
 )
@@ -919,6 +922,43 @@ func TestGetRole(t *testing.T) {
 	assert.Equal(t, 1, len(roles))
 }
 
+func TestExprRole(t *testing.T) {
+	cache := globalMetaCache
+	defer func() { globalMetaCache = cache }()
+	mockCache := NewMockCache(t)
+	mockCache.EXPECT().GetUserRole("alice").Return([]string{"writer", "tenant", "reader"}).Maybe()
+	mockCache.EXPECT().GetUserRole("bob").Return(nil).Maybe()
+	globalMetaCache = mockCache
+
+	helper, err := typeutil.CreateSchemaHelper(newTestSchema())
+	assert.NoError(t, err)
+	restricted := helper.WithEpoch("default", 1, 1)
+	unrestricted := helper.WithEpoch("default", 2, 1)
+	planparserv2.RegisterRowLevelPolicy(1, "tenant", "Int64Field == 1")
+	defer planparserv2.UnregisterRowLevelPolicy(1, "tenant")
+
+	var audited string
+	planparserv2.SetAuditHook(func(ctx context.Context, event *planparserv2.AuditEvent) {
+		audited = event.Role
+	})
+	defer planparserv2.SetAuditHook(nil)
+	role := func(ctx context.Context, schema *typeutil.SchemaHelper) string {
+		audited = "unset"
+		_, err := planparserv2.ParseExpr(schema, "Int32Field > 1", nil, exprRole(ctx, schema))
+		assert.NoError(t, err)
+		return audited
+	}
+
+	alice := GetContext(context.Background(), "alice:123456")
+	bob := GetContext(context.Background(), "bob:123456")
+	// the role with a policy on the collection, or the first role by name.
+	assert.Equal(t, "tenant", role(alice, restricted))
+	assert.Equal(t, "reader", role(alice, unrestricted))
+	// the users without roles and the requests without users have the empty role.
+	assert.Equal(t, "", role(bob, restricted))
+	assert.Equal(t, "", role(context.Background(), restricted))
+}
+
 func TestPasswordVerify(t *testing.T) {
 	username := "user-test00"
 	password := "PasswordVerify"
@@ -3493,3 +3533,77 @@ func BenchmarkCheckVarcharFormat(b *testing.B) {
 		checkInputUtf8Compatiable(schema, data)
 	}
 }
+
+func TestSetExprWarnings(t *testing.T) {
+	status := merr.Success()
+	SetExprWarnings(status, nil)
+	assert.Nil(t, status.GetExtraInfo())
+
+	warnings := []*planparserv2.ExprWarning{
+		{Code: planparserv2.WarnCodeFloatEquality, Message: "a"},
+		{Code: planparserv2.WarnCodeLeadingWildcard, Message: "b"},
+	}
+	SetExprWarnings(status, warnings)
+	assert.Equal(t, "float_equality: a\nleading_wildcard: b", status.GetExtraInfo()["expr_warnings"])
+
+	status = merr.Status(merr.ErrParameterInvalid)
+	SetExprWarnings(status, warnings)
+	assert.NotContains(t, status.GetExtraInfo(), "expr_warnings")
+}
+
+func TestRecordParseStats(t *testing.T) {
+	oldRateCol := rateCol
+	defer func() {
+		rateCol = oldRateCol
+	}()
+	var err error
+	rateCol, err = ratelimitutil.NewRateCollector(ratelimitutil.DefaultWindow, ratelimitutil.DefaultGranularity, false)
+	assert.NoError(t, err)
+	rateCol.Register(metricsinfo.ParseTime)
+	rateCol.Register(metricsinfo.ParseNodes)
+
+	req := &milvuspb.QueryRequest{DbName: "db", CollectionName: "coll"}
+	recordParseStats(req, &planparserv2.ParseStats{})
+	recordParseStats(req, &planparserv2.ParseStats{CPUTime: 2 * time.Millisecond, Nodes: 3})
+
+	subLabel := ratelimitutil.FormatSubLabel(metricsinfo.ParseNodes, ratelimitutil.GetCollectionSubLabel("db", "coll"))
+	rates, err := rateCol.RateSubLabel(metricsinfo.ParseNodes, ratelimitutil.DefaultAvgDuration)
+	assert.NoError(t, err)
+	assert.Positive(t, rates[subLabel])
+	rates, err = rateCol.RateSubLabel(metricsinfo.ParseTime, ratelimitutil.DefaultAvgDuration)
+	assert.NoError(t, err)
+	assert.Len(t, rates, 1)
+}
+
+func TestCheckConstraints(t *testing.T) {
+	collSchema := &schemapb.CollectionSchema{
+		Name: "constraints",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+		},
+		Properties: []*commonpb.KeyValuePair{
+			{Key: planparserv2.ConstraintKeyPrefix + "positive", Value: "pk > 0"},
+		},
+	}
+	schema := newSchemaInfo(collSchema)
+	fieldsData := []*schemapb.FieldData{
+		{
+			Type: schemapb.DataType_Int64, FieldId: 100,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{1, 0}}},
+			}},
+		},
+	}
+	assert.NoError(t, checkConstraints(schema, fieldsData, 1))
+	assert.ErrorContains(t, checkConstraints(schema, fieldsData, 2), "row 1 violates constraint positive")
+
+	// the constraints are compiled once per schema.
+	first, err := schema.GetConstraints()
+	assert.NoError(t, err)
+	second, err := schema.GetConstraints()
+	assert.NoError(t, err)
+	assert.Len(t, first, 1)
+	assert.Same(t, first[0], second[0])
+
+	assert.NoError(t, checkConstraints(newSchemaInfo(newTestSchema()), fieldsData, 2))
+}
@@ -738,9 +738,87 @@ func (q *QuotaCenter) calculateReadRates() error {
 	if len(deniedDatabaseIDs) != 0 {
 		q.forceDenyReading(commonpb.ErrorCode_ForceDeny, false, maps.Keys(deniedDatabaseIDs), log)
 	}
+
+	parseTimeFactors := q.getParseTimeFactor()
+	if len(parseTimeFactors) == 0 {
+		return nil
+	}
+	searchRates := q.getCollectionRealTimeRates(internalpb.RateType_DQLSearch.String())
+	queryRates := q.getCollectionRealTimeRates(internalpb.RateType_DQLQuery.String())
+	for collection, factor := range parseTimeFactors {
+		dbID, ok := q.collectionIDToDBID.Get(collection)
+		if !ok {
+			log.Warn("cannot find db for collection", zap.Int64("collection", collection))
+			continue
+		}
+		collectionLimiter := q.rateLimiter.GetCollectionLimiters(dbID, collection)
+		if collectionLimiter == nil {
+			return fmt.Errorf("collection limiter not found: %d", collection)
+		}
+
+		// the limits are lowered from the current rates, since the dql limits are usually unlimited.
+		limiter := collectionLimiter.GetLimiters()
+		for rt, rate := range map[internalpb.RateType]float64{
+			internalpb.RateType_DQLSearch: searchRates[collection],
+			internalpb.RateType_DQLQuery:  queryRates[collection],
+		} {
+			v, ok := limiter.Get(rt)
+			if ok && rate > 0 && v.Limit() > Limit(rate*factor) {
+				v.SetLimit(Limit(rate * factor))
+			}
+		}
+
+		collectionProps := q.getCollectionLimitProperties(collection)
+		q.guaranteeMinRate(getCollectionRateLimitConfig(collectionProps, common.CollectionSearchRateMinKey),
+			internalpb.RateType_DQLSearch, collectionLimiter)
+		q.guaranteeMinRate(getCollectionRateLimitConfig(collectionProps, common.CollectionQueryRateMinKey),
+			internalpb.RateType_DQLQuery, collectionLimiter)
+		log.RatedDebug(10, "QuotaCenter cool read rates off for parse time done",
+			zap.Int64("collectionID", collection),
+			zap.Float64("factor", factor))
+	}
 	return nil
 }
 
+// getParseTimeFactor returns the factors lowering the dql rates of the collections whose filters take the proxies
+// longer to parse than the parse time quota.
+func (q *QuotaCenter) getParseTimeFactor() map[int64]float64 {
+	maxRate := Params.QuotaConfig.ParseTimeMaxRate.GetAsFloat()
+	if maxRate <= 0 {
+		return nil
+	}
+	factors := make(map[int64]float64)
+	for collection, rate := range q.getCollectionRealTimeRates(metricsinfo.ParseTime) {
+		if rate > maxRate {
+			factors[collection] = maxRate / rate
+		}
+	}
+	return factors
+}
+
+// getCollectionRealTimeRates sums the collection rates of the label reported by the proxies.
+func (q *QuotaCenter) getCollectionRealTimeRates(label string) map[int64]float64 {
+	rates := make(map[int64]float64)
+	for _, metric := range q.proxyMetrics {
+		for _, r := range metric.Rms {
+			mainLabel, database, collectionName, ok := ratelimitutil.SplitCollectionSubLabel(r.Label)
+			if !ok || mainLabel != label {
+				continue
+			}
+			dbID, ok := q.dbs.Get(database)
+			if !ok {
+				continue
+			}
+			collection, ok := q.collections.Get(FormatCollectionKey(dbID, collectionName))
+			if !ok {
+				continue
+			}
+			rates[collection] += r.Rate
+		}
+	}
+	return rates
+}
+
 func (q *QuotaCenter) getDenyWritingDBs() map[int64]struct{} {
 	dbIDs := make(map[int64]struct{})
 	for _, dbID := range lo.Uniq(q.collectionIDToDBID.Values()) {
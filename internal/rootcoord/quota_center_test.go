@@ -676,6 +676,57 @@ func TestQuotaCenter(t *testing.T) {
 		assert.Equal(t, Limit(0), b.Limit())
 	})
 
+	t.Run("test parse time factors", func(t *testing.T) {
+		qc := mocks.NewMockQueryCoordClient(t)
+		meta := mockrootcoord.NewIMetaTable(t)
+		meta.EXPECT().GetDatabaseByID(mock.Anything, mock.Anything, mock.Anything).Return(nil, merr.ErrDatabaseNotFound).Maybe()
+		meta.EXPECT().GetCollectionByIDWithMaxTs(mock.Anything, mock.Anything).Return(nil, merr.ErrCollectionNotFound).Maybe()
+
+		quotaCenter := NewQuotaCenter(pcm, qc, dc, core.tsoAllocator, meta)
+		quotaCenter.clearMetrics()
+		quotaCenter.collectionIDToDBID = collectionIDToDBID
+		quotaCenter.readableCollections = map[int64]map[int64][]int64{
+			0: {1: {}, 2: {}},
+		}
+		quotaCenter.dbs.Insert("default", 0)
+		quotaCenter.collections.Insert(FormatCollectionKey(0, "col1"), 1)
+		quotaCenter.collections.Insert(FormatCollectionKey(0, "col2"), 2)
+
+		subLabel := func(label, collection string) string {
+			return ratelimitutil.FormatSubLabel(label, ratelimitutil.GetCollectionSubLabel("default", collection))
+		}
+		quotaCenter.proxyMetrics = map[UniqueID]*metricsinfo.ProxyQuotaMetrics{
+			1: {Rms: []metricsinfo.RateMetric{
+				{Label: subLabel(metricsinfo.ParseTime, "col1"), Rate: 150},
+				{Label: subLabel(metricsinfo.ParseTime, "col2"), Rate: 50},
+				{Label: subLabel(internalpb.RateType_DQLSearch.String(), "col1"), Rate: 10},
+				{Label: subLabel(internalpb.RateType_DQLQuery.String(), "col1"), Rate: 20},
+			}},
+			2: {Rms: []metricsinfo.RateMetric{
+				{Label: subLabel(metricsinfo.ParseTime, "col1"), Rate: 50},
+			}},
+		}
+
+		paramtable.Get().Save(Params.QuotaConfig.ParseTimeMaxRate.Key, "0")
+		assert.Empty(t, quotaCenter.getParseTimeFactor())
+
+		paramtable.Get().Save(Params.QuotaConfig.ParseTimeMaxRate.Key, "100")
+		defer paramtable.Get().Reset(Params.QuotaConfig.ParseTimeMaxRate.Key)
+		assert.Equal(t, map[int64]float64{1: 0.5}, quotaCenter.getParseTimeFactor())
+
+		quotaCenter.resetAllCurrentRates()
+		err = quotaCenter.calculateReadRates()
+		assert.NoError(t, err)
+		limiters := quotaCenter.rateLimiter.GetCollectionLimiters(0, 1).GetLimiters()
+		a, _ := limiters.Get(internalpb.RateType_DQLSearch)
+		assert.Equal(t, Limit(5), a.Limit())
+		b, _ := limiters.Get(internalpb.RateType_DQLQuery)
+		assert.Equal(t, Limit(10), b.Limit())
+		limiters = quotaCenter.rateLimiter.GetCollectionLimiters(0, 2).GetLimiters()
+		a, _ = limiters.Get(internalpb.RateType_DQLSearch)
+		assert.Equal(t, Inf, a.Limit())
+	})
+
 	t.Run("test calculateWriteRates", func(t *testing.T) {
 		qc := mocks.NewMockQueryCoordClient(t)
 		meta := mockrootcoord.NewIMetaTable(t)
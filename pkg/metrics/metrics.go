@@ -124,6 +124,7 @@ const (
 	pathLabelName            = "path"
 	cgoNameLabelName         = `cgo_name`
 	cgoTypeLabelName         = `cgo_type`
+	exprFeatureLabelName     = "expr_feature"
 
 	// entities label
 	LoadedLabel         = "loaded"
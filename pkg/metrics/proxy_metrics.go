@@ -418,6 +418,15 @@ var (
 			Help:      "counter of recall search",
 		}, []string{nodeIDLabelName, queryTypeLabelName, collectionName})
 
+	// ProxyExprFeatureCount records the expression features used by the requests of each collection, when enabled
+	ProxyExprFeatureCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "expr_feature_count",
+			Help:      "counter of requests using each expression feature",
+		}, []string{nodeIDLabelName, queryTypeLabelName, collectionName, exprFeatureLabelName})
+
 	// ProxySearchSparseNumNonZeros records the estimated number of non-zeros in each sparse search task
 	ProxySearchSparseNumNonZeros = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -488,6 +497,7 @@ func RegisterProxy(registry *prometheus.Registry) {
 	registry.MustRegister(ProxyRetrySearchCount)
 	registry.MustRegister(ProxyRetrySearchResultInsufficientCount)
 	registry.MustRegister(ProxyRecallSearchCount)
+	registry.MustRegister(ProxyExprFeatureCount)
 
 	registry.MustRegister(ProxySearchSparseNumNonZeros)
 
@@ -620,4 +630,8 @@ func CleanupProxyCollectionMetrics(nodeID int64, collection string) {
 		queryTypeLabelName: SearchLabel,
 		collectionName:     collection,
 	})
+	ProxyExprFeatureCount.DeletePartialMatch(prometheus.Labels{
+		nodeIDLabelName: strconv.FormatInt(nodeID, 10),
+		collectionName:  collection,
+	})
 }
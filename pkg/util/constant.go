@@ -70,6 +70,8 @@ const (
 
 	HeaderUserAgent = "user-agent"
 	HeaderDBName    = "dbName"
+	// HeaderLanguage carries the languages the client accepts error messages in, like "fr-CH, fr;q=0.9".
+	HeaderLanguage = "accept-language"
 
 	RoleConfigPrivileges = "privileges"
 	RoleConfigObjectType = "object_type"
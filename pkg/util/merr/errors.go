@@ -72,6 +72,7 @@ var (
 	ErrCollectionVectorClusteringKeyNotAllowed = newMilvusError("vector clustering key not allowed", 107, false)
 	ErrCollectionReplicateMode                 = newMilvusError("can't operate on the collection under standby mode", 108, false)
 	ErrCollectionSchemaMismatch                = newMilvusError("collection schema mismatch", 109, false)
+	ErrCollectionSchemaOutdated                = newMilvusError("collection schema outdated", 110, true)
 	// Partition related
 	ErrPartitionNotFound       = newMilvusError("partition not found", 200, false)
 	ErrPartitionNotLoaded      = newMilvusError("partition not loaded", 201, false)
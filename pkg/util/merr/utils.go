@@ -561,6 +561,15 @@ func WrapErrCollectionSchemaMisMatch(collection any, msg ...string) error {
 	return err
 }
 
+// WrapErrCollectionSchemaOutdated wraps ErrCollectionSchemaOutdated with collection
+func WrapErrCollectionSchemaOutdated(collection any, msg ...string) error {
+	err := wrapFields(ErrCollectionSchemaOutdated, value("collection", collection))
+	if len(msg) > 0 {
+		err = errors.Wrap(err, strings.Join(msg, "->"))
+	}
+	return err
+}
+
 func WrapErrAliasNotFound(db any, alias any, msg ...string) error {
 	err := wrapFields(ErrAliasNotFound,
 		value("database", db),
@@ -27,6 +27,10 @@ const (
 	ReadResultThroughput    RateMetricLabel = "ReadResultThroughput"
 	InsertConsumeThroughput RateMetricLabel = "InsertConsumeThroughput"
 	DeleteConsumeThroughput RateMetricLabel = "DeleteConsumeThroughput"
+	// ParseTime is the time spent by the proxies parsing the expressions of search and query, in milliseconds.
+	ParseTime RateMetricLabel = "DQLParseTime"
+	// ParseNodes is the number of nodes of the expressions parsed by the proxies for search and query.
+	ParseNodes RateMetricLabel = "DQLParseNodes"
 )
 
 const (
@@ -1358,6 +1358,15 @@ type proxyConfig struct {
 	SkipPartitionKeyCheck        ParamItem `refreshable:"true"`
 	MaxVarCharLength             ParamItem `refreshable:"false"`
 	MaxTextLength                ParamItem `refreshable:"false"`
+	DisabledExprOperators        ParamItem `refreshable:"true"`
+	EnableSystemFieldExpr        ParamItem `refreshable:"true"`
+	RedactExprLiterals           ParamItem `refreshable:"true"`
+	ExprUnicodeConversion        ParamItem `refreshable:"true"`
+	StrictJSONNumberComparison   ParamItem `refreshable:"true"`
+	LegacyExprSyntax             ParamItem `refreshable:"true"`
+	MaxPlanSize                  ParamItem `refreshable:"true"`
+	ExprFeatureTelemetry         ParamItem `refreshable:"true"`
+	EnableExprValueSetRefs       ParamItem `refreshable:"true"`
 
 	AccessLog AccessLogConfig
 
@@ -1778,6 +1787,78 @@ please adjust in embedded Milvus: false`,
 	}
 	p.MaxTextLength.Init(base.mgr)
 
+	p.DisabledExprOperators = ParamItem{
+		Key:          "proxy.disabledExprOperators",
+		Version:      "2.6.0",
+		DefaultValue: "",
+		Doc:          "comma separated operators disabled in filter expressions, such as regex, random_sample or call",
+	}
+	p.DisabledExprOperators.Init(base.mgr)
+
+	p.EnableSystemFieldExpr = ParamItem{
+		Key:          "proxy.enableSystemFieldExpr",
+		Version:      "2.6.0",
+		DefaultValue: "false",
+		Doc:          "allow filtering on the insert timestamp and row id of entities with timestamp() and row_id() in query and search expressions",
+	}
+	p.EnableSystemFieldExpr.Init(base.mgr)
+
+	p.RedactExprLiterals = ParamItem{
+		Key:          "proxy.redactExprLiterals",
+		Version:      "2.6.0",
+		DefaultValue: "false",
+		Doc:          "mask the string and numeric literals of filter expressions in the logs and errors of the expression parser",
+	}
+	p.RedactExprLiterals.Init(base.mgr)
+
+	p.ExprUnicodeConversion = ParamItem{
+		Key:          "proxy.exprUnicodeConversion",
+		Version:      "2.6.0",
+		DefaultValue: "han",
+		Doc:          "how non-ASCII characters of filter expressions are converted before lexing: han escapes Han characters only, full escapes all non-ASCII characters, native lexes them as is",
+	}
+	p.ExprUnicodeConversion.Init(base.mgr)
+
+	p.StrictJSONNumberComparison = ParamItem{
+		Key:          "proxy.strictJSONNumberComparison",
+		Version:      "2.6.0",
+		DefaultValue: "false",
+		Doc:          "reject comparing JSON values to integers beyond ±2^53 in filter expressions, which JSON numbers stored as double can't tell apart",
+	}
+	p.StrictJSONNumberComparison.Init(base.mgr)
+
+	p.LegacyExprSyntax = ParamItem{
+		Key:          "proxy.legacyExprSyntax",
+		Version:      "2.6.0",
+		DefaultValue: "false",
+		Doc:          "accept the v1 spellings of the search, query and delete expressions, like `=`, `<>` or `And`, which are translated to the v2 syntax with deprecation warnings",
+	}
+	p.LegacyExprSyntax.Init(base.mgr)
+
+	p.MaxPlanSize = ParamItem{
+		Key:          "proxy.maxPlanSize",
+		Version:      "2.6.0",
+		DefaultValue: "0",
+		Doc:          "maximum serialized size in bytes of the plans built from search, query and delete expressions, 0 means unlimited",
+	}
+	p.MaxPlanSize.Init(base.mgr)
+
+	p.ExprFeatureTelemetry = ParamItem{
+		Key:          "proxy.exprFeatureTelemetry",
+		Version:      "2.6.0",
+		DefaultValue: "false",
+		Doc:          "count the expression features, like regex, json paths, array operators or templates, used by the searches, queries and deletes of each collection",
+	}
+	p.ExprFeatureTelemetry.Init(base.mgr)
+
+	p.EnableExprValueSetRefs = ParamItem{
+		Key:          "proxy.enableExprValueSetRefs",
+		Version:      "2.6.0",
+		DefaultValue: "false",
+		Doc:          "allow the root user to delete the entities listed in a file with `pk in ref(\"s3://bucket/ids.parquet\")`, once a resolver of the references is registered",
+	}
+	p.EnableExprValueSetRefs.Init(base.mgr)
+
 	p.GracefulStopTimeout = ParamItem{
 		Key:          "proxy.gracefulStopTimeout",
 		Version:      "2.3.7",
@@ -164,6 +164,7 @@ type quotaConfig struct {
 
 	// limit reading
 	ForceDenyReading ParamItem `refreshable:"true"`
+	ParseTimeMaxRate ParamItem `refreshable:"true"`
 }
 
 func (p *quotaConfig) init(base *BaseTable) {
@@ -1987,6 +1988,22 @@ specific conditions, such as collection has been dropped), ` + "true" + ` means
 	}
 	p.ForceDenyReading.Init(base.mgr)
 
+	p.ParseTimeMaxRate = ParamItem{
+		Key:          "quotaAndLimits.limitReading.parseTime.maxRate",
+		Version:      "2.6.0",
+		DefaultValue: "0",
+		Formatter: func(v string) string {
+			if getAsFloat(v) < 0 {
+				return "0"
+			}
+			return v
+		},
+		Doc: `maxRate is the time in milliseconds per second the proxies may spend parsing the filters of the dql requests
+on a collection, the dql rates of the collection are lowered in proportion once exceeded. 0 means no limit.`,
+		Export: true,
+	}
+	p.ParseTimeMaxRate.Init(base.mgr)
+
 	p.AllocRetryTimes = ParamItem{
 		Key:          "quotaAndLimits.limits.allocRetryTimes",
 		Version:      "2.4.0",
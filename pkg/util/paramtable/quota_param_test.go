@@ -205,6 +205,7 @@ func TestQuotaParam(t *testing.T) {
 
 	t.Run("test limit reading", func(t *testing.T) {
 		assert.False(t, qc.ForceDenyReading.GetAsBool())
+		assert.Equal(t, float64(0), qc.ParseTimeMaxRate.GetAsFloat())
 	})
 
 	t.Run("test disk quota", func(t *testing.T) {
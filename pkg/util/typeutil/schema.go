@@ -276,6 +276,12 @@ type SchemaHelper struct {
 	clusteringKeyOffset int
 	dynamicFieldOffset  int
 	loadFields          Set[int64]
+	// aliasOffset maps the user-defined aliases to the fields.
+	aliasOffset map[string]int
+	// the collection and epoch identify the schema snapshot, epoch 0 means unknown.
+	database     string
+	collectionID int64
+	epoch        uint64
 }
 
 func CreateSchemaHelperWithLoadFields(schema *schemapb.CollectionSchema, loadFields []int64) (*SchemaHelper, error) {
@@ -373,16 +379,47 @@ func (helper *SchemaHelper) GetDynamicField() (*schemapb.FieldSchema, error) {
 
 // GetFieldFromName is used to find the schema by field name
 func (helper *SchemaHelper) GetFieldFromName(fieldName string) (*schemapb.FieldSchema, error) {
-	offset, ok := helper.nameOffset[fieldName]
+	offset, ok := helper.getNameOffset(fieldName)
 	if !ok {
 		return nil, fmt.Errorf("failed to get field schema by name: fieldName(%s) not found", fieldName)
 	}
 	return helper.schema.Fields[offset], nil
 }
 
+// getNameOffset resolves the field name, or one of its aliases.
+func (helper *SchemaHelper) getNameOffset(fieldName string) (int, bool) {
+	if offset, ok := helper.nameOffset[fieldName]; ok {
+		return offset, true
+	}
+	offset, ok := helper.aliasOffset[fieldName]
+	return offset, ok
+}
+
+// WithFieldAliases returns a copy of the helper which also resolves the fields by the aliases,
+// the aliases map alias to field name. An alias cannot shadow a field name, nor refer to the dynamic field.
+func (helper *SchemaHelper) WithFieldAliases(aliases map[string]string) (*SchemaHelper, error) {
+	aliasOffset := make(map[string]int, len(aliases))
+	for alias, fieldName := range aliases {
+		if _, ok := helper.nameOffset[alias]; ok {
+			return nil, fmt.Errorf("alias %s conflicts with field name", alias)
+		}
+		offset, ok := helper.nameOffset[fieldName]
+		if !ok {
+			return nil, fmt.Errorf("failed to set alias %s: fieldName(%s) not found", alias, fieldName)
+		}
+		if helper.schema.Fields[offset].GetIsDynamic() {
+			return nil, fmt.Errorf("failed to set alias %s: dynamic field cannot be aliased", alias)
+		}
+		aliasOffset[alias] = offset
+	}
+	ret := *helper
+	ret.aliasOffset = aliasOffset
+	return &ret, nil
+}
+
 // GetFieldFromNameDefaultJSON is used to find the schema by field name, if not exist, use json field
 func (helper *SchemaHelper) GetFieldFromNameDefaultJSON(fieldName string) (*schemapb.FieldSchema, error) {
-	offset, ok := helper.nameOffset[fieldName]
+	offset, ok := helper.getNameOffset(fieldName)
 	if !ok {
 		return helper.getDefaultJSONField(fieldName)
 	}
@@ -483,6 +520,35 @@ func (helper *SchemaHelper) GetCollectionName() string {
 	return helper.schema.Name
 }
 
+// GetSchema returns the collection schema held by the helper.
+func (helper *SchemaHelper) GetSchema() *schemapb.CollectionSchema {
+	return helper.schema
+}
+
+// GetDatabaseName returns the database of the collection set by WithEpoch.
+func (helper *SchemaHelper) GetDatabaseName() string {
+	return helper.database
+}
+
+// GetCollectionID returns the collection id set by WithEpoch.
+func (helper *SchemaHelper) GetCollectionID() int64 {
+	return helper.collectionID
+}
+
+// GetEpoch returns the epoch of the schema snapshot, which grows when the schema is altered.
+func (helper *SchemaHelper) GetEpoch() uint64 {
+	return helper.epoch
+}
+
+// WithEpoch returns a copy of the helper tagged with the collection and the epoch of the schema snapshot.
+func (helper *SchemaHelper) WithEpoch(database string, collectionID int64, epoch uint64) *SchemaHelper {
+	ret := *helper
+	ret.database = database
+	ret.collectionID = collectionID
+	ret.epoch = epoch
+	return &ret
+}
+
 func IsBinaryVectorType(dataType schemapb.DataType) bool {
 	return dataType == schemapb.DataType_BinaryVector
 }
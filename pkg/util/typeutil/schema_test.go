@@ -498,6 +498,65 @@ func TestSchemaHelper_GetDynamicField(t *testing.T) {
 	})
 }
 
+func TestSchemaHelper_WithEpoch(t *testing.T) {
+	sch := &schemapb.CollectionSchema{
+		Name: "testColl",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "field_int64", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+		},
+	}
+	helper, err := CreateSchemaHelper(sch)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), helper.GetEpoch())
+
+	tagged := helper.WithEpoch("db", 1, 10)
+	assert.Equal(t, "db", tagged.GetDatabaseName())
+	assert.Equal(t, int64(1), tagged.GetCollectionID())
+	assert.Equal(t, uint64(10), tagged.GetEpoch())
+	// the original helper is not modified.
+	assert.Equal(t, uint64(0), helper.GetEpoch())
+
+	f, err := tagged.GetPrimaryKeyField()
+	assert.NoError(t, err)
+	assert.Equal(t, "field_int64", f.GetName())
+}
+
+func TestSchemaHelper_WithFieldAliases(t *testing.T) {
+	sch := &schemapb.CollectionSchema{
+		Name: "testColl",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "field_int64", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "field_json", DataType: schemapb.DataType_JSON},
+			{FieldID: 102, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+		},
+	}
+	helper, err := CreateSchemaHelper(sch)
+	require.NoError(t, err)
+
+	aliased, err := helper.WithFieldAliases(map[string]string{"id": "field_int64", "meta": "field_json"})
+	require.NoError(t, err)
+	f, err := aliased.GetFieldFromName("id")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), f.GetFieldID())
+	f, err = aliased.GetFieldFromNameDefaultJSON("meta")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(101), f.GetFieldID())
+	f, err = aliased.GetFieldFromName("field_int64")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), f.GetFieldID())
+
+	// the original helper is not modified.
+	_, err = helper.GetFieldFromName("id")
+	assert.Error(t, err)
+
+	_, err = helper.WithFieldAliases(map[string]string{"field_json": "field_int64"})
+	assert.Error(t, err)
+	_, err = helper.WithFieldAliases(map[string]string{"id": "not_exist"})
+	assert.Error(t, err)
+	_, err = helper.WithFieldAliases(map[string]string{"dynamic": "$meta"})
+	assert.Error(t, err)
+}
+
 func TestSchemaHelper_GetClusteringKeyField(t *testing.T) {
 	t.Run("with_clustering_key", func(t *testing.T) {
 		sch := &schemapb.CollectionSchema{